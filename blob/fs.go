@@ -0,0 +1,74 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// FS is a Store on the local filesystem, for development and single replica
+// deployments. The http server serves it under /images.
+type FS struct {
+	dir     string
+	baseURL string
+}
+
+func NewFS(dir, baseURL string) *FS {
+	return &FS{dir: dir, baseURL: baseURL}
+}
+
+// Put writes to a temporary file and renames it into place so readers never
+// see a partial blob.
+func (s *FS) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *FS) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *FS) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
+
+// FileSystem serves the stored blobs by key.
+func (s *FS) FileSystem() http.FileSystem {
+	return http.Dir(s.dir)
+}
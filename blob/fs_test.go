@@ -0,0 +1,31 @@
+package blob_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFS(t *testing.T) {
+	dir := t.TempDir()
+	s := blob.NewFS(dir, "http://localhost:8080/images/")
+	ctx := context.Background()
+
+	assert.NoError(t, s.Put(ctx, "drinks/1/a.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+	b, err := os.ReadFile(filepath.Join(dir, "drinks", "1", "a.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", string(b))
+	assert.Equal(t, "http://localhost:8080/images/drinks/1/a.jpg", s.URL("drinks/1/a.jpg"))
+
+	assert.NoError(t, s.Delete(ctx, "drinks/1/a.jpg"))
+	assert.NoError(t, s.Delete(ctx, "drinks/1/a.jpg"), "deleting a missing blob is not an error")
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "drinks/../../secret", "drinks//a.jpg"} {
+		assert.ErrorIs(t, s.Put(ctx, key, strings.NewReader(""), 0, ""), blob.ErrInvalidKey, key)
+	}
+}
@@ -0,0 +1,72 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 is a Store in a bucket on any S3-compatible service, such as MinIO. The
+// bucket must exist and allow public reads for the URLs to resolve.
+type S3 struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+// NewS3 connects to the bucket named by the path of rawURL, in the form
+// http(s)://access-key:secret-key@host:port/bucket. baseURL defaults to the
+// bucket's own URL and can point at a CDN instead.
+func NewS3(rawURL, baseURL string) (*S3, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	bucket := strings.Trim(u.Path, "/")
+	if bucket == "" {
+		return nil, errors.New("s3 url has no bucket")
+	}
+
+	secret, _ := u.User.Password()
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(u.User.Username(), secret, ""),
+		Secure: u.Scheme == "https",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if baseURL == "" {
+		baseURL = u.Scheme + "://" + u.Host + "/" + bucket
+	}
+	return &S3{client: client, bucket: bucket, baseURL: baseURL}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+		// keys change with the content, so clients can cache forever
+		CacheControl: "public, max-age=31536000, immutable",
+	})
+	return err
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
@@ -0,0 +1,38 @@
+// Package blob stores uploaded files, such as drink images, behind a Store so
+// they can live on local disk in development and in an S3-compatible bucket in
+// production.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store holds blobs under slash separated keys.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL is where clients can fetch the blob at key.
+	URL(key string) string
+}
+
+// validKey rejects keys that could escape a directory or bucket prefix.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
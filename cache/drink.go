@@ -0,0 +1,248 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/metrics"
+)
+
+const DefaultTTL = 10 * time.Minute
+
+// DrinkService caches catalog reads from the wrapped service and purges the
+// store whenever the catalog is written through it, or elsewhere if it
+// follows catalog events.
+type DrinkService struct {
+	drinkee.DrinkService
+
+	store Store
+	ttl   time.Duration
+	// writes is bumped on every write so a read that raced with a write does
+	// not store a result fetched before it.
+	writes atomic.Uint64
+}
+
+func NewDrinkService(next drinkee.DrinkService, store Store, ttl time.Duration) *DrinkService {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &DrinkService{DrinkService: next, store: store, ttl: ttl}
+}
+
+func (s *DrinkService) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	var drink *drinkee.Drink
+	err := s.cached(ctx, "drink:"+strconv.Itoa(id), &drink, func() (interface{}, error) {
+		return s.DrinkService.FindDrinkByID(ctx, id)
+	})
+	return drink, err
+}
+
+func (s *DrinkService) FindDrinkBySlug(ctx context.Context, slug string) (*drinkee.Drink, error) {
+	var drink *drinkee.Drink
+	err := s.cached(ctx, "drink-slug:"+slug, &drink, func() (interface{}, error) {
+		return s.DrinkService.FindDrinkBySlug(ctx, slug)
+	})
+	return drink, err
+}
+
+func (s *DrinkService) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	var drinks []*drinkee.Drink
+	err := s.cached(ctx, "drinks:"+filterKey(f), &drinks, func() (interface{}, error) {
+		return s.DrinkService.FindDrinks(ctx, f)
+	})
+	return drinks, err
+}
+
+func (s *DrinkService) FindIngredients(ctx context.Context) ([]*drinkee.Ingredient, error) {
+	var ingredients []*drinkee.Ingredient
+	err := s.cached(ctx, "ingredients", &ingredients, func() (interface{}, error) {
+		return s.DrinkService.FindIngredients(ctx)
+	})
+	return ingredients, err
+}
+
+func (s *DrinkService) GenerateDrinks(ctx context.Context, i []drinkee.Ingredient) ([]*drinkee.Drink, error) {
+	var drinks []*drinkee.Drink
+	err := s.cached(ctx, "generate:strict:"+ingredientSetKey(i), &drinks, func() (interface{}, error) {
+		return s.DrinkService.GenerateDrinks(ctx, i)
+	})
+	return drinks, err
+}
+
+func (s *DrinkService) GenerateNonStrictDrinks(ctx context.Context, i []drinkee.Ingredient, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
+	var drinks []*drinkee.NonStrictDrink
+	err := s.cached(ctx, "generate:nonstrict:"+ingredientSetKey(i)+":"+optionsKey(opts), &drinks, func() (interface{}, error) {
+		return s.DrinkService.GenerateNonStrictDrinks(ctx, i, opts)
+	})
+	return drinks, err
+}
+
+func (s *DrinkService) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	drink, err := s.DrinkService.CreateDrink(ctx, cd)
+	if err != nil {
+		return nil, err
+	}
+	s.Invalidate(ctx)
+	return drink, nil
+}
+
+// Invalidate drops every cached read. Wrappers adding further write methods
+// must call it after a successful write.
+func (s *DrinkService) Invalidate(ctx context.Context) {
+	s.writes.Add(1)
+	if err := s.store.Purge(ctx); err != nil {
+		logger.FromContext(ctx, logger.NewNop()).Error("error purging cache", logger.Err(err))
+	}
+}
+
+// Follow purges the store on every catalog event until ctx is done, so writes
+// made through other replicas don't stay cached until the TTL. A dropped
+// subscription may have missed events, so it purges then too.
+func (s *DrinkService) Follow(ctx context.Context, es drinkee.EventService) {
+	l := logger.FromContext(ctx, logger.NewNop())
+	for {
+		ch, err := es.SubscribeEvents(ctx, 0)
+		if err != nil {
+			l.Error("error following catalog for cache", logger.Err(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
+		for range ch {
+			s.Invalidate(ctx)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		s.Invalidate(ctx)
+	}
+}
+
+// cached decodes the entry at key into dst, or calls load and stores its
+// result. Store failures are logged and fall through to load so the cache can
+// never take the API down.
+func (s *DrinkService) cached(ctx context.Context, key string, dst interface{}, load func() (interface{}, error)) error {
+	l := logger.FromContext(ctx, logger.NewNop())
+
+	b, ok, err := s.store.Get(ctx, key)
+	if err != nil {
+		l.Warn("error reading cache", logger.F("key", key), logger.Err(err))
+	}
+	if ok {
+		if err := json.Unmarshal(b, dst); err == nil {
+			metrics.CacheRequests.WithLabelValues("hit").Inc()
+			return nil
+		}
+	}
+	metrics.CacheRequests.WithLabelValues("miss").Inc()
+
+	before := s.writes.Load()
+	v, err := load()
+	if err != nil {
+		return err
+	}
+
+	b, err = json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if s.writes.Load() == before {
+		if err := s.store.Set(ctx, key, b, s.ttl); err != nil {
+			l.Warn("error writing cache", logger.F("key", key), logger.Err(err))
+		}
+	}
+
+	return json.Unmarshal(b, dst)
+}
+
+// filterKey, ingredientSetKey and optionsKey query-escape every value, so
+// no value can pass for another field or a second entry.
+func filterKey(f drinkee.DrinkFilter) string {
+	v := url.Values{}
+	v.Set("limit", strconv.Itoa(f.Limit))
+	v.Set("skip", strconv.Itoa(f.Skip))
+	if f.ID != nil {
+		v.Set("id", strconv.Itoa(*f.ID))
+	}
+	if f.Name != nil {
+		v.Set("name", *f.Name)
+	}
+	if len(f.Ingredients) > 0 {
+		ingredients := append([]string(nil), f.Ingredients...)
+		sort.Strings(ingredients)
+		v["ingredient"] = ingredients
+	}
+	for name, value := range map[string]*string{
+		"glass": f.Glass, "method": f.Method, "ice": f.Ice,
+		"garnish": f.Garnish, "origin": f.Origin, "creator": f.Creator,
+	} {
+		if value != nil {
+			v.Set(name, *value)
+		}
+	}
+	if f.Year != nil {
+		v.Set("year", strconv.Itoa(*f.Year))
+	}
+	for name, value := range map[string]*float64{
+		"minAbv": f.MinABV, "maxAbv": f.MaxABV, "maxCalories": f.MaxCalories,
+	} {
+		if value != nil {
+			v.Set(name, strconv.FormatFloat(*value, 'g', -1, 64))
+		}
+	}
+	return v.Encode()
+}
+
+// ingredientSetKey is order independent so the same pantry hits the same
+// entry. Ingredients given by name rather than ID are keyed by name.
+func ingredientSetKey(ingredients []drinkee.Ingredient) string {
+	v := url.Values{}
+	for _, i := range ingredients {
+		if i.ID == 0 && i.Name != "" {
+			v.Add("name", i.Name)
+			continue
+		}
+		v.Add("id", strconv.Itoa(i.ID))
+	}
+	for key, values := range v {
+		v[key] = unique(values)
+	}
+	return v.Encode()
+}
+
+// unique sorts values and drops repeats.
+func unique(values []string) []string {
+	sort.Strings(values)
+
+	out := values[:0]
+	for _, value := range values {
+		if len(out) > 0 && value == out[len(out)-1] {
+			continue
+		}
+		out = append(out, value)
+	}
+	return out
+}
+
+func optionsKey(opts drinkee.GenerateOptions) string {
+	v := url.Values{}
+	v.Set("limit", strconv.Itoa(opts.Limit))
+	v.Set("skip", strconv.Itoa(opts.Skip))
+	v.Set("rank", opts.Rank)
+	v.Set("minCoverage", strconv.FormatFloat(opts.MinCoverage, 'g', -1, 64))
+	if opts.MaxMissing != nil {
+		v.Set("maxMissing", strconv.Itoa(*opts.MaxMissing))
+	}
+	return v.Encode()
+}
@@ -0,0 +1,148 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/cache"
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingDrinkService struct {
+	drinkee.DrinkService
+	calls  map[string]int
+	drinks []*drinkee.Drink
+}
+
+func (s *countingDrinkService) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	s.calls["FindDrinks"]++
+	return s.drinks, nil
+}
+
+func (s *countingDrinkService) GenerateDrinks(ctx context.Context, i []drinkee.Ingredient) ([]*drinkee.Drink, error) {
+	s.calls["GenerateDrinks"]++
+	return s.drinks, nil
+}
+
+func (s *countingDrinkService) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	s.calls["CreateDrink"]++
+	drink := &drinkee.Drink{ID: len(s.drinks) + 1, Name: cd.Name}
+	s.drinks = append(s.drinks, drink)
+	return drink, nil
+}
+
+func TestDrinkServiceCachesUntilWrite(t *testing.T) {
+	ctx := context.Background()
+	next := &countingDrinkService{
+		calls:  map[string]int{},
+		drinks: []*drinkee.Drink{{ID: 1, Name: "negroni"}},
+	}
+	s := cache.NewDrinkService(next, cache.NewLRU(10), time.Minute)
+
+	for i := 0; i < 3; i++ {
+		drinks, err := s.FindDrinks(ctx, drinkee.DrinkFilter{Limit: 100})
+		assert.NoError(t, err)
+		assert.Len(t, drinks, 1)
+	}
+	assert.Equal(t, 1, next.calls["FindDrinks"])
+
+	// ingredient sets are keyed regardless of order
+	s.GenerateDrinks(ctx, []drinkee.Ingredient{{ID: 2}, {ID: 1}})
+	s.GenerateDrinks(ctx, []drinkee.Ingredient{{ID: 1}, {ID: 2}})
+	assert.Equal(t, 1, next.calls["GenerateDrinks"])
+
+	created, err := s.CreateDrink(ctx, &drinkee.CreateDrink{Name: "boulevardier"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created.ID)
+
+	drinks, err := s.FindDrinks(ctx, drinkee.DrinkFilter{Limit: 100})
+	assert.NoError(t, err)
+	assert.Len(t, drinks, 2)
+	assert.Equal(t, 2, next.calls["FindDrinks"])
+}
+
+func TestDrinkServiceKeysEveryFilterField(t *testing.T) {
+	ctx := context.Background()
+	next := &countingDrinkService{calls: map[string]int{}}
+	s := cache.NewDrinkService(next, cache.NewLRU(10), time.Minute)
+
+	coupe, rocks, year := "coupe", "rocks", 1927
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Glass: &coupe})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Glass: &rocks})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Glass: &rocks, Year: &year})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Method: &rocks})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Glass: &rocks, Year: &year})
+	assert.Equal(t, 4, next.calls["FindDrinks"])
+}
+
+func TestDrinkServiceKeysDontCollide(t *testing.T) {
+	ctx := context.Background()
+	next := &countingDrinkService{calls: map[string]int{}}
+	s := cache.NewDrinkService(next, cache.NewLRU(10), time.Minute)
+
+	x, glassY, y := "x&glass=y", "x", "y"
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Name: &x})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Name: &glassY, Glass: &y})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Ingredients: []string{"gin,rum"}})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{Ingredients: []string{"gin", "rum"}})
+	assert.Equal(t, 4, next.calls["FindDrinks"])
+
+	s.GenerateDrinks(ctx, []drinkee.Ingredient{{Name: "gin,name:rum"}})
+	s.GenerateDrinks(ctx, []drinkee.Ingredient{{Name: "gin"}, {Name: "rum"}})
+	s.GenerateDrinks(ctx, []drinkee.Ingredient{{Name: "rum"}, {Name: "gin"}, {Name: "gin"}})
+	assert.Equal(t, 2, next.calls["GenerateDrinks"])
+}
+
+// subscriptions reports each subscription to the wrapped broker.
+type subscriptions struct {
+	*events.Broker
+	subscribed chan struct{}
+}
+
+func (s subscriptions) SubscribeEvents(ctx context.Context, lastEventID int64) (<-chan drinkee.Event, error) {
+	ch, err := s.Broker.SubscribeEvents(ctx, lastEventID)
+	s.subscribed <- struct{}{}
+	return ch, err
+}
+
+func TestDrinkServiceFollowsCatalogEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	next := &countingDrinkService{calls: map[string]int{}}
+	s := cache.NewDrinkService(next, cache.NewLRU(10), time.Minute)
+	es := subscriptions{Broker: events.NewBroker(10), subscribed: make(chan struct{}, 1)}
+	go s.Follow(ctx, es)
+	<-es.subscribed
+
+	s.FindDrinks(ctx, drinkee.DrinkFilter{})
+	s.FindDrinks(ctx, drinkee.DrinkFilter{})
+	assert.Equal(t, 1, next.calls["FindDrinks"])
+
+	// a drink created through another replica
+	es.Publish(drinkee.Event{ID: 1, Type: drinkee.EventDrinkCreated})
+	assert.Eventually(t, func() bool {
+		s.FindDrinks(ctx, drinkee.DrinkFilter{})
+		return next.calls["FindDrinks"] > 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	_, ok, _ := c.Get(ctx, "b")
+	assert.False(t, ok)
+	v, ok, _ := c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(v))
+	assert.Equal(t, 2, c.Len())
+}
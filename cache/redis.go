@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Store backed by any server speaking the Redis protocol, so all
+// replicas share one cache. Keys are namespaced by a generation counter and
+// Purge bumps the counter, which invalidates every replica at once without
+// scanning; stale generations age out through their TTL.
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedis(url, prefix string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Redis{client: redis.NewClient(opts), prefix: prefix}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	gen, err := r.generation(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := r.client.Get(ctx, r.key(gen, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return b, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	gen, err := r.generation(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.key(gen, key), value, ttl).Err()
+}
+
+func (r *Redis) Purge(ctx context.Context) error {
+	return r.client.Incr(ctx, r.prefix+":generation").Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+func (r *Redis) generation(ctx context.Context) (int64, error) {
+	gen, err := r.client.Get(ctx, r.prefix+":generation").Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return gen, err
+}
+
+func (r *Redis) key(gen int64, key string) string {
+	return r.prefix + ":" + strconv.FormatInt(gen, 10) + ":" + key
+}
@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store is a byte cache that can drop everything it holds in one call.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Purge invalidates every entry, it is called after catalog writes.
+	Purge(ctx context.Context) error
+}
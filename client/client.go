@@ -0,0 +1,323 @@
+// Package client is a Go SDK for the drinkee HTTP API. Client implements
+// drinkee.DrinkService, so code written against the interface can run against
+// the postgres implementation in-process or a remote server unchanged.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 200 * time.Millisecond
+	DefaultPageSize   = 100
+
+	maxBackoff = 5 * time.Second
+)
+
+var _ drinkee.DrinkService = (*Client)(nil)
+
+type Client struct {
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+	// MaxRetries is how many times an idempotent request is retried after a
+	// network error, 429 or 5xx response.
+	MaxRetries int
+	// Backoff is the base delay, doubled on each retry with jitter.
+	Backoff time.Duration
+	// Timeout bounds each attempt on top of any deadline on the context.
+	Timeout time.Duration
+}
+
+// NewClient returns a client for the server at baseURL, e.g. http://localhost:8080.
+func NewClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q", baseURL)
+	}
+
+	return &Client{
+		BaseURL:    u,
+		HTTPClient: &http.Client{},
+		MaxRetries: DefaultMaxRetries,
+		Backoff:    DefaultBackoff,
+		Timeout:    DefaultTimeout,
+	}, nil
+}
+
+// Error is returned for any non-2xx response.
+type Error struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("drinkee: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+func IsBadRequest(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusBadRequest
+}
+
+// IsConflict reports whether a create collided with an existing record.
+func IsConflict(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
+
+func (c *Client) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	var drink drinkee.Drink
+	if err := c.do(ctx, http.MethodGet, "/api/v1/drinks/"+strconv.Itoa(id), nil, nil, &drink, true); err != nil {
+		return nil, err
+	}
+	return &drink, nil
+}
+
+func (c *Client) FindDrinkBySlug(ctx context.Context, slug string) (*drinkee.Drink, error) {
+	var drink drinkee.Drink
+	if err := c.do(ctx, http.MethodGet, "/api/v1/drinks/by-slug/"+slug, nil, nil, &drink, true); err != nil {
+		return nil, err
+	}
+	return &drink, nil
+}
+
+func (c *Client) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	q := url.Values{}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Skip > 0 {
+		q.Set("skip", strconv.Itoa(f.Skip))
+	}
+	if f.Name != nil {
+		q.Set("name", *f.Name)
+	}
+	if f.ID != nil {
+		q.Set("id", strconv.Itoa(*f.ID))
+	}
+	if len(f.Ingredients) > 0 {
+		q.Set("ingredients", strings.Join(f.Ingredients, ","))
+	}
+	for name, value := range map[string]*string{
+		"glass":   f.Glass,
+		"method":  f.Method,
+		"ice":     f.Ice,
+		"garnish": f.Garnish,
+		"origin":  f.Origin,
+		"creator": f.Creator,
+	} {
+		if value != nil {
+			q.Set(name, *value)
+		}
+	}
+	if f.Year != nil {
+		q.Set("year", strconv.Itoa(*f.Year))
+	}
+	for name, value := range map[string]*float64{
+		"minAbv":      f.MinABV,
+		"maxAbv":      f.MaxABV,
+		"maxCalories": f.MaxCalories,
+	} {
+		if value != nil {
+			q.Set(name, strconv.FormatFloat(*value, 'g', -1, 64))
+		}
+	}
+
+	var drinks []*drinkee.Drink
+	if err := c.do(ctx, http.MethodGet, "/api/v1/drinks", q, nil, &drinks, true); err != nil {
+		return nil, err
+	}
+	return drinks, nil
+}
+
+// CreateDrink is not retried, a timed out attempt may still have been applied.
+// It uses the v2 endpoint, which is the one that returns the created drink.
+func (c *Client) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	var resp struct {
+		Data *drinkee.Drink `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v2/drinks", nil, cd, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *Client) GenerateDrinks(ctx context.Context, i []drinkee.Ingredient) ([]*drinkee.Drink, error) {
+	var drinks []*drinkee.Drink
+	q := url.Values{"strict": {"true"}}
+	body := ingredientListRequest{Ingredients: i}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/generateDrinks", q, body, &drinks, true); err != nil {
+		return nil, err
+	}
+	return drinks, nil
+}
+
+// GenerateNonStrictDrinks always sends a limit, since the server defaults to
+// 100 where a zero Limit means every match.
+func (c *Client) GenerateNonStrictDrinks(ctx context.Context, i []drinkee.Ingredient, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
+	q := url.Values{"limit": {strconv.Itoa(opts.Limit)}}
+	if opts.Skip > 0 {
+		q.Set("skip", strconv.Itoa(opts.Skip))
+	}
+	if opts.Rank != "" {
+		q.Set("rank", opts.Rank)
+	}
+	if opts.MaxMissing != nil {
+		q.Set("maxMissing", strconv.Itoa(*opts.MaxMissing))
+	}
+	if opts.MinCoverage > 0 {
+		q.Set("minCoverage", strconv.FormatFloat(opts.MinCoverage, 'g', -1, 64))
+	}
+
+	var drinks []*drinkee.NonStrictDrink
+	body := ingredientListRequest{Ingredients: i}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/generateDrinks", q, body, &drinks, true); err != nil {
+		return nil, err
+	}
+	return drinks, nil
+}
+
+func (c *Client) FindIngredients(ctx context.Context) ([]*drinkee.Ingredient, error) {
+	var ingredients []*drinkee.Ingredient
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ingredients", nil, nil, &ingredients, true); err != nil {
+		return nil, err
+	}
+	return ingredients, nil
+}
+
+// mirrors http.IngredientListRequest without importing the server package
+type ingredientListRequest struct {
+	Ingredients []drinkee.Ingredient `json:"ingredients"`
+}
+
+// do sends one API request, retrying idempotent ones, and decodes a JSON
+// response into out when out is non-nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}, idempotent bool) error {
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	u := *c.BaseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	retries := 0
+	if idempotent {
+		retries = c.MaxRetries
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = c.attempt(ctx, method, u.String(), body, out)
+		if err == nil || !retry || attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(c.backoff(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, url string, body []byte, out interface{}) (retry bool, err error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if id := drinkee.RequestIDFromContext(ctx); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+	if actor := drinkee.ActorFromContext(ctx); actor != "" {
+		req.Header.Set("X-Actor", actor)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, &Error{
+			StatusCode: resp.StatusCode,
+			Message:    errorMessage(msg),
+			RequestID:  resp.Header.Get("X-Request-ID"),
+		}
+	}
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("drinkee: decoding response: %w", err)
+	}
+	return false, nil
+}
+
+// errorMessage extracts the message from a v2 error envelope, falling back to
+// the plain text body v1 endpoints send.
+func errorMessage(body []byte) string {
+	var envelope struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Errors) > 0 {
+		return envelope.Errors[0].Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.Backoff << attempt
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	// full jitter keeps many clients from retrying in lockstep
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
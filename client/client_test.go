@@ -0,0 +1,159 @@
+package client_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/client"
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type memDrinkService struct {
+	drinkee.DrinkService
+	drinks []*drinkee.Drink
+}
+
+func (s *memDrinkService) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	for _, d := range s.drinks {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *memDrinkService) FindDrinkBySlug(ctx context.Context, slug string) (*drinkee.Drink, error) {
+	for _, d := range s.drinks {
+		if d.Slug == slug {
+			return d, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *memDrinkService) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	if f.Skip >= len(s.drinks) {
+		return []*drinkee.Drink{}, nil
+	}
+	end := f.Skip + f.Limit
+	if end > len(s.drinks) {
+		end = len(s.drinks)
+	}
+	return s.drinks[f.Skip:end], nil
+}
+
+func (s *memDrinkService) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	for _, d := range s.drinks {
+		if d.Name == cd.Name {
+			return nil, &drinkee.ConflictError{Resource: "drink", ID: d.ID, Name: d.Name, Slug: d.Slug}
+		}
+	}
+	d := &drinkee.Drink{ID: len(s.drinks) + 1, Name: cd.Name, DisplayName: cd.DisplayName, Slug: drinkee.Slugify(cd.DisplayName), Instructions: cd.Instructions}
+	s.drinks = append(s.drinks, d)
+	return d, nil
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *client.Client {
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	c, err := client.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Backoff = time.Millisecond
+	return c
+}
+
+func TestClientAgainstServer(t *testing.T) {
+	svc := &memDrinkService{}
+	for i := 1; i <= 5; i++ {
+		svc.drinks = append(svc.drinks, &drinkee.Drink{ID: i, Name: "drink" + strconv.Itoa(i), DisplayName: "Drink", Slug: "drink-" + strconv.Itoa(i)})
+	}
+
+	s := drinkeehttp.NewServer()
+	s.DrinkService = svc
+	c := newTestClient(t, s.Router)
+	ctx := context.Background()
+
+	drink, err := c.FindDrinkByID(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, drink.ID)
+
+	_, err = c.FindDrinkByID(ctx, 42)
+	assert.True(t, client.IsNotFound(err), "expected not found, got %v", err)
+
+	drink, err = c.FindDrinkBySlug(ctx, "drink-4")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, drink.ID)
+
+	_, err = c.FindDrinkBySlug(ctx, "drink-42")
+	assert.True(t, client.IsNotFound(err), "expected not found, got %v", err)
+
+	var pages int
+	err = c.EachDrinkPage(ctx, drinkee.DrinkFilter{}, 2, func(page []*drinkee.Drink) error {
+		pages++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pages)
+
+	all, err := c.AllDrinks(ctx, drinkee.DrinkFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	created, err := c.CreateDrink(ctx, &drinkee.CreateDrink{
+		Name:             "negroni",
+		DisplayName:      "Negroni",
+		Instructions:     "Stir with ice.",
+		DrinkIngredients: []drinkee.DrinkIngredient{{Name: "gin", Measurement: "1 oz"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, created.ID)
+	assert.Equal(t, "Negroni", created.DisplayName)
+	assert.Equal(t, "negroni", created.Slug)
+
+	_, err = c.CreateDrink(ctx, &drinkee.CreateDrink{
+		Name:             "negroni",
+		DisplayName:      "Negroni",
+		Instructions:     "Stir with ice.",
+		DrinkIngredients: []drinkee.DrinkIngredient{{Name: "gin", Measurement: "1 oz"}},
+	})
+	assert.True(t, client.IsConflict(err), "expected conflict, got %v", err)
+
+	_, err = c.CreateDrink(ctx, &drinkee.CreateDrink{Name: "negroni"})
+	assert.True(t, client.IsBadRequest(err), "expected bad request, got %v", err)
+	assert.Contains(t, err.Error(), "invalid drink")
+}
+
+func TestClientRetriesIdempotentRequests(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "vodka", "displayName": "Vodka"}]`))
+	}))
+
+	ingredients, err := c.FindIngredients(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, ingredients, 1)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	_, err = c.CreateDrink(context.Background(), &drinkee.CreateDrink{Name: "negroni"})
+	var apiErr *client.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "try again", apiErr.Message)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+// EachDrinkPage walks the drinks matching f page by page using limit/skip,
+// starting at f.Skip. pageSize defaults to DefaultPageSize. Returning an
+// error from fn stops the walk and returns that error.
+func (c *Client) EachDrinkPage(ctx context.Context, f drinkee.DrinkFilter, pageSize int, fn func(page []*drinkee.Drink) error) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	f.Limit = pageSize
+	for {
+		page, err := c.FindDrinks(ctx, f)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+
+		f.Skip += len(page)
+	}
+}
+
+// AllDrinks collects every drink matching f, ignoring f.Limit.
+func (c *Client) AllDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	var drinks []*drinkee.Drink
+	err := c.EachDrinkPage(ctx, f, DefaultPageSize, func(page []*drinkee.Drink) error {
+		drinks = append(drinks, page...)
+		return nil
+	})
+	return drinks, err
+}
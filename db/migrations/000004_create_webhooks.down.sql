@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS record_catalog_event ON ingredients;
+DROP TRIGGER IF EXISTS record_catalog_event ON drinks;
+DROP FUNCTION IF EXISTS record_catalog_event();
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS catalog_events;
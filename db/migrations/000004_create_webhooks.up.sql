@@ -0,0 +1,97 @@
+CREATE TABLE IF NOT EXISTS catalog_events(
+    id bigserial PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    data jsonb NOT NULL,
+    created_at timestamp NOT NULL DEFAULT current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions(
+    id serial PRIMARY KEY,
+    url text NOT NULL,
+    secret text NOT NULL,
+    -- an empty list subscribes to every event type
+    events text[] NOT NULL DEFAULT '{}',
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp NOT NULL DEFAULT current_timestamp,
+    updated_at timestamp NOT NULL DEFAULT current_timestamp
+);
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON webhook_subscriptions
+FOR EACH ROW
+EXECUTE PROCEDURE set_updated_at();
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+    id bigserial PRIMARY KEY,
+    subscription_id int NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id bigint NOT NULL REFERENCES catalog_events(id) ON DELETE CASCADE,
+    -- pending, succeeded or failed
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts int NOT NULL DEFAULT 0,
+    next_attempt_at timestamp NOT NULL DEFAULT current_timestamp,
+    created_at timestamp NOT NULL DEFAULT current_timestamp,
+    updated_at timestamp NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, id);
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON webhook_deliveries
+FOR EACH ROW
+EXECUTE PROCEDURE set_updated_at();
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts(
+    id bigserial PRIMARY KEY,
+    delivery_id bigint NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    status_code int,
+    error text,
+    duration_ms int NOT NULL,
+    created_at timestamp NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX webhook_delivery_attempts_delivery_idx ON webhook_delivery_attempts (delivery_id);
+
+-- record_catalog_event turns every change to a catalog table into an event
+-- and queues a delivery for each subscription that wants it, in the same
+-- transaction as the change. TG_ARGV[0] is the resource name.
+CREATE OR REPLACE FUNCTION record_catalog_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    rec record;
+    new_event_type text;
+    new_event_id bigint;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    new_event_type := TG_ARGV[0] || '.' || CASE TG_OP
+        WHEN 'INSERT' THEN 'created'
+        WHEN 'UPDATE' THEN 'updated'
+        ELSE 'deleted'
+    END;
+
+    INSERT INTO catalog_events (type, data)
+    VALUES (new_event_type, jsonb_build_object('id', rec.id, 'name', rec.name, 'displayName', rec.display_name))
+    RETURNING id INTO new_event_id;
+
+    INSERT INTO webhook_deliveries (subscription_id, event_id)
+    SELECT id, new_event_id FROM webhook_subscriptions
+    WHERE active AND (cardinality(events) = 0 OR new_event_type = ANY(events));
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_catalog_event
+AFTER INSERT OR UPDATE OR DELETE ON drinks
+FOR EACH ROW
+EXECUTE PROCEDURE record_catalog_event('drink');
+
+CREATE TRIGGER record_catalog_event
+AFTER INSERT OR UPDATE OR DELETE ON ingredients
+FOR EACH ROW
+EXECUTE PROCEDURE record_catalog_event('ingredient');
@@ -0,0 +1,38 @@
+DROP TRIGGER IF EXISTS record_catalog_event_delete ON drink_ingredients;
+DROP TRIGGER IF EXISTS record_catalog_event_update ON drink_ingredients;
+DROP TRIGGER IF EXISTS record_catalog_event_insert ON drink_ingredients;
+DROP FUNCTION IF EXISTS record_drink_ingredients_event();
+
+-- back to the 000004 version, which doesn't notify
+CREATE OR REPLACE FUNCTION record_catalog_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    rec record;
+    new_event_type text;
+    new_event_id bigint;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    new_event_type := TG_ARGV[0] || '.' || CASE TG_OP
+        WHEN 'INSERT' THEN 'created'
+        WHEN 'UPDATE' THEN 'updated'
+        ELSE 'deleted'
+    END;
+
+    INSERT INTO catalog_events (type, data)
+    VALUES (new_event_type, jsonb_build_object('id', rec.id, 'name', rec.name, 'displayName', rec.display_name))
+    RETURNING id INTO new_event_id;
+
+    INSERT INTO webhook_deliveries (subscription_id, event_id)
+    SELECT id, new_event_id FROM webhook_subscriptions
+    WHERE active AND (cardinality(events) = 0 OR new_event_type = ANY(events));
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS emit_catalog_event(text, jsonb);
@@ -0,0 +1,91 @@
+-- emit_catalog_event records an event, queues its webhook deliveries and
+-- announces it on the catalog_events channel. Notifications reach the
+-- listener on every replica in commit order, but event IDs are taken at
+-- insert, so a later notification can carry a lower ID.
+CREATE OR REPLACE FUNCTION emit_catalog_event(event_type text, event_data jsonb)
+RETURNS void AS $$
+DECLARE
+    new_event_id bigint;
+BEGIN
+    INSERT INTO catalog_events (type, data)
+    VALUES (event_type, event_data)
+    RETURNING id INTO new_event_id;
+
+    INSERT INTO webhook_deliveries (subscription_id, event_id)
+    SELECT id, new_event_id FROM webhook_subscriptions
+    WHERE active AND (cardinality(events) = 0 OR event_type = ANY(events));
+
+    PERFORM pg_notify('catalog_events', jsonb_build_object(
+        'id', new_event_id,
+        'type', event_type,
+        'data', event_data,
+        'createdAt', current_timestamp
+    )::text);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_catalog_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    rec record;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM emit_catalog_event(
+        TG_ARGV[0] || '.' || CASE TG_OP
+            WHEN 'INSERT' THEN 'created'
+            WHEN 'UPDATE' THEN 'updated'
+            ELSE 'deleted'
+        END,
+        jsonb_build_object('id', rec.id, 'name', rec.name, 'displayName', rec.display_name)
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- record_drink_ingredients_event reports a change to a drink's ingredients as
+-- one drink.updated per drink, however many rows the statement touched. Drinks
+-- inserted in the same transaction are skipped, drink.created covers them.
+-- The triggers below name their transition table "changed".
+CREATE OR REPLACE FUNCTION record_drink_ingredients_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    d record;
+BEGIN
+    FOR d IN
+        SELECT DISTINCT drinks.id, drinks.name, drinks.display_name
+        FROM changed
+        JOIN drinks ON drinks.id = changed.drink_id
+        -- current_timestamp is the transaction's start time
+        WHERE drinks.created_at <> current_timestamp::timestamp
+        ORDER BY drinks.id
+    LOOP
+        PERFORM emit_catalog_event('drink.updated', jsonb_build_object('id', d.id, 'name', d.name, 'displayName', d.display_name));
+    END LOOP;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_catalog_event_insert
+AFTER INSERT ON drink_ingredients
+REFERENCING NEW TABLE AS changed
+FOR EACH STATEMENT
+EXECUTE PROCEDURE record_drink_ingredients_event();
+
+CREATE TRIGGER record_catalog_event_update
+AFTER UPDATE ON drink_ingredients
+REFERENCING NEW TABLE AS changed
+FOR EACH STATEMENT
+EXECUTE PROCEDURE record_drink_ingredients_event();
+
+CREATE TRIGGER record_catalog_event_delete
+AFTER DELETE ON drink_ingredients
+REFERENCING OLD TABLE AS changed
+FOR EACH STATEMENT
+EXECUTE PROCEDURE record_drink_ingredients_event();
@@ -0,0 +1 @@
+ALTER TABLE drinks DROP COLUMN IF EXISTS image;
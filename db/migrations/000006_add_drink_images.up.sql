@@ -0,0 +1,2 @@
+-- image holds the drink's image and thumbnail URLs, see drinkee.Image.
+ALTER TABLE drinks ADD COLUMN IF NOT EXISTS image jsonb;
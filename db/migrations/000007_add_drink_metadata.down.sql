@@ -0,0 +1,11 @@
+ALTER TABLE drinks
+    DROP COLUMN IF EXISTS glass_id,
+    DROP COLUMN IF EXISTS method,
+    DROP COLUMN IF EXISTS ice_id,
+    DROP COLUMN IF EXISTS garnish,
+    DROP COLUMN IF EXISTS origin,
+    DROP COLUMN IF EXISTS creator,
+    DROP COLUMN IF EXISTS year;
+
+DROP TABLE IF EXISTS ice_styles;
+DROP TABLE IF EXISTS glasses;
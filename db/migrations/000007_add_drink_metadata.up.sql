@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS glasses(
+    id serial PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL
+);
+
+INSERT INTO glasses (name, display_name) VALUES
+    ('cocktail', 'Cocktail'),
+    ('coupe', 'Coupe'),
+    ('nick and nora', 'Nick and Nora'),
+    ('rocks', 'Rocks'),
+    ('double rocks', 'Double Rocks'),
+    ('highball', 'Highball'),
+    ('collins', 'Collins'),
+    ('copper mug', 'Copper Mug'),
+    ('julep cup', 'Julep Cup'),
+    ('hurricane', 'Hurricane'),
+    ('margarita', 'Margarita'),
+    ('tiki mug', 'Tiki Mug'),
+    ('wine', 'Wine'),
+    ('flute', 'Flute'),
+    ('irish coffee', 'Irish Coffee'),
+    ('shot', 'Shot')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS ice_styles(
+    id serial PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL
+);
+
+INSERT INTO ice_styles (name, display_name) VALUES
+    ('none', 'None'),
+    ('cubed', 'Cubed'),
+    ('large cube', 'Large Cube'),
+    ('sphere', 'Sphere'),
+    ('crushed', 'Crushed'),
+    ('pebble', 'Pebble')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE drinks
+    ADD COLUMN IF NOT EXISTS glass_id integer REFERENCES glasses (id),
+    -- a fixed set, see drinkee.Methods
+    ADD COLUMN IF NOT EXISTS method VARCHAR(16) CHECK (method IN ('shaken', 'stirred', 'built', 'blended', 'thrown')),
+    ADD COLUMN IF NOT EXISTS ice_id integer REFERENCES ice_styles (id),
+    ADD COLUMN IF NOT EXISTS garnish text,
+    ADD COLUMN IF NOT EXISTS origin text,
+    ADD COLUMN IF NOT EXISTS creator text,
+    ADD COLUMN IF NOT EXISTS year smallint;
+
+CREATE INDEX IF NOT EXISTS drinks_glass_id_idx ON drinks (glass_id);
+CREATE INDEX IF NOT EXISTS drinks_ice_id_idx ON drinks (ice_id);
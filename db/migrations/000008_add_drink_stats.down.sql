@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS drinks_abv_idx;
+
+ALTER TABLE drinks DROP COLUMN IF EXISTS stats;
+
+ALTER TYPE ingredient_data
+    DROP ATTRIBUTE IF EXISTS ml,
+    DROP ATTRIBUTE IF EXISTS unit,
+    DROP ATTRIBUTE IF EXISTS amount;
+
+ALTER TABLE drink_ingredients
+    DROP COLUMN IF EXISTS ml,
+    DROP COLUMN IF EXISTS unit,
+    DROP COLUMN IF EXISTS amount;
+
+ALTER TABLE ingredients
+    DROP COLUMN IF EXISTS unit_ml,
+    DROP COLUMN IF EXISTS calories_per_100ml,
+    DROP COLUMN IF EXISTS sugar_per_100ml,
+    DROP COLUMN IF EXISTS abv;
@@ -0,0 +1,54 @@
+-- Strength and sugar are per ingredient, calories_per_100ml overrides the
+-- figure worked out from them. unit_ml is the volume of one of a counted
+-- ingredient, such as an egg white.
+ALTER TABLE ingredients
+    ADD COLUMN IF NOT EXISTS abv numeric(5, 2) NOT NULL DEFAULT 0 CHECK (abv BETWEEN 0 AND 100),
+    ADD COLUMN IF NOT EXISTS sugar_per_100ml numeric(6, 2) NOT NULL DEFAULT 0 CHECK (sugar_per_100ml >= 0),
+    ADD COLUMN IF NOT EXISTS calories_per_100ml numeric(6, 2) CHECK (calories_per_100ml >= 0),
+    ADD COLUMN IF NOT EXISTS unit_ml numeric(8, 2) NOT NULL DEFAULT 0 CHECK (unit_ml >= 0);
+
+UPDATE ingredients SET abv = 40 WHERE name IN (
+    'vodka', 'gin', 'rum', 'light rum', 'white rum', 'dark rum', 'gold rum', 'spiced rum',
+    'tequila', 'blanco tequila', 'reposado tequila', 'mezcal', 'whiskey', 'whisky',
+    'bourbon', 'rye', 'rye whiskey', 'scotch', 'irish whiskey', 'brandy', 'cognac', 'pisco', 'cachaca'
+);
+UPDATE ingredients SET abv = 45 WHERE name IN ('absinthe');
+UPDATE ingredients SET abv = 44.7, sugar_per_100ml = 4 WHERE name IN ('angostura bitters', 'bitters', 'orange bitters', 'peychaud''s bitters');
+UPDATE ingredients SET abv = 40, sugar_per_100ml = 25 WHERE name IN ('triple sec', 'cointreau', 'grand marnier', 'orange liqueur', 'curacao', 'blue curacao');
+UPDATE ingredients SET abv = 32, sugar_per_100ml = 35 WHERE name IN ('maraschino liqueur', 'amaretto', 'chartreuse', 'green chartreuse', 'yellow chartreuse', 'benedictine', 'drambuie');
+UPDATE ingredients SET abv = 20, sugar_per_100ml = 40 WHERE name IN ('coffee liqueur', 'kahlua', 'creme de cacao', 'creme de menthe', 'creme de cassis', 'irish cream', 'baileys');
+UPDATE ingredients SET abv = 25, sugar_per_100ml = 24 WHERE name IN ('campari');
+UPDATE ingredients SET abv = 11, sugar_per_100ml = 20 WHERE name IN ('aperol');
+UPDATE ingredients SET abv = 18, sugar_per_100ml = 3 WHERE name IN ('dry vermouth');
+UPDATE ingredients SET abv = 16, sugar_per_100ml = 15 WHERE name IN ('sweet vermouth', 'vermouth', 'lillet', 'lillet blanc');
+UPDATE ingredients SET abv = 12, sugar_per_100ml = 1 WHERE name IN ('champagne', 'prosecco', 'sparkling wine', 'white wine', 'red wine');
+UPDATE ingredients SET abv = 5, sugar_per_100ml = 0, calories_per_100ml = 43 WHERE name IN ('beer', 'lager');
+UPDATE ingredients SET sugar_per_100ml = 63 WHERE name IN ('simple syrup', 'sugar syrup', 'syrup');
+UPDATE ingredients SET sugar_per_100ml = 65 WHERE name IN ('grenadine', 'orgeat', 'honey syrup');
+UPDATE ingredients SET sugar_per_100ml = 2.5 WHERE name IN ('lime juice', 'lemon juice');
+UPDATE ingredients SET sugar_per_100ml = 9 WHERE name IN ('orange juice', 'grapefruit juice');
+UPDATE ingredients SET sugar_per_100ml = 10 WHERE name IN ('pineapple juice', 'cranberry juice');
+UPDATE ingredients SET sugar_per_100ml = 10.6 WHERE name IN ('cola', 'ginger beer', 'ginger ale');
+UPDATE ingredients SET sugar_per_100ml = 8.6 WHERE name IN ('tonic water', 'tonic');
+UPDATE ingredients SET calories_per_100ml = 0 WHERE name IN ('soda water', 'club soda', 'water', 'ice');
+UPDATE ingredients SET calories_per_100ml = 340, sugar_per_100ml = 3 WHERE name IN ('heavy cream', 'cream');
+UPDATE ingredients SET calories_per_100ml = 52, unit_ml = 30 WHERE name IN ('egg white');
+UPDATE ingredients SET unit_ml = 4, sugar_per_100ml = 100 WHERE name IN ('sugar cube');
+
+-- amount, unit and ml are measurement parsed by the app, see
+-- drinkee.Quantity. They are null when it has no amount, like "top up".
+ALTER TABLE drink_ingredients
+    ADD COLUMN IF NOT EXISTS amount numeric,
+    ADD COLUMN IF NOT EXISTS unit VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS ml numeric;
+
+ALTER TYPE ingredient_data
+    ADD ATTRIBUTE amount numeric,
+    ADD ATTRIBUTE unit VARCHAR(16),
+    ADD ATTRIBUTE ml numeric;
+
+-- stats holds the app's estimates, see drinkee.DrinkStats. Existing drinks
+-- get theirs when the app next starts.
+ALTER TABLE drinks ADD COLUMN IF NOT EXISTS stats jsonb;
+
+CREATE INDEX IF NOT EXISTS drinks_abv_idx ON drinks (((stats->>'abv')::numeric));
@@ -0,0 +1,33 @@
+-- category groups shopping lists, see drinkee.IngredientCategories.
+ALTER TABLE ingredients
+    ADD COLUMN IF NOT EXISTS category VARCHAR(16) NOT NULL DEFAULT 'other'
+    CHECK (category IN ('spirit', 'liqueur', 'wine', 'beer', 'bitters', 'syrup', 'juice', 'mixer', 'dairy', 'produce', 'other'));
+
+UPDATE ingredients SET category = 'spirit' WHERE name IN (
+    'vodka', 'gin', 'rum', 'light rum', 'white rum', 'dark rum', 'gold rum', 'spiced rum',
+    'tequila', 'blanco tequila', 'reposado tequila', 'mezcal', 'whiskey', 'whisky',
+    'bourbon', 'rye', 'rye whiskey', 'scotch', 'irish whiskey', 'brandy', 'cognac', 'pisco', 'cachaca', 'absinthe'
+);
+UPDATE ingredients SET category = 'liqueur' WHERE name IN (
+    'triple sec', 'cointreau', 'grand marnier', 'orange liqueur', 'curacao', 'blue curacao',
+    'maraschino liqueur', 'amaretto', 'chartreuse', 'green chartreuse', 'yellow chartreuse', 'benedictine', 'drambuie',
+    'coffee liqueur', 'kahlua', 'creme de cacao', 'creme de menthe', 'creme de cassis', 'irish cream', 'baileys',
+    'campari', 'aperol'
+);
+UPDATE ingredients SET category = 'wine' WHERE name IN (
+    'dry vermouth', 'sweet vermouth', 'vermouth', 'lillet', 'lillet blanc',
+    'champagne', 'prosecco', 'sparkling wine', 'white wine', 'red wine'
+);
+UPDATE ingredients SET category = 'beer' WHERE name IN ('beer', 'lager');
+UPDATE ingredients SET category = 'bitters' WHERE name IN ('angostura bitters', 'bitters', 'orange bitters', 'peychaud''s bitters');
+UPDATE ingredients SET category = 'syrup' WHERE name IN ('simple syrup', 'sugar syrup', 'syrup', 'grenadine', 'orgeat', 'honey syrup');
+UPDATE ingredients SET category = 'juice' WHERE name IN (
+    'lime juice', 'lemon juice', 'orange juice', 'grapefruit juice', 'pineapple juice', 'cranberry juice'
+);
+UPDATE ingredients SET category = 'mixer' WHERE name IN (
+    'cola', 'ginger beer', 'ginger ale', 'tonic water', 'tonic', 'soda water', 'club soda', 'water'
+);
+UPDATE ingredients SET category = 'dairy' WHERE name IN ('heavy cream', 'cream', 'milk', 'egg white', 'egg', 'egg yolk');
+UPDATE ingredients SET category = 'produce' WHERE name IN (
+    'lime', 'lemon', 'orange', 'grapefruit', 'mint', 'basil', 'cucumber', 'olive', 'cherry', 'maraschino cherry', 'celery'
+);
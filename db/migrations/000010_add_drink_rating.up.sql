@@ -0,0 +1,3 @@
+-- an editorial rating out of 5, used to rank generated drinks
+ALTER TABLE drinks
+    ADD COLUMN IF NOT EXISTS rating numeric(2, 1) CHECK (rating BETWEEN 0 AND 5);
@@ -0,0 +1,7 @@
+ALTER TYPE ingredient_data
+    DROP ATTRIBUTE IF EXISTS role,
+    DROP ATTRIBUTE IF EXISTS optional;
+
+ALTER TABLE drink_ingredients
+    DROP COLUMN IF EXISTS role,
+    DROP COLUMN IF EXISTS optional;
@@ -0,0 +1,16 @@
+-- optional ingredients don't keep a drink out of strict generation and count
+-- for less in non-strict generation. role is one of drinkee.Roles; garnishes
+-- are always optional.
+ALTER TABLE drink_ingredients
+    ADD COLUMN IF NOT EXISTS optional boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS role VARCHAR(16) CHECK (role IN ('base', 'modifier', 'garnish'));
+
+ALTER TYPE ingredient_data
+    ADD ATTRIBUTE optional boolean,
+    ADD ATTRIBUTE role VARCHAR(16);
+
+UPDATE drink_ingredients di SET role = 'garnish', optional = true
+FROM ingredients i
+WHERE i.id = di.ingredient_id
+  AND (di.measurement ~* 'garnish'
+    OR (i.category = 'produce' AND di.measurement ~* '(twist|wedge|wheel|slice|peel|sprig|zest|rim)'));
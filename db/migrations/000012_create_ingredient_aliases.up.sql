@@ -0,0 +1,26 @@
+-- aliases are other names for an ingredient, like brands, matched ignoring
+-- case. An ingredient's own name always wins over an alias, and an alias of
+-- more than one ingredient is ambiguous, so lookups by it are refused.
+CREATE TABLE IF NOT EXISTS ingredient_aliases(
+    id serial PRIMARY KEY,
+    alias VARCHAR(128) NOT NULL,
+    ingredient_id int NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT current_timestamp
+);
+
+CREATE UNIQUE INDEX ingredient_aliases_alias_idx ON ingredient_aliases (lower(alias), ingredient_id);
+
+INSERT INTO ingredient_aliases (alias, ingredient_id)
+SELECT a.alias, i.id
+FROM (VALUES
+    ('oj', 'orange juice'),
+    ('cassis', 'creme de cassis'),
+    ('crème de cassis', 'creme de cassis'),
+    ('crème de cacao', 'creme de cacao'),
+    ('crème de menthe', 'creme de menthe'),
+    ('curaçao', 'curacao'),
+    ('cachaça', 'cachaca'),
+    ('bailey''s', 'baileys')
+) AS a(alias, name)
+JOIN ingredients i ON i.name = a.name
+ON CONFLICT DO NOTHING;
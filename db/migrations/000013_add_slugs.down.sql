@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS ingredients_slug_idx;
+DROP INDEX IF EXISTS ingredients_name_idx;
+DROP INDEX IF EXISTS drinks_slug_idx;
+DROP INDEX IF EXISTS drinks_name_idx;
+DROP TRIGGER IF EXISTS set_slug ON ingredients;
+DROP TRIGGER IF EXISTS set_slug ON drinks;
+ALTER TABLE ingredients DROP COLUMN IF EXISTS slug;
+ALTER TABLE drinks DROP COLUMN IF EXISTS slug;
+DROP FUNCTION IF EXISTS set_slug();
+DROP FUNCTION IF EXISTS slugify(text);
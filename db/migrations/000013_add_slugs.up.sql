@@ -0,0 +1,88 @@
+-- slugify makes a URL-safe name: lower case letters and digits in runs joined
+-- by hyphens, with accents folded and '&' spelled out. drinkee.Slugify agrees
+-- with it.
+CREATE OR REPLACE FUNCTION slugify(value text)
+RETURNS text AS $$
+    SELECT trim(BOTH '-' FROM regexp_replace(
+        translate(
+            replace(lower(value), '&', ' and '),
+            'àáâãäåçèéêëìíîïñòóôõöøùúûüýÿ',
+            'aaaaaaceeeeiiiinoooooouuuuyy'
+        ),
+        '[^a-z0-9]+', '-', 'g'
+    ));
+$$ LANGUAGE sql IMMUTABLE;
+
+-- set_slug fills in the slug of a row inserted without one.
+CREATE OR REPLACE FUNCTION set_slug()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.slug IS NULL THEN
+        NEW.slug = slugify(NEW.display_name);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE drinks ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+-- clients key on names, so names duplicated ignoring case are left for an
+-- operator to rename or merge; the migration fails listing them
+DO $$
+DECLARE
+    conflicts text;
+BEGIN
+    SELECT string_agg(format('drinks %s named %L', ids, name), '; ') INTO conflicts
+    FROM (
+        SELECT lower(name) AS name, string_agg(id::text, ', ' ORDER BY id) AS ids
+        FROM drinks GROUP BY lower(name) HAVING count(*) > 1
+    ) dup;
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'drink names must be unique ignoring case, rename or merge %', conflicts;
+    END IF;
+
+    SELECT string_agg(format('ingredients %s named %L', ids, name), '; ') INTO conflicts
+    FROM (
+        SELECT lower(name) AS name, string_agg(id::text, ', ' ORDER BY id) AS ids
+        FROM ingredients GROUP BY lower(name) HAVING count(*) > 1
+    ) dup;
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'ingredient names must be unique ignoring case, rename or merge %', conflicts;
+    END IF;
+END;
+$$;
+
+-- slugs are new, so rows whose display names slugify the same get their id
+-- appended after the oldest
+UPDATE drinks d SET slug = CASE WHEN s.n > 1 THEN s.slug || '-' || d.id ELSE s.slug END
+FROM (
+    SELECT id, slug, row_number() OVER (PARTITION BY slug ORDER BY id) AS n
+    FROM (SELECT id, coalesce(nullif(slugify(display_name), ''), 'drink-' || id) AS slug FROM drinks) d
+) s
+WHERE s.id = d.id;
+
+UPDATE ingredients i SET slug = CASE WHEN s.n > 1 THEN s.slug || '-' || i.id ELSE s.slug END
+FROM (
+    SELECT id, slug, row_number() OVER (PARTITION BY slug ORDER BY id) AS n
+    FROM (SELECT id, coalesce(nullif(slugify(display_name), ''), 'ingredient-' || id) AS slug FROM ingredients) i
+) s
+WHERE s.id = i.id;
+
+ALTER TABLE drinks ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE ingredients ALTER COLUMN slug SET NOT NULL;
+
+CREATE TRIGGER set_slug
+BEFORE INSERT ON drinks
+FOR EACH ROW
+EXECUTE PROCEDURE set_slug();
+
+CREATE TRIGGER set_slug
+BEFORE INSERT ON ingredients
+FOR EACH ROW
+EXECUTE PROCEDURE set_slug();
+
+CREATE UNIQUE INDEX drinks_name_idx ON drinks (lower(name));
+CREATE UNIQUE INDEX drinks_slug_idx ON drinks (slug);
+CREATE UNIQUE INDEX ingredients_name_idx ON ingredients (lower(name));
+CREATE UNIQUE INDEX ingredients_slug_idx ON ingredients (slug);
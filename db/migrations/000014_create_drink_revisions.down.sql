@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS drink_revisions;
+DROP FUNCTION IF EXISTS drink_snapshot(int);
+DROP FUNCTION IF EXISTS forbid_drink_revision_change();
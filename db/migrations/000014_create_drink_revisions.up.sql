@@ -0,0 +1,71 @@
+-- drink_revisions snapshots a drink and its ingredients after every change
+-- made through the service, numbered from 1 for each drink. Revisions are
+-- never changed or removed, so there is no foreign key to cascade from drinks.
+CREATE TABLE IF NOT EXISTS drink_revisions(
+    id serial PRIMARY KEY,
+    drink_id int NOT NULL,
+    number int NOT NULL,
+    -- a fixed set, see drinkee.RevisionActions
+    action VARCHAR(16) NOT NULL CHECK (action IN ('imported', 'created', 'updated', 'reverted')),
+    reverted_from int,
+    actor VARCHAR(128),
+    request_id VARCHAR(128),
+    snapshot jsonb NOT NULL,
+    created_at timestamp NOT NULL DEFAULT current_timestamp,
+    UNIQUE (drink_id, number)
+);
+
+CREATE OR REPLACE FUNCTION forbid_drink_revision_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'drink revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER forbid_change
+BEFORE UPDATE OR DELETE ON drink_revisions
+FOR EACH ROW
+EXECUTE PROCEDURE forbid_drink_revision_change();
+
+CREATE TRIGGER forbid_truncate
+BEFORE TRUNCATE ON drink_revisions
+FOR EACH STATEMENT
+EXECUTE PROCEDURE forbid_drink_revision_change();
+
+-- drink_snapshot is what a revision records of a drink, a drinkee.DrinkSnapshot.
+-- Stats are left out since they are worked out from the rest.
+CREATE OR REPLACE FUNCTION drink_snapshot(drink int)
+RETURNS jsonb AS $$
+    SELECT jsonb_strip_nulls(jsonb_build_object(
+        'name', d.name,
+        'displayName', d.display_name,
+        'description', d.description,
+        'instructions', d.instructions,
+        'image', d.image,
+        'glass', (SELECT name FROM glasses WHERE id = d.glass_id),
+        'method', d.method,
+        'ice', (SELECT name FROM ice_styles WHERE id = d.ice_id),
+        'garnish', d.garnish,
+        'origin', d.origin,
+        'creator', d.creator,
+        'year', d.year,
+        'rating', d.rating
+    )) || jsonb_build_object('drinkIngredients', COALESCE((
+        SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+            'name', i.name,
+            'displayName', i.display_name,
+            'measurement', di.measurement,
+            'optional', di.optional,
+            'role', di.role
+        )) ORDER BY di.id)
+        FROM drink_ingredients di
+        JOIN ingredients i ON i.id = di.ingredient_id
+        WHERE di.drink_id = d.id
+    ), '[]'::jsonb))
+    FROM drinks d
+    WHERE d.id = drink;
+$$ LANGUAGE sql STABLE;
+
+-- drinks made before revisions start from how they are now
+INSERT INTO drink_revisions (drink_id, number, action, snapshot, created_at)
+SELECT id, 1, 'imported', drink_snapshot(id), updated_at FROM drinks;
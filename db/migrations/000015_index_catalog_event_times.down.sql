@@ -0,0 +1 @@
+DROP INDEX IF EXISTS catalog_events_created_at_idx;
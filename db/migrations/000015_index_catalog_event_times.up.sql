@@ -0,0 +1,2 @@
+-- the event listener catches up and prunes by creation time
+CREATE INDEX IF NOT EXISTS catalog_events_created_at_idx ON catalog_events (created_at);
@@ -0,0 +1 @@
+ALTER TABLE drink_revisions DROP COLUMN IF EXISTS client_ip;
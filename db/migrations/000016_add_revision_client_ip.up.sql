@@ -0,0 +1,3 @@
+-- actors are asserted by clients, so revisions also keep where the request
+-- came from
+ALTER TABLE drink_revisions ADD COLUMN IF NOT EXISTS client_ip inet;
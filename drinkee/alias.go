@@ -0,0 +1,79 @@
+package drinkee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IngredientAliasService manages other names ingredients go by, like brands.
+// Wherever an ingredient is given by name, a name matching no ingredient,
+// ignoring case, is looked up among the aliases.
+type IngredientAliasService interface {
+	FindIngredientAliases(ctx context.Context) ([]*IngredientAlias, error)
+	CreateIngredientAlias(ctx context.Context, ca *CreateIngredientAlias) (*IngredientAlias, error)
+	DeleteIngredientAlias(ctx context.Context, id int) error
+}
+
+type IngredientAlias struct {
+	ID    int    `json:"id"`
+	Alias string `json:"alias"`
+	// Ingredient is the name of the ingredient the alias stands for.
+	Ingredient   string    `json:"ingredient"`
+	IngredientID int       `json:"ingredientId" db:"ingredient_id"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+}
+
+type CreateIngredientAlias struct {
+	Alias string `json:"alias" binding:"required,max=128"`
+	// Ingredient is the name of the ingredient the alias stands for. Giving
+	// an alias to a second ingredient makes it ambiguous.
+	Ingredient string `json:"ingredient" binding:"required"`
+}
+
+// ErrInvalidAlias is returned for an alias of an unknown ingredient, one
+// shadowed by an ingredient's name or one that already exists.
+var ErrInvalidAlias = errors.New("invalid alias")
+
+// ErrAmbiguousIngredient is returned, as an AmbiguousIngredientError, when
+// an ingredient is given by an alias of more than one.
+var ErrAmbiguousIngredient = errors.New("ambiguous ingredient")
+
+// AmbiguousIngredientError names the ingredients an alias could mean.
+type AmbiguousIngredientError struct {
+	Name        string
+	Ingredients []string
+}
+
+func (e *AmbiguousIngredientError) Error() string {
+	return fmt.Sprintf("%s %q could be any of %s", ErrAmbiguousIngredient, e.Name, strings.Join(e.Ingredients, ", "))
+}
+
+func (e *AmbiguousIngredientError) Unwrap() error {
+	return ErrAmbiguousIngredient
+}
+
+// ErrUnknownIngredient is returned, as an UnknownIngredientError, when
+// ingredients to generate from are given by names matching no ingredient or
+// alias. Drinks naming such ingredients are an ErrInvalidDrink wrapping one.
+var ErrUnknownIngredient = errors.New("unknown ingredient")
+
+// UnknownIngredientError lists the names matching nothing.
+type UnknownIngredientError struct {
+	Names []string
+}
+
+func (e *UnknownIngredientError) Error() string {
+	quoted := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return fmt.Sprintf("%s %s", ErrUnknownIngredient, strings.Join(quoted, ", "))
+}
+
+func (e *UnknownIngredientError) Unwrap() error {
+	return ErrUnknownIngredient
+}
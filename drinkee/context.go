@@ -0,0 +1,46 @@
+package drinkee
+
+import "context"
+
+type contextKey int
+
+const (
+	requestIDContextKey = contextKey(iota + 1)
+	actorContextKey
+	clientIPContextKey
+)
+
+// NewContextWithRequestID returns a copy of ctx tagged with the request's correlation ID.
+func NewContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// RequestIDFromContext returns the correlation ID stored in ctx, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// NewContextWithActor returns a copy of ctx tagged with who is making the
+// request, recorded on the drink revisions it makes.
+func NewContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, if any.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey).(string)
+	return actor
+}
+
+// NewContextWithClientIP returns a copy of ctx tagged with the address the
+// request came from, recorded next to the actor it asserted.
+func NewContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey, ip)
+}
+
+// ClientIPFromContext returns the client IP stored in ctx, if any.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey).(string)
+	return ip
+}
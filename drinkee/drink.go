@@ -1,43 +1,131 @@
 package drinkee
 
 import (
+	"context"
 	"encoding/json"
-
-	"github.com/gin-gonic/gin"
+	"errors"
+	"fmt"
 )
 
 type DrinkService interface {
-	FindDrinkByID(ctx *gin.Context, id int) (*Drink, error)
-	FindDrinks(ctx *gin.Context, f DrinkFilter) ([]*Drink, error)
-	CreateDrink(ctx *gin.Context, cr *CreateDrink) error
-	GenerateDrinks(c *gin.Context, i []Ingredient) ([]*Drink, error)
-	GenerateNonStrictDrinks(c *gin.Context, i []Ingredient) ([]*NonStrictDrink, error)
-	FindIngredients(c *gin.Context) ([]*Ingredient, error)
+	FindDrinkByID(ctx context.Context, id int) (*Drink, error)
+	// FindDrinkBySlug returns sql.ErrNoRows if there is no such drink.
+	FindDrinkBySlug(ctx context.Context, slug string) (*Drink, error)
+	FindDrinks(ctx context.Context, f DrinkFilter) ([]*Drink, error)
+	CreateDrink(ctx context.Context, cr *CreateDrink) (*Drink, error)
+	GenerateDrinks(ctx context.Context, i []Ingredient) ([]*Drink, error)
+	GenerateNonStrictDrinks(ctx context.Context, i []Ingredient, opts GenerateOptions) ([]*NonStrictDrink, error)
+	FindIngredients(ctx context.Context) ([]*Ingredient, error)
 }
 
 type Drink struct {
-	ID               int                  `json:"id"`
-	Name             string               `json:"name"`
-	DisplayName      string               `json:"displayName" db:"display_name"`
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName" db:"display_name"`
+	// Slug is derived from DisplayName when the drink is created, see
+	// Slugify, and is unique like Name.
+	Slug             string               `json:"slug"`
 	Description      string               `json:"description,omitempty"`
 	Instructions     string               `json:"instructions"`
 	DrinkIngredients DrinkIngredientSlice `json:"drinkIngredients" db:"drink_ingredients"`
+	Image            *Image               `json:"image,omitempty"`
+	Stats            *DrinkStats          `json:"stats,omitempty"`
+	// Scale is set when the recipe was scaled, see measure.ScaleDrink.
+	Scale *Scale `json:"scale,omitempty"`
+	DrinkMetadata
 }
 
 type NonStrictDrink struct {
-	ID                     int                  `json:"id"`
-	Name                   string               `json:"name"`
-	DisplayName            string               `json:"displayName" db:"display_name"`
-	Description            string               `json:"description,omitempty"`
-	Instructions           string               `json:"instructions"`
-	MissingIngredientCount int                  `json:"missingIngredientCount" db:"missing_ingredients"`
-	HaveIngredientCount    int                  `json:"haveIngredientCount" db:"ingredients_present"`
-	DrinkIngredients       DrinkIngredientSlice `json:"drinkIngredients" db:"drink_ingredients"`
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName" db:"display_name"`
+	Slug         string `json:"slug"`
+	Description  string `json:"description,omitempty"`
+	Instructions string `json:"instructions"`
+	// MissingIngredientCount leaves out optional ingredients, which
+	// MissingIngredients still lists.
+	MissingIngredientCount int `json:"missingIngredientCount" db:"missing_ingredients"`
+	HaveIngredientCount    int `json:"haveIngredientCount" db:"ingredients_present"`
+	// Coverage is the share of the drink's ingredients on hand, 0 to 1, with
+	// optional ones counting half.
+	Coverage           float64              `json:"coverage"`
+	MatchedIngredients DrinkIngredientSlice `json:"matchedIngredients" db:"matched_ingredients"`
+	MissingIngredients DrinkIngredientSlice `json:"missingIngredients" db:"missing_ingredient_list"`
+	DrinkIngredients   DrinkIngredientSlice `json:"drinkIngredients" db:"drink_ingredients"`
+	Image              *Image               `json:"image,omitempty"`
+	Stats              *DrinkStats          `json:"stats,omitempty"`
+	DrinkMetadata
+}
+
+// Rankings for non-strict generation, the only values GenerateOptions.Rank
+// accepts. Ties go to the larger share on hand, then to fewer missing, then
+// to the drink's name.
+const (
+	// RankMissing puts drinks missing the fewest ingredients first.
+	RankMissing = "missing"
+	// RankCoverage puts drinks with the largest share of their ingredients on
+	// hand first, so a two-ingredient drink missing one ranks below a
+	// six-ingredient drink missing two.
+	RankCoverage = "coverage"
+	// RankRating puts the highest rated drinks first, unrated ones last.
+	RankRating = "rating"
+)
+
+// Rankings lists every ranking.
+var Rankings = []string{RankMissing, RankCoverage, RankRating}
+
+// ErrInvalidGenerateOptions is returned for generate options out of range or
+// naming an unknown ranking.
+var ErrInvalidGenerateOptions = errors.New("invalid generate options")
+
+// GenerateOptions narrow and order non-strict generation. The zero value
+// returns every drink with an ingredient on hand, ranked by RankMissing.
+type GenerateOptions struct {
+	// MaxMissing leaves out drinks missing more ingredients, optional ones
+	// aside, nil for no cap.
+	MaxMissing *int
+	// MinCoverage leaves out drinks with a smaller share of their
+	// ingredients on hand, from 0 to 1.
+	MinCoverage float64
+	// Rank is one of Rankings, RankMissing when empty.
+	Rank string
+	// Limit caps how many drinks are returned, 0 for every match.
+	Limit int
+	Skip  int
+}
+
+// Validate returns ErrInvalidGenerateOptions for options out of range or an
+// unknown ranking.
+func (o GenerateOptions) Validate() error {
+	if o.Rank != "" && !Contains(Rankings, o.Rank) {
+		return fmt.Errorf("%w: unknown rank %q", ErrInvalidGenerateOptions, o.Rank)
+	}
+	if o.MinCoverage < 0 || o.MinCoverage > 1 {
+		return fmt.Errorf("%w: minCoverage %g must be between 0 and 1", ErrInvalidGenerateOptions, o.MinCoverage)
+	}
+	if o.MaxMissing != nil && *o.MaxMissing < 0 {
+		return fmt.Errorf("%w: maxMissing %d is negative", ErrInvalidGenerateOptions, *o.MaxMissing)
+	}
+	if o.Limit < 0 || o.Skip < 0 {
+		return fmt.Errorf("%w: limit and skip can't be negative", ErrInvalidGenerateOptions)
+	}
+	return nil
+}
+
+// Contains reports whether v is one of values, for checking fields against
+// lists like Methods and Rankings.
+func Contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
 }
 
 type DrinkResponse struct {
 	Drink      `json:"drink"`
-	TotalCount int `json:"total_count"`
+	TotalCount int `json:"totalCount"`
 }
 
 type CreateDrink struct {
@@ -45,7 +133,40 @@ type CreateDrink struct {
 	DisplayName      string            `json:"displayName" binding:"required"`
 	Description      string            `json:"description"`
 	Instructions     string            `json:"instructions" binding:"required"`
-	DrinkIngredients []DrinkIngredient `json:"drinkIngredients" binding:"required"`
+	DrinkIngredients []DrinkIngredient `json:"drinkIngredients" binding:"required,dive"`
+	DrinkMetadata
+}
+
+// Preparation methods, the only values DrinkMetadata.Method accepts.
+const (
+	MethodShaken  = "shaken"
+	MethodStirred = "stirred"
+	MethodBuilt   = "built"
+	MethodBlended = "blended"
+	MethodThrown  = "thrown"
+)
+
+// Methods lists every preparation method.
+var Methods = []string{MethodShaken, MethodStirred, MethodBuilt, MethodBlended, MethodThrown}
+
+// ErrInvalidDrink is returned when a drink refers to something that doesn't
+// exist, such as an unknown glass, or has a display name with no letters or
+// digits to make a slug of.
+var ErrInvalidDrink = errors.New("invalid drink")
+
+// DrinkMetadata describes how a drink is served and where it comes from. Every
+// field is optional. Glass and Ice must name an entry in their lookup table.
+type DrinkMetadata struct {
+	Glass   string `json:"glass,omitempty"`
+	Method  string `json:"method,omitempty" binding:"omitempty,oneof=shaken stirred built blended thrown"`
+	Ice     string `json:"ice,omitempty"`
+	Garnish string `json:"garnish,omitempty"`
+	Origin  string `json:"origin,omitempty"`
+	Creator string `json:"creator,omitempty"`
+	// Year the drink was created, 0 when unknown.
+	Year int `json:"year,omitempty" binding:"omitempty,min=1,max=9999"`
+	// Rating out of 5, 0 when unrated.
+	Rating float64 `json:"rating,omitempty" binding:"omitempty,min=0,max=5"`
 }
 
 type DrinkIngredientSlice []DrinkIngredient
@@ -67,6 +188,77 @@ type DrinkIngredient struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName" db:"display_name"`
 	Measurement string `json:"measurement"`
+	// Quantity is Measurement parsed, nil when it has no amount, like "top up".
+	// It is ignored when creating a drink.
+	Quantity *Quantity `json:"quantity,omitempty"`
+	// Optional ingredients, such as a rim of salt, don't keep a drink out of
+	// strict generation and count for less in non-strict generation.
+	// Garnishes are always optional.
+	Optional bool `json:"optional,omitempty"`
+	// Role is one of Roles, empty when unknown.
+	Role string `json:"role,omitempty" binding:"omitempty,oneof=base modifier garnish"`
+}
+
+// Roles an ingredient plays in a drink, the only values DrinkIngredient.Role
+// accepts.
+const (
+	RoleBase     = "base"
+	RoleModifier = "modifier"
+	RoleGarnish  = "garnish"
+)
+
+// Roles lists every role.
+var Roles = []string{RoleBase, RoleModifier, RoleGarnish}
+
+type Quantity struct {
+	Amount float64 `json:"amount"`
+	// Unit is normalized, e.g. "oz" for "fl oz", and empty for plain counts.
+	Unit string `json:"unit,omitempty"`
+	// ML is the volume in milliliters, nil when a count has no known volume.
+	ML *float64 `json:"ml,omitempty"`
+}
+
+// DrinkStats are estimates worked out from a drink's measurements and its
+// ingredients' strength and sugar, after dilution from its method.
+type DrinkStats struct {
+	// Volume is the final volume in milliliters, including melted ice.
+	Volume         float64 `json:"volumeMl"`
+	ABV            float64 `json:"abv"`
+	StandardDrinks float64 `json:"standardDrinks"`
+	Calories       float64 `json:"calories"`
+	// Unmeasured names ingredients whose volume isn't known. They are left
+	// out of the estimates.
+	Unmeasured []string `json:"unmeasured,omitempty"`
+}
+
+// ErrUnknownVolume is returned when a drink without stats is scaled to a batch
+// volume or diluted.
+var ErrUnknownVolume = errors.New("drink has no known volume")
+
+// Scale describes a recipe scaled for a number of servings or a batch.
+type Scale struct {
+	Servings float64 `json:"servings"`
+	// Units is "metric" or "us", empty when each ingredient keeps the
+	// system it was measured in.
+	Units string `json:"units,omitempty"`
+	// Volume is the batch's total in milliliters, water included, 0 when
+	// unknown.
+	Volume float64 `json:"volumeMl,omitempty"`
+	// Water is milliliters of water added in place of melting ice.
+	Water float64 `json:"waterMl,omitempty"`
+}
+
+func (ds *DrinkStats) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, ds)
 }
 
 type DrinkFilter struct {
@@ -74,4 +266,22 @@ type DrinkFilter struct {
 	Skip  int
 	Name  *string `json:"name,omitempty"`
 	ID    *int    `json:"id,omitempty"`
+	// Ingredients matches drinks that use any of the named ingredients.
+	// Names matching no ingredient or alias match no drinks.
+	Ingredients []string `json:"ingredients,omitempty"`
+
+	Glass  *string `json:"glass,omitempty"`
+	Method *string `json:"method,omitempty"`
+	Ice    *string `json:"ice,omitempty"`
+	// Garnish, Origin and Creator match case insensitive substrings.
+	Garnish *string `json:"garnish,omitempty"`
+	Origin  *string `json:"origin,omitempty"`
+	Creator *string `json:"creator,omitempty"`
+	Year    *int    `json:"year,omitempty"`
+
+	// MinABV, MaxABV and MaxCalories match on DrinkStats. Drinks without
+	// stats never match.
+	MinABV      *float64 `json:"minAbv,omitempty"`
+	MaxABV      *float64 `json:"maxAbv,omitempty"`
+	MaxCalories *float64 `json:"maxCalories,omitempty"`
 }
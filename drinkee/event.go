@@ -0,0 +1,40 @@
+package drinkee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Catalog event types, <resource>.<change>. Events are recorded by the
+// database whenever a drink, its ingredients or an ingredient change.
+const (
+	EventDrinkCreated      = "drink.created"
+	EventDrinkUpdated      = "drink.updated"
+	EventDrinkDeleted      = "drink.deleted"
+	EventIngredientCreated = "ingredient.created"
+	EventIngredientUpdated = "ingredient.updated"
+	EventIngredientDeleted = "ingredient.deleted"
+)
+
+// ErrEventsExpired is returned when events after the requested ID have
+// already left the replay buffer.
+var ErrEventsExpired = errors.New("events after the given id are no longer available")
+
+// EventService streams catalog events to live subscribers.
+type EventService interface {
+	// SubscribeEvents returns a channel of events committed after lastEventID,
+	// or only new events when lastEventID is 0. The channel is closed when ctx
+	// is done or the subscriber falls too far behind.
+	SubscribeEvents(ctx context.Context, lastEventID int64) (<-chan Event, error)
+}
+
+// Event is a change to the catalog. Data holds the id, name and displayName
+// of the changed resource.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
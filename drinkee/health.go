@@ -0,0 +1,14 @@
+package drinkee
+
+import "context"
+
+type HealthService interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+// BuildInfo is injected at build time through -ldflags and reported on /version.
+type BuildInfo struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+}
@@ -0,0 +1,46 @@
+package drinkee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// MaxImageSize is the largest drink image upload accepted, in bytes.
+const MaxImageSize = 10 << 20
+
+var (
+	ErrImageTooLarge = errors.New("image is larger than 10MB or 40 megapixels")
+	// ErrImageType is returned for uploads that aren't a JPEG, PNG or WebP
+	// image, or that fail to decode as one.
+	ErrImageType = errors.New("image must be a JPEG, PNG or WebP")
+)
+
+type ImageService interface {
+	// SetDrinkImage stores an uploaded image and its thumbnails and makes it
+	// the drink's image, replacing any previous one.
+	SetDrinkImage(ctx context.Context, drinkID int, r io.Reader) (*Image, error)
+}
+
+type Image struct {
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	// Thumbnails maps a size name, such as "small", to its URL.
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+func (img *Image) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, img)
+}
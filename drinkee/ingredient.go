@@ -1,7 +1,42 @@
 package drinkee
 
+// Ingredient categories, in shopping list order.
+const (
+	CategorySpirit  = "spirit"
+	CategoryLiqueur = "liqueur"
+	CategoryWine    = "wine"
+	CategoryBeer    = "beer"
+	CategoryBitters = "bitters"
+	CategorySyrup   = "syrup"
+	CategoryJuice   = "juice"
+	CategoryMixer   = "mixer"
+	CategoryDairy   = "dairy"
+	CategoryProduce = "produce"
+	CategoryOther   = "other"
+)
+
+// IngredientCategories lists every category.
+var IngredientCategories = []string{
+	CategorySpirit, CategoryLiqueur, CategoryWine, CategoryBeer, CategoryBitters, CategorySyrup,
+	CategoryJuice, CategoryMixer, CategoryDairy, CategoryProduce, CategoryOther,
+}
+
 type Ingredient struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName" db:"display_name"`
+	// Slug is derived from DisplayName, see Slugify, and is unique like Name.
+	Slug string `json:"slug"`
+	// Category is one of IngredientCategories.
+	Category string `json:"category,omitempty"`
+	// ABV is percent alcohol by volume.
+	ABV float64 `json:"abv"`
+	// Sugar is grams per 100ml.
+	Sugar float64 `json:"sugarPer100ml" db:"sugar_per_100ml"`
+	// Calories is kcal per 100ml. When nil it is worked out from ABV and
+	// Sugar.
+	Calories *float64 `json:"caloriesPer100ml,omitempty" db:"calories_per_100ml"`
+	// UnitML is the volume of one of a counted ingredient, like "1 egg white",
+	// 0 when unknown.
+	UnitML float64 `json:"unitMl,omitempty" db:"unit_ml"`
 }
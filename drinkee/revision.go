@@ -0,0 +1,159 @@
+package drinkee
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// DrinkRevisionService keeps the history of every drink. Each create, update
+// and revert of a drink or its ingredients is recorded as a numbered revision
+// that is never changed.
+type DrinkRevisionService interface {
+	// FindDrinkRevisions returns the drink's revisions, newest first, or
+	// sql.ErrNoRows if it has none.
+	FindDrinkRevisions(ctx context.Context, drinkID int) ([]*DrinkRevision, error)
+	// FindDrinkRevision returns sql.ErrNoRows if the drink has no such
+	// revision.
+	FindDrinkRevision(ctx context.Context, drinkID, number int) (*DrinkRevision, error)
+	// RevertDrink restores the drink to how it was at a revision, keeping its
+	// slug, and records that as a new revision. It returns sql.ErrNoRows if
+	// the drink has no such revision.
+	RevertDrink(ctx context.Context, drinkID, number int) (*Drink, error)
+}
+
+// Revision actions, the only values DrinkRevision.Action takes.
+const (
+	// RevisionImported is the first revision of a drink made before
+	// revisions were recorded.
+	RevisionImported = "imported"
+	RevisionCreated  = "created"
+	RevisionUpdated  = "updated"
+	RevisionReverted = "reverted"
+)
+
+// RevisionActions lists every revision action.
+var RevisionActions = []string{RevisionImported, RevisionCreated, RevisionUpdated, RevisionReverted}
+
+type DrinkRevision struct {
+	DrinkID int `json:"drinkId" db:"drink_id"`
+	// Number counts a drink's revisions from 1.
+	Number int `json:"number"`
+	// Action is one of RevisionActions.
+	Action string `json:"action"`
+	// RevertedFrom is the revision a revert restored, 0 for other actions.
+	RevertedFrom int `json:"revertedFrom,omitempty" db:"reverted_from"`
+	// Actor is who the request said made the change, see
+	// NewContextWithActor, empty when unknown. Nothing authenticates it.
+	Actor string `json:"actor,omitempty"`
+	// ClientIP is the address the change was requested from.
+	ClientIP  string        `json:"clientIp,omitempty" db:"client_ip"`
+	RequestID string        `json:"requestId,omitempty" db:"request_id"`
+	CreatedAt time.Time     `json:"createdAt" db:"created_at"`
+	Drink     DrinkSnapshot `json:"drink" db:"snapshot"`
+}
+
+// DrinkSnapshot is a drink as a revision recorded it. It leaves out the ID,
+// slug and stats, which a revert doesn't change.
+type DrinkSnapshot struct {
+	Name             string               `json:"name"`
+	DisplayName      string               `json:"displayName"`
+	Description      string               `json:"description,omitempty"`
+	Instructions     string               `json:"instructions"`
+	DrinkIngredients DrinkIngredientSlice `json:"drinkIngredients"`
+	Image            *Image               `json:"image,omitempty"`
+	DrinkMetadata
+}
+
+func (ds *DrinkSnapshot) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, ds)
+}
+
+// RevisionDiff is what changed from one revision of a drink to another.
+type RevisionDiff struct {
+	DrinkID int `json:"drinkId"`
+	From    int `json:"from"`
+	To      int `json:"to"`
+	// Fields are the changed fields other than ingredients, in the order
+	// DrinkSnapshot declares them, named as in JSON.
+	Fields []FieldChange `json:"fields"`
+	// Ingredients are the ingredients changed or added, in their order in
+	// the later revision, then the ones removed.
+	Ingredients []IngredientChange `json:"ingredients"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// IngredientChange has no From for an added ingredient and no To for a
+// removed one.
+type IngredientChange struct {
+	Name string           `json:"name"`
+	From *DrinkIngredient `json:"from"`
+	To   *DrinkIngredient `json:"to"`
+}
+
+// DiffDrinkRevisions compares two revisions of the same drink.
+func DiffDrinkRevisions(from, to *DrinkRevision) *RevisionDiff {
+	diff := &RevisionDiff{
+		DrinkID:     to.DrinkID,
+		From:        from.Number,
+		To:          to.Number,
+		Fields:      []FieldChange{},
+		Ingredients: []IngredientChange{},
+	}
+
+	a, b := &from.Drink, &to.Drink
+	for _, f := range []FieldChange{
+		{"name", a.Name, b.Name},
+		{"displayName", a.DisplayName, b.DisplayName},
+		{"description", a.Description, b.Description},
+		{"instructions", a.Instructions, b.Instructions},
+		{"image", a.Image, b.Image},
+		{"glass", a.Glass, b.Glass},
+		{"method", a.Method, b.Method},
+		{"ice", a.Ice, b.Ice},
+		{"garnish", a.Garnish, b.Garnish},
+		{"origin", a.Origin, b.Origin},
+		{"creator", a.Creator, b.Creator},
+		{"year", a.Year, b.Year},
+		{"rating", a.Rating, b.Rating},
+	} {
+		if !reflect.DeepEqual(f.From, f.To) {
+			diff.Fields = append(diff.Fields, f)
+		}
+	}
+
+	before := make(map[string]*DrinkIngredient, len(a.DrinkIngredients))
+	for i := range a.DrinkIngredients {
+		before[a.DrinkIngredients[i].Name] = &a.DrinkIngredients[i]
+	}
+	after := make(map[string]bool, len(b.DrinkIngredients))
+	for i := range b.DrinkIngredients {
+		di := &b.DrinkIngredients[i]
+		after[di.Name] = true
+		if old, ok := before[di.Name]; !ok || !reflect.DeepEqual(*old, *di) {
+			diff.Ingredients = append(diff.Ingredients, IngredientChange{Name: di.Name, From: old, To: di})
+		}
+	}
+	for i := range a.DrinkIngredients {
+		if di := &a.DrinkIngredients[i]; !after[di.Name] {
+			diff.Ingredients = append(diff.Ingredients, IngredientChange{Name: di.Name, From: di})
+		}
+	}
+
+	return diff
+}
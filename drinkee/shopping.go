@@ -0,0 +1,67 @@
+package drinkee
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidShoppingList is returned for a shopping list naming a drink that
+// doesn't exist or a pantry amount that can't be read.
+var ErrInvalidShoppingList = errors.New("invalid shopping list")
+
+type ShoppingListService interface {
+	BuildShoppingList(ctx context.Context, r *ShoppingListRequest) (*ShoppingList, error)
+}
+
+type ShoppingListRequest struct {
+	Drinks []ShoppingListDrink `json:"drinks" binding:"required,min=1,max=100,dive"`
+	// Pantry is subtracted from what the drinks need.
+	Pantry []PantryItem `json:"pantry" binding:"dive"`
+	// Units is "metric" or "us", metric by default. Bottles are always
+	// metric.
+	Units string `json:"units" binding:"omitempty,oneof=metric us"`
+}
+
+type ShoppingListDrink struct {
+	ID       int     `json:"id" binding:"required"`
+	Servings float64 `json:"servings" binding:"required,gt=0,lte=1000"`
+}
+
+type PantryItem struct {
+	Name string `json:"name" binding:"required"`
+	// Amount on hand, such as "350ml" or "4 wedges". When empty there is
+	// enough.
+	Amount string `json:"amount,omitempty"`
+}
+
+type ShoppingList struct {
+	// Categories are ordered as IngredientCategories, skipping empty ones.
+	Categories []ShoppingCategory `json:"categories"`
+	// Covered names ingredients the pantry has enough of.
+	Covered []string `json:"covered,omitempty"`
+}
+
+type ShoppingCategory struct {
+	Category string         `json:"category"`
+	Items    []ShoppingItem `json:"items"`
+}
+
+type ShoppingItem struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	// Needed is what the drinks call for, such as "1.2 l" or "8 wedges", or
+	// "as needed" for measurements without an amount, like "top up".
+	Needed string `json:"needed"`
+	// Buy is what's left after the pantry.
+	Buy   string   `json:"buy"`
+	BuyML *float64 `json:"buyMl,omitempty"`
+	// Bottles to buy, for bottled categories.
+	Bottles []Bottle `json:"bottles,omitempty"`
+	// Drinks names the drinks using the ingredient.
+	Drinks []string `json:"drinks"`
+}
+
+type Bottle struct {
+	Size  float64 `json:"sizeMl"`
+	Count int     `json:"count"`
+}
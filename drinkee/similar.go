@@ -0,0 +1,50 @@
+package drinkee
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type SimilarityService interface {
+	// FindSimilarDrinks returns up to limit drinks sharing ingredients with
+	// the drink, most similar first, or sql.ErrNoRows if there is no such
+	// drink.
+	FindSimilarDrinks(ctx context.Context, id int, limit int) ([]*SimilarDrink, error)
+}
+
+type SimilarDrink struct {
+	Drink
+	Similarity Similarity `json:"similarity"`
+}
+
+// Similarity is the weighted Jaccard index of two drinks' ingredients: the
+// weight of the ingredients they share over the weight of all of them. An
+// ingredient weighs less the more drinks use it, so sharing ice or sugar
+// counts for little and sharing a rare liqueur for a lot.
+type Similarity struct {
+	Score float64 `json:"score"`
+	// Shared ingredients are in both drinks, Missing ones only in the drink
+	// compared against and Extra ones only in this drink, heaviest first.
+	Shared  []WeightedIngredient `json:"shared"`
+	Missing []WeightedIngredient `json:"missing"`
+	Extra   []WeightedIngredient `json:"extra"`
+}
+
+func (sim *Similarity) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, sim)
+}
+
+type WeightedIngredient struct {
+	Name        string  `json:"name"`
+	DisplayName string  `json:"displayName"`
+	Weight      float64 `json:"weight"`
+}
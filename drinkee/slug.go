@@ -0,0 +1,64 @@
+package drinkee
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// accents folds the accented letters the slugify migration function folds.
+var accents = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u",
+	"ý", "y", "ÿ", "y",
+)
+
+// Slugify derives the canonical slug of a display name, lower case letters
+// and digits in runs joined by hyphens, so "Piña Colada" is "pina-colada"
+// and "Gin & Tonic" is "gin-and-tonic". It agrees with the database's slugify,
+// which fills in slugs the server didn't set. It is empty when the name has
+// no letters or digits.
+func Slugify(displayName string) string {
+	s := accents.Replace(strings.ReplaceAll(strings.ToLower(displayName), "&", " and "))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
+
+// ErrConflict is returned, as a ConflictError, when a create would duplicate
+// the name or slug of an existing record.
+var ErrConflict = errors.New("already exists")
+
+// ConflictError names the existing record a create collided with.
+type ConflictError struct {
+	// Resource is "drink" or "ingredient".
+	Resource string
+	ID       int
+	Name     string
+	Slug     string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %q %s with id %d and slug %q", e.Resource, e.Name, ErrConflict, e.ID, e.Slug)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
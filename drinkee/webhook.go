@@ -0,0 +1,102 @@
+package drinkee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookService interface {
+	CreateWebhookSubscription(ctx context.Context, cs *CreateWebhookSubscription) (*WebhookSubscription, error)
+	FindWebhookSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int) error
+	FindWebhookDeliveries(ctx context.Context, f WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+	FindWebhookDeliveryByID(ctx context.Context, id int64) (*WebhookDelivery, error)
+	// RedeliverWebhook queues a new delivery of the same event to the same
+	// subscription, leaving the original and its attempts untouched.
+	RedeliverWebhook(ctx context.Context, id int64) (*WebhookDelivery, error)
+}
+
+type WebhookSubscription struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+	// Secret is only returned when the subscription is created.
+	Secret string `json:"secret,omitempty"`
+	// Events filters which event types are delivered, empty means all.
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type CreateWebhookSubscription struct {
+	URL string `json:"url" binding:"required,url"`
+	// Secret signs deliveries, one is generated when empty.
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"dive,oneof=drink.created drink.updated drink.deleted ingredient.created ingredient.updated ingredient.deleted"`
+}
+
+// ErrInvalidWebhook is returned for a subscription URL deliveries can't be
+// sent to.
+var ErrInvalidWebhook = errors.New("invalid webhook subscription")
+
+// Validate returns ErrInvalidWebhook unless the URL is https, or http when
+// allowHTTP is set. Where the URL may point is checked on delivery, when its
+// host is resolved.
+func (cs *CreateWebhookSubscription) Validate(allowHTTP bool) error {
+	u, err := url.Parse(cs.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidWebhook, err)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: url has no host", ErrInvalidWebhook)
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && allowHTTP:
+	default:
+		return fmt.Errorf("%w: url must use https", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	SubscriptionID int        `json:"subscriptionId" db:"subscription_id"`
+	URL            string     `json:"url"`
+	Event          Event      `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	// AttemptLog is only loaded by FindWebhookDeliveryByID.
+	AttemptLog []WebhookAttempt `json:"attemptLog,omitempty"`
+	// Secret is only set on deliveries claimed for sending.
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt is the outcome of one POST of a delivery. StatusCode is 0
+// when no response was received, with the reason in Error.
+type WebhookAttempt struct {
+	ID         int64     `json:"id"`
+	DeliveryID int64     `json:"deliveryId" db:"delivery_id"`
+	StatusCode int       `json:"statusCode" db:"status_code"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int       `json:"durationMs" db:"duration_ms"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+type WebhookDeliveryFilter struct {
+	Limit          int
+	Skip           int
+	SubscriptionID *int
+	Status         *string
+}
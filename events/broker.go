@@ -0,0 +1,160 @@
+// Package events fans catalog events out to live subscribers. Every replica
+// runs its own Broker fed by postgres.EventListener, buffering events in the
+// order they arrive. Postgres notifies in commit order, which isn't ID order,
+// and events a replica catches up on after reconnecting arrive late, so
+// replicas can buffer the same events in different orders. Resuming with
+// Last-Event-ID on the same replica is exact; on another one, events received
+// close together can repeat or be missed.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+const (
+	DefaultBufferSize = 1000
+	// DefaultSubscriberBuffer is how many events a subscriber can fall behind
+	// before it's dropped.
+	DefaultSubscriberBuffer = 64
+)
+
+var _ drinkee.EventService = (*Broker)(nil)
+
+type Broker struct {
+	mu sync.Mutex
+	// buffer is a ring of the most recent events in the order they arrived.
+	buffer []drinkee.Event
+	start  int
+	seen   map[int64]struct{}
+	subs   map[*subscriber]struct{}
+
+	SubscriberBuffer int
+}
+
+type subscriber struct {
+	ch chan drinkee.Event
+	// done is closed with ch, ending the goroutine waiting on the context
+	done   chan struct{}
+	closed bool
+}
+
+// NewBroker returns a broker that keeps the last size events for replay.
+func NewBroker(size int) *Broker {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Broker{
+		buffer:           make([]drinkee.Event, 0, size),
+		seen:             make(map[int64]struct{}, size),
+		subs:             make(map[*subscriber]struct{}),
+		SubscriberBuffer: DefaultSubscriberBuffer,
+	}
+}
+
+// Publish buffers e and sends it to every subscriber. Events already buffered
+// are ignored, so a listener catching up after a reconnect can't send
+// duplicates. Subscribers that aren't keeping up are closed and can resume
+// from the buffer.
+func (b *Broker) Publish(e drinkee.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.seen[e.ID]; ok {
+		return
+	}
+
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, e)
+	} else {
+		delete(b.seen, b.buffer[b.start].ID)
+		b.buffer[b.start] = e
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+	b.seen[e.ID] = struct{}{}
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- e:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+// LastEventID returns the ID of the most recently published event, or 0.
+func (b *Broker) LastEventID() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.buffer) == 0 {
+		return 0
+	}
+	return b.at(len(b.buffer) - 1).ID
+}
+
+func (b *Broker) SubscribeEvents(ctx context.Context, lastEventID int64) (<-chan drinkee.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []drinkee.Event
+	if lastEventID != 0 {
+		i := b.indexOf(lastEventID)
+		if i < 0 {
+			return nil, drinkee.ErrEventsExpired
+		}
+		for j := i + 1; j < len(b.buffer); j++ {
+			replay = append(replay, b.at(j))
+		}
+	}
+
+	sub := &subscriber{ch: make(chan drinkee.Event, len(replay)+b.SubscriberBuffer), done: make(chan struct{})}
+	for _, e := range replay {
+		sub.ch <- e
+	}
+	b.subs[sub] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-sub.done:
+			return
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	}()
+
+	return sub.ch, nil
+}
+
+// remove closes sub once. b.mu must be held.
+func (b *Broker) remove(sub *subscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+	close(sub.done)
+	delete(b.subs, sub)
+}
+
+// at returns the i-th oldest buffered event. b.mu must be held.
+func (b *Broker) at(i int) drinkee.Event {
+	return b.buffer[(b.start+i)%len(b.buffer)]
+}
+
+// indexOf finds id in the buffer, newest first, or returns -1. b.mu must be held.
+func (b *Broker) indexOf(id int64) int {
+	if _, ok := b.seen[id]; !ok {
+		return -1
+	}
+	for i := len(b.buffer) - 1; i >= 0; i-- {
+		if b.at(i).ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,93 @@
+package events_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func ids(ch <-chan drinkee.Event, n int) []int64 {
+	var got []int64
+	for i := 0; i < n; i++ {
+		got = append(got, (<-ch).ID)
+	}
+	return got
+}
+
+func TestBrokerReplaysAfterLastEventID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := events.NewBroker(3)
+	for id := int64(1); id <= 5; id++ {
+		b.Publish(drinkee.Event{ID: id, Type: drinkee.EventDrinkCreated})
+	}
+	// already buffered, ignored
+	b.Publish(drinkee.Event{ID: 4})
+	assert.Equal(t, int64(5), b.LastEventID())
+
+	ch, err := b.SubscribeEvents(ctx, 3)
+	assert.NoError(t, err)
+	b.Publish(drinkee.Event{ID: 6})
+	assert.Equal(t, []int64{4, 5, 6}, ids(ch, 3))
+
+	// 2 has been pushed out of the buffer
+	_, err = b.SubscribeEvents(ctx, 2)
+	assert.ErrorIs(t, err, drinkee.ErrEventsExpired)
+
+	live, err := b.SubscribeEvents(ctx, 0)
+	assert.NoError(t, err)
+	b.Publish(drinkee.Event{ID: 7})
+	assert.Equal(t, []int64{7}, ids(live, 1))
+}
+
+func TestBrokerClosesSubscribers(t *testing.T) {
+	b := events.NewBroker(10)
+	b.SubscriberBuffer = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	slow, _ := b.SubscribeEvents(ctx, 0)
+
+	b.Publish(drinkee.Event{ID: 1})
+	b.Publish(drinkee.Event{ID: 2})
+
+	// the first event was buffered, then the subscriber was dropped
+	assert.Equal(t, int64(1), (<-slow).ID)
+	_, open := <-slow
+	assert.False(t, open)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	done, _ := b.SubscribeEvents(ctx, 0)
+	cancel()
+	_, open = <-done
+	assert.False(t, open)
+}
+
+func TestBrokerDroppedSubscribersDontLeak(t *testing.T) {
+	b := events.NewBroker(10)
+	b.SubscriberBuffer = 1
+
+	// a context that outlives the subscriptions, like FollowMatcher's
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	before := runtime.NumGoroutine()
+	for i := int64(1); i <= 50; i++ {
+		b.SubscribeEvents(ctx, 0)
+		b.Publish(drinkee.Event{ID: 2 * i})
+		b.Publish(drinkee.Event{ID: 2*i + 1})
+	}
+
+	// assert.Eventually would count its own goroutine
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
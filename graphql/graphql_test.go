@@ -0,0 +1,120 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type catalog struct {
+	drinkee.DrinkService
+
+	mu      sync.Mutex
+	calls   map[string]int
+	filters []drinkee.DrinkFilter
+}
+
+var (
+	gin_    = drinkee.DrinkIngredient{Name: "gin", DisplayName: "Gin", Measurement: "1 oz"}
+	campari = drinkee.DrinkIngredient{Name: "campari", DisplayName: "Campari", Measurement: "1 oz"}
+	tonic   = drinkee.DrinkIngredient{Name: "tonic", DisplayName: "Tonic", Measurement: "4 oz"}
+
+	drinks = []*drinkee.Drink{
+		{ID: 1, Name: "negroni", DisplayName: "Negroni", DrinkIngredients: drinkee.DrinkIngredientSlice{gin_, campari}},
+		{ID: 2, Name: "gin and tonic", DisplayName: "Gin and Tonic", DrinkIngredients: drinkee.DrinkIngredientSlice{gin_, tonic}},
+	}
+)
+
+func (c *catalog) record(method string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[method]++
+}
+
+func (c *catalog) FindIngredients(ctx context.Context) ([]*drinkee.Ingredient, error) {
+	c.record("FindIngredients")
+	return []*drinkee.Ingredient{
+		{ID: 1, Name: "campari", DisplayName: "Campari"},
+		{ID: 2, Name: "gin", DisplayName: "Gin"},
+		{ID: 3, Name: "tonic", DisplayName: "Tonic"},
+	}, nil
+}
+
+func (c *catalog) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	c.record("FindDrinks")
+	c.mu.Lock()
+	c.filters = append(c.filters, f)
+	c.mu.Unlock()
+
+	var matches []*drinkee.Drink
+	for _, d := range drinks {
+		if len(f.Ingredients) == 0 {
+			matches = append(matches, d)
+			continue
+		}
+	next:
+		for _, di := range d.DrinkIngredients {
+			for _, name := range f.Ingredients {
+				if di.Name == name {
+					matches = append(matches, d)
+					break next
+				}
+			}
+		}
+	}
+	return matches, nil
+}
+
+func execute(t *testing.T, h http.Handler, query string) map[string]interface{} {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data   map[string]interface{}   `json:"data"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Errors)
+	return resp.Data
+}
+
+func TestNestedQueriesAreBatched(t *testing.T) {
+	c := &catalog{calls: map[string]int{}}
+	h := &graphql.Handler{DrinkService: c}
+
+	data := execute(t, h, `{ ingredients { name drinks { name drinkIngredients { ingredient { id } } } } }`)
+
+	ingredients := data["ingredients"].([]interface{})
+	assert.Len(t, ingredients, 3)
+	gin := ingredients[1].(map[string]interface{})
+	assert.Equal(t, "gin", gin["name"])
+	assert.Len(t, gin["drinks"], 2)
+
+	// one query for the ingredient list, one for every ingredient's drinks and
+	// one for every drink ingredient's ingredient
+	assert.Equal(t, 1, c.calls["FindDrinks"])
+	assert.Equal(t, 2, c.calls["FindIngredients"])
+	assert.ElementsMatch(t, []string{"campari", "gin", "tonic"}, c.filters[0].Ingredients)
+}
+
+func TestDrinksQueryDefaults(t *testing.T) {
+	c := &catalog{calls: map[string]int{}}
+	h := &graphql.Handler{DrinkService: c}
+
+	data := execute(t, h, `{ drinks { displayName } }`)
+
+	assert.Len(t, data["drinks"], 2)
+	assert.Equal(t, 100, c.filters[0].Limit)
+}
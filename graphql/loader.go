@@ -0,0 +1,89 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// batchWait is how long a loader collects keys before it queries. graphql-go
+// resolves sibling list items concurrently, so this groups a whole level of
+// the query into one SQL statement.
+const batchWait = 2 * time.Millisecond
+
+// loaders are created per request so batches never mix callers and cached
+// values never outlive the request.
+type loaders struct {
+	drinkService drinkee.DrinkService
+
+	ingredientByName   *dataloader.Loader[string, *drinkee.Ingredient]
+	drinksByIngredient *dataloader.Loader[string, []*drinkee.Drink]
+}
+
+func newLoaders(ds drinkee.DrinkService) *loaders {
+	l := &loaders{drinkService: ds}
+	l.ingredientByName = dataloader.NewBatchedLoader(l.loadIngredients, dataloader.WithWait[string, *drinkee.Ingredient](batchWait))
+	l.drinksByIngredient = dataloader.NewBatchedLoader(l.loadDrinksByIngredient, dataloader.WithWait[string, []*drinkee.Drink](batchWait))
+	return l
+}
+
+type contextKey int
+
+const loadersContextKey = contextKey(0)
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersContextKey).(*loaders)
+}
+
+// loadIngredients answers every name in the batch from one FindIngredients call.
+func (l *loaders) loadIngredients(ctx context.Context, names []string) []*dataloader.Result[*drinkee.Ingredient] {
+	results := make([]*dataloader.Result[*drinkee.Ingredient], len(names))
+
+	ingredients, err := l.drinkService.FindIngredients(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*drinkee.Ingredient]{Error: err}
+		}
+		return results
+	}
+
+	byName := make(map[string]*drinkee.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		byName[ingredient.Name] = ingredient
+	}
+	for i, name := range names {
+		results[i] = &dataloader.Result[*drinkee.Ingredient]{Data: byName[name]}
+	}
+	return results
+}
+
+// loadDrinksByIngredient fetches drinks for every ingredient in the batch with
+// a single FindDrinks and groups them by ingredient name.
+func (l *loaders) loadDrinksByIngredient(ctx context.Context, names []string) []*dataloader.Result[[]*drinkee.Drink] {
+	results := make([]*dataloader.Result[[]*drinkee.Drink], len(names))
+
+	drinks, err := l.drinkService.FindDrinks(ctx, drinkee.DrinkFilter{Ingredients: names})
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*drinkee.Drink]{Error: err}
+		}
+		return results
+	}
+
+	byIngredient := make(map[string][]*drinkee.Drink, len(names))
+	for _, drink := range drinks {
+		for _, di := range drink.DrinkIngredients {
+			byIngredient[di.Name] = append(byIngredient[di.Name], drink)
+		}
+	}
+	for i, name := range names {
+		results[i] = &dataloader.Result[[]*drinkee.Drink]{Data: byIngredient[name]}
+	}
+	return results
+}
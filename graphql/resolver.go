@@ -0,0 +1,383 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type queryResolver struct{}
+
+func (r *queryResolver) Drinks(ctx context.Context, args struct {
+	Limit       int32
+	Skip        int32
+	Name        *string
+	Ingredients *[]string
+	Filter      *struct {
+		Glass   *string
+		Method  *string
+		Ice     *string
+		Garnish *string
+		Origin  *string
+		Creator *string
+		Year    *int32
+
+		MinAbv      *float64
+		MaxAbv      *float64
+		MaxCalories *float64
+	}
+}) ([]*drinkResolver, error) {
+	f := drinkee.DrinkFilter{
+		Limit: int(args.Limit),
+		Skip:  int(args.Skip),
+		Name:  args.Name,
+	}
+	if args.Ingredients != nil {
+		f.Ingredients = *args.Ingredients
+	}
+	if mf := args.Filter; mf != nil {
+		f.Glass, f.Method, f.Ice = mf.Glass, mf.Method, mf.Ice
+		f.Garnish, f.Origin, f.Creator = mf.Garnish, mf.Origin, mf.Creator
+		if mf.Year != nil {
+			year := int(*mf.Year)
+			f.Year = &year
+		}
+		f.MinABV, f.MaxABV, f.MaxCalories = mf.MinAbv, mf.MaxAbv, mf.MaxCalories
+	}
+
+	drinks, err := loadersFrom(ctx).drinkService.FindDrinks(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return newDrinkResolvers(drinks), nil
+}
+
+func (r *queryResolver) Drink(ctx context.Context, args struct{ ID graphql.ID }) (*drinkResolver, error) {
+	id, err := strconv.Atoi(string(args.ID))
+	if err != nil {
+		return nil, errors.New("invalid drink id")
+	}
+
+	drink, err := loadersFrom(ctx).drinkService.FindDrinkByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &drinkResolver{drink}, nil
+}
+
+func (r *queryResolver) DrinkBySlug(ctx context.Context, args struct{ Slug string }) (*drinkResolver, error) {
+	drink, err := loadersFrom(ctx).drinkService.FindDrinkBySlug(ctx, args.Slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &drinkResolver{drink}, nil
+}
+
+func (r *queryResolver) Ingredients(ctx context.Context) ([]*ingredientResolver, error) {
+	ingredients, err := loadersFrom(ctx).drinkService.FindIngredients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*ingredientResolver, 0, len(ingredients))
+	for _, i := range ingredients {
+		resolvers = append(resolvers, &ingredientResolver{i})
+	}
+	return resolvers, nil
+}
+
+func (r *queryResolver) Ingredient(ctx context.Context, args struct{ Name string }) (*ingredientResolver, error) {
+	ingredient, err := loadersFrom(ctx).ingredientByName.Load(ctx, args.Name)()
+	if err != nil || ingredient == nil {
+		return nil, err
+	}
+	return &ingredientResolver{ingredient}, nil
+}
+
+func (r *queryResolver) GenerateDrinks(ctx context.Context, args struct{ IngredientIds []graphql.ID }) ([]*drinkResolver, error) {
+	ingredients, err := ingredientRefs(args.IngredientIds)
+	if err != nil {
+		return nil, err
+	}
+
+	drinks, err := loadersFrom(ctx).drinkService.GenerateDrinks(ctx, ingredients)
+	if err != nil {
+		return nil, err
+	}
+	return newDrinkResolvers(drinks), nil
+}
+
+func (r *queryResolver) GenerateNonStrictDrinks(ctx context.Context, args struct {
+	IngredientIds []graphql.ID
+	MaxMissing    *int32
+	MinCoverage   float64
+	Rank          string
+	Limit         int32
+	Skip          int32
+}) ([]*nonStrictDrinkResolver, error) {
+	ingredients, err := ingredientRefs(args.IngredientIds)
+	if err != nil {
+		return nil, err
+	}
+	opts := drinkee.GenerateOptions{
+		MinCoverage: args.MinCoverage,
+		Rank:        args.Rank,
+		Limit:       int(args.Limit),
+		Skip:        int(args.Skip),
+	}
+	if args.MaxMissing != nil {
+		maxMissing := int(*args.MaxMissing)
+		opts.MaxMissing = &maxMissing
+	}
+
+	drinks, err := loadersFrom(ctx).drinkService.GenerateNonStrictDrinks(ctx, ingredients, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*nonStrictDrinkResolver, 0, len(drinks))
+	for _, d := range drinks {
+		resolvers = append(resolvers, &nonStrictDrinkResolver{d})
+	}
+	return resolvers, nil
+}
+
+type drinkResolver struct {
+	d *drinkee.Drink
+}
+
+func newDrinkResolvers(drinks []*drinkee.Drink) []*drinkResolver {
+	resolvers := make([]*drinkResolver, 0, len(drinks))
+	for _, d := range drinks {
+		resolvers = append(resolvers, &drinkResolver{d})
+	}
+	return resolvers
+}
+
+func (r *drinkResolver) ID() graphql.ID       { return graphql.ID(strconv.Itoa(r.d.ID)) }
+func (r *drinkResolver) Name() string         { return r.d.Name }
+func (r *drinkResolver) DisplayName() string  { return r.d.DisplayName }
+func (r *drinkResolver) Slug() string         { return r.d.Slug }
+func (r *drinkResolver) Instructions() string { return r.d.Instructions }
+
+func (r *drinkResolver) Description() *string {
+	if r.d.Description == "" {
+		return nil
+	}
+	return &r.d.Description
+}
+
+func (r *drinkResolver) Glass() *string   { return optional(r.d.Glass) }
+func (r *drinkResolver) Method() *string  { return optional(r.d.Method) }
+func (r *drinkResolver) Ice() *string     { return optional(r.d.Ice) }
+func (r *drinkResolver) Garnish() *string { return optional(r.d.Garnish) }
+func (r *drinkResolver) Origin() *string  { return optional(r.d.Origin) }
+func (r *drinkResolver) Creator() *string { return optional(r.d.Creator) }
+
+func (r *drinkResolver) Year() *int32 {
+	if r.d.Year == 0 {
+		return nil
+	}
+	year := int32(r.d.Year)
+	return &year
+}
+
+func (r *drinkResolver) Rating() *float64 {
+	if r.d.Rating == 0 {
+		return nil
+	}
+	return &r.d.Rating
+}
+
+// optional maps the zero value of an optional field to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (r *drinkResolver) DrinkIngredients() []*drinkIngredientResolver {
+	return newDrinkIngredientResolvers(r.d.DrinkIngredients)
+}
+
+func (r *drinkResolver) Image() *imageResolver {
+	if r.d.Image == nil {
+		return nil
+	}
+	return &imageResolver{r.d.Image}
+}
+
+func (r *drinkResolver) Stats() *statsResolver {
+	if r.d.Stats == nil {
+		return nil
+	}
+	return &statsResolver{r.d.Stats}
+}
+
+type statsResolver struct {
+	s *drinkee.DrinkStats
+}
+
+func (r *statsResolver) VolumeMl() float64       { return r.s.Volume }
+func (r *statsResolver) Abv() float64            { return r.s.ABV }
+func (r *statsResolver) StandardDrinks() float64 { return r.s.StandardDrinks }
+func (r *statsResolver) Calories() float64       { return r.s.Calories }
+
+func (r *statsResolver) Unmeasured() []string {
+	if r.s.Unmeasured == nil {
+		return []string{}
+	}
+	return r.s.Unmeasured
+}
+
+type imageResolver struct {
+	img *drinkee.Image
+}
+
+func (r *imageResolver) URL() string         { return r.img.URL }
+func (r *imageResolver) ContentType() string { return r.img.ContentType }
+func (r *imageResolver) Width() int32        { return int32(r.img.Width) }
+func (r *imageResolver) Height() int32       { return int32(r.img.Height) }
+
+func (r *imageResolver) Thumbnail(args struct{ Size string }) *string {
+	url, ok := r.img.Thumbnails[args.Size]
+	if !ok {
+		return nil
+	}
+	return &url
+}
+
+type drinkIngredientResolver struct {
+	di *drinkee.DrinkIngredient
+}
+
+func newDrinkIngredientResolvers(ingredients drinkee.DrinkIngredientSlice) []*drinkIngredientResolver {
+	resolvers := make([]*drinkIngredientResolver, 0, len(ingredients))
+	for i := range ingredients {
+		resolvers = append(resolvers, &drinkIngredientResolver{&ingredients[i]})
+	}
+	return resolvers
+}
+
+func (r *drinkIngredientResolver) Name() string        { return r.di.Name }
+func (r *drinkIngredientResolver) DisplayName() string { return r.di.DisplayName }
+func (r *drinkIngredientResolver) Measurement() string { return r.di.Measurement }
+func (r *drinkIngredientResolver) Optional() bool      { return r.di.Optional }
+func (r *drinkIngredientResolver) Role() *string       { return optional(r.di.Role) }
+
+func (r *drinkIngredientResolver) Quantity() *quantityResolver {
+	if r.di.Quantity == nil {
+		return nil
+	}
+	return &quantityResolver{r.di.Quantity}
+}
+
+type quantityResolver struct {
+	q *drinkee.Quantity
+}
+
+func (r *quantityResolver) Amount() float64 { return r.q.Amount }
+func (r *quantityResolver) Unit() *string   { return optional(r.q.Unit) }
+func (r *quantityResolver) Ml() *float64    { return r.q.ML }
+
+func (r *drinkIngredientResolver) Ingredient(ctx context.Context) (*ingredientResolver, error) {
+	ingredient, err := loadersFrom(ctx).ingredientByName.Load(ctx, r.di.Name)()
+	if err != nil || ingredient == nil {
+		return nil, err
+	}
+	return &ingredientResolver{ingredient}, nil
+}
+
+type ingredientResolver struct {
+	i *drinkee.Ingredient
+}
+
+func (r *ingredientResolver) ID() graphql.ID             { return graphql.ID(strconv.Itoa(r.i.ID)) }
+func (r *ingredientResolver) Name() string               { return r.i.Name }
+func (r *ingredientResolver) DisplayName() string        { return r.i.DisplayName }
+func (r *ingredientResolver) Slug() string               { return r.i.Slug }
+func (r *ingredientResolver) Abv() float64               { return r.i.ABV }
+func (r *ingredientResolver) SugarPer100ml() float64     { return r.i.Sugar }
+func (r *ingredientResolver) CaloriesPer100ml() *float64 { return r.i.Calories }
+
+func (r *ingredientResolver) Category() string {
+	if r.i.Category == "" {
+		return drinkee.CategoryOther
+	}
+	return r.i.Category
+}
+
+func (r *ingredientResolver) UnitMl() *float64 {
+	if r.i.UnitML == 0 {
+		return nil
+	}
+	return &r.i.UnitML
+}
+
+func (r *ingredientResolver) Drinks(ctx context.Context) ([]*drinkResolver, error) {
+	drinks, err := loadersFrom(ctx).drinksByIngredient.Load(ctx, r.i.Name)()
+	if err != nil {
+		return nil, err
+	}
+	return newDrinkResolvers(drinks), nil
+}
+
+type nonStrictDrinkResolver struct {
+	d *drinkee.NonStrictDrink
+}
+
+func (r *nonStrictDrinkResolver) Drink() *drinkResolver {
+	return &drinkResolver{&drinkee.Drink{
+		ID:               r.d.ID,
+		Name:             r.d.Name,
+		DisplayName:      r.d.DisplayName,
+		Slug:             r.d.Slug,
+		Description:      r.d.Description,
+		Instructions:     r.d.Instructions,
+		DrinkIngredients: r.d.DrinkIngredients,
+		Image:            r.d.Image,
+		Stats:            r.d.Stats,
+		DrinkMetadata:    r.d.DrinkMetadata,
+	}}
+}
+
+func (r *nonStrictDrinkResolver) MissingIngredientCount() int32 {
+	return int32(r.d.MissingIngredientCount)
+}
+
+func (r *nonStrictDrinkResolver) HaveIngredientCount() int32 {
+	return int32(r.d.HaveIngredientCount)
+}
+
+func (r *nonStrictDrinkResolver) Coverage() float64 { return r.d.Coverage }
+
+func (r *nonStrictDrinkResolver) MatchedIngredients() []*drinkIngredientResolver {
+	return newDrinkIngredientResolvers(r.d.MatchedIngredients)
+}
+
+func (r *nonStrictDrinkResolver) MissingIngredients() []*drinkIngredientResolver {
+	return newDrinkIngredientResolvers(r.d.MissingIngredients)
+}
+
+func ingredientRefs(ids []graphql.ID) ([]drinkee.Ingredient, error) {
+	ingredients := make([]drinkee.Ingredient, 0, len(ids))
+	for _, id := range ids {
+		n, err := strconv.Atoi(string(id))
+		if err != nil {
+			return nil, errors.New("invalid ingredient id " + string(id))
+		}
+		ingredients = append(ingredients, drinkee.Ingredient{ID: n})
+	}
+	return ingredients, nil
+}
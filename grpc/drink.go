@@ -0,0 +1,310 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/grpc/drinkeepb"
+	"github.com/dylanconnolly/drinkee/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type drinkServiceServer struct {
+	drinkeepb.UnimplementedDrinkServiceServer
+	s *Server
+}
+
+func (d *drinkServiceServer) GetDrink(ctx context.Context, req *drinkeepb.GetDrinkRequest) (*drinkeepb.Drink, error) {
+	drink, err := d.s.DrinkService.FindDrinkByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return marshalDrink(drink), nil
+}
+
+func (d *drinkServiceServer) ListDrinks(ctx context.Context, req *drinkeepb.DrinkFilter) (*drinkeepb.ListDrinksResponse, error) {
+	f := drinkee.DrinkFilter{
+		Limit: int(req.GetLimit()),
+		Skip:  int(req.GetSkip()),
+		Name:  req.Name,
+
+		Glass:   req.Glass,
+		Method:  req.Method,
+		Ice:     req.Ice,
+		Garnish: req.Garnish,
+		Origin:  req.Origin,
+		Creator: req.Creator,
+
+		MinABV:      req.MinAbv,
+		MaxABV:      req.MaxAbv,
+		MaxCalories: req.MaxCalories,
+	}
+	if f.Limit <= 0 {
+		f.Limit = 100
+	}
+	if req.Id != nil {
+		id := int(req.GetId())
+		f.ID = &id
+	}
+	if req.Year != nil {
+		year := int(req.GetYear())
+		f.Year = &year
+	}
+
+	drinks, err := d.s.DrinkService.FindDrinks(ctx, f)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &drinkeepb.ListDrinksResponse{Drinks: make([]*drinkeepb.Drink, 0, len(drinks))}
+	for _, drink := range drinks {
+		resp.Drinks = append(resp.Drinks, marshalDrink(drink))
+	}
+	return resp, nil
+}
+
+func (d *drinkServiceServer) CreateDrink(ctx context.Context, req *drinkeepb.CreateDrinkRequest) (*drinkeepb.CreateDrinkResponse, error) {
+	if req.GetName() == "" || req.GetDisplayName() == "" || req.GetInstructions() == "" || len(req.GetDrinkIngredients()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "name, display_name, instructions and drink_ingredients are required")
+	}
+
+	cd := &drinkee.CreateDrink{
+		Name:         req.GetName(),
+		DisplayName:  req.GetDisplayName(),
+		Description:  req.GetDescription(),
+		Instructions: req.GetInstructions(),
+		DrinkMetadata: drinkee.DrinkMetadata{
+			Glass:   req.GetGlass(),
+			Method:  req.GetMethod(),
+			Ice:     req.GetIce(),
+			Garnish: req.GetGarnish(),
+			Origin:  req.GetOrigin(),
+			Creator: req.GetCreator(),
+			Year:    int(req.GetYear()),
+			Rating:  req.GetRating(),
+		},
+	}
+	for _, di := range req.GetDrinkIngredients() {
+		cd.DrinkIngredients = append(cd.DrinkIngredients, drinkee.DrinkIngredient{
+			Name:        di.GetName(),
+			Measurement: di.GetMeasurement(),
+			Optional:    di.GetOptional(),
+			Role:        di.GetRole(),
+		})
+	}
+
+	drink, err := d.s.DrinkService.CreateDrink(ctx, cd)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &drinkeepb.CreateDrinkResponse{Drink: marshalDrink(drink)}, nil
+}
+
+func (d *drinkServiceServer) ListIngredients(ctx context.Context, req *drinkeepb.ListIngredientsRequest) (*drinkeepb.ListIngredientsResponse, error) {
+	ingredients, err := d.s.DrinkService.FindIngredients(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &drinkeepb.ListIngredientsResponse{Ingredients: make([]*drinkeepb.Ingredient, 0, len(ingredients))}
+	for _, i := range ingredients {
+		resp.Ingredients = append(resp.Ingredients, marshalIngredient(i))
+	}
+	return resp, nil
+}
+
+func (d *drinkServiceServer) GenerateDrinks(ctx context.Context, req *drinkeepb.GenerateDrinksRequest) (*drinkeepb.GenerateDrinksResponse, error) {
+	metrics.GenerateRequests.WithLabelValues("strict").Inc()
+
+	drinks, err := d.s.DrinkService.GenerateDrinks(ctx, unmarshalIngredients(req.GetIngredients()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &drinkeepb.GenerateDrinksResponse{Drinks: make([]*drinkeepb.Drink, 0, len(drinks))}
+	for _, drink := range drinks {
+		resp.Drinks = append(resp.Drinks, marshalDrink(drink))
+	}
+	return resp, nil
+}
+
+func (d *drinkServiceServer) GenerateNonStrictDrinks(ctx context.Context, req *drinkeepb.GenerateDrinksRequest) (*drinkeepb.GenerateNonStrictDrinksResponse, error) {
+	metrics.GenerateRequests.WithLabelValues("non_strict").Inc()
+
+	drinks, err := d.s.DrinkService.GenerateNonStrictDrinks(ctx, unmarshalIngredients(req.GetIngredients()), unmarshalGenerateOptions(req))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &drinkeepb.GenerateNonStrictDrinksResponse{Drinks: make([]*drinkeepb.NonStrictDrink, 0, len(drinks))}
+	for _, drink := range drinks {
+		resp.Drinks = append(resp.Drinks, marshalNonStrictDrink(drink))
+	}
+	return resp, nil
+}
+
+func (d *drinkServiceServer) StreamNonStrictDrinks(req *drinkeepb.GenerateDrinksRequest, stream drinkeepb.DrinkService_StreamNonStrictDrinksServer) error {
+	metrics.GenerateRequests.WithLabelValues("non_strict").Inc()
+
+	drinks, err := d.s.DrinkService.GenerateNonStrictDrinks(stream.Context(), unmarshalIngredients(req.GetIngredients()), unmarshalGenerateOptions(req))
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for _, drink := range drinks {
+		if err := stream.Send(marshalNonStrictDrink(drink)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toStatus maps service errors onto gRPC status codes.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, drinkee.ErrInvalidDrink), errors.Is(err, drinkee.ErrInvalidGenerateOptions), errors.Is(err, drinkee.ErrAmbiguousIngredient),
+		errors.Is(err, drinkee.ErrUnknownIngredient):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, drinkee.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func marshalDrink(d *drinkee.Drink) *drinkeepb.Drink {
+	pb := &drinkeepb.Drink{
+		Id:           int64(d.ID),
+		Name:         d.Name,
+		DisplayName:  d.DisplayName,
+		Description:  d.Description,
+		Instructions: d.Instructions,
+		Glass:        d.Glass,
+		Method:       d.Method,
+		Ice:          d.Ice,
+		Garnish:      d.Garnish,
+		Origin:       d.Origin,
+		Creator:      d.Creator,
+		Year:         int32(d.Year),
+		Rating:       d.Rating,
+		Slug:         d.Slug,
+	}
+	pb.DrinkIngredients = marshalDrinkIngredients(d.DrinkIngredients)
+	if d.Image != nil {
+		pb.Image = &drinkeepb.Image{
+			Url:         d.Image.URL,
+			ContentType: d.Image.ContentType,
+			Width:       int32(d.Image.Width),
+			Height:      int32(d.Image.Height),
+			Thumbnails:  d.Image.Thumbnails,
+		}
+	}
+	if s := d.Stats; s != nil {
+		pb.Stats = &drinkeepb.DrinkStats{
+			VolumeMl:       s.Volume,
+			Abv:            s.ABV,
+			StandardDrinks: s.StandardDrinks,
+			Calories:       s.Calories,
+			Unmeasured:     s.Unmeasured,
+		}
+	}
+	return pb
+}
+
+func marshalNonStrictDrink(d *drinkee.NonStrictDrink) *drinkeepb.NonStrictDrink {
+	return &drinkeepb.NonStrictDrink{
+		Drink: marshalDrink(&drinkee.Drink{
+			ID:               d.ID,
+			Name:             d.Name,
+			DisplayName:      d.DisplayName,
+			Slug:             d.Slug,
+			Description:      d.Description,
+			Instructions:     d.Instructions,
+			DrinkIngredients: d.DrinkIngredients,
+			Image:            d.Image,
+			Stats:            d.Stats,
+			DrinkMetadata:    d.DrinkMetadata,
+		}),
+		MissingIngredientCount: int32(d.MissingIngredientCount),
+		HaveIngredientCount:    int32(d.HaveIngredientCount),
+		Coverage:               d.Coverage,
+		MatchedIngredients:     marshalDrinkIngredients(d.MatchedIngredients),
+		MissingIngredients:     marshalDrinkIngredients(d.MissingIngredients),
+	}
+}
+
+func marshalDrinkIngredients(dis []drinkee.DrinkIngredient) []*drinkeepb.DrinkIngredient {
+	var pbs []*drinkeepb.DrinkIngredient
+	for _, di := range dis {
+		pdi := &drinkeepb.DrinkIngredient{
+			Name:        di.Name,
+			DisplayName: di.DisplayName,
+			Measurement: di.Measurement,
+			Optional:    di.Optional,
+			Role:        di.Role,
+		}
+		if q := di.Quantity; q != nil {
+			pdi.Quantity = &drinkeepb.Quantity{Amount: q.Amount, Unit: q.Unit, Ml: q.ML}
+		}
+		pbs = append(pbs, pdi)
+	}
+	return pbs
+}
+
+func marshalIngredient(i *drinkee.Ingredient) *drinkeepb.Ingredient {
+	return &drinkeepb.Ingredient{
+		Id:                int64(i.ID),
+		Name:              i.Name,
+		DisplayName:       i.DisplayName,
+		Abv:               i.ABV,
+		SugarPer_100Ml:    i.Sugar,
+		CaloriesPer_100Ml: i.Calories,
+		UnitMl:            i.UnitML,
+		Category:          i.Category,
+		Slug:              i.Slug,
+	}
+}
+
+func unmarshalIngredients(pbs []*drinkeepb.Ingredient) []drinkee.Ingredient {
+	ingredients := make([]drinkee.Ingredient, 0, len(pbs))
+	for _, pb := range pbs {
+		ingredients = append(ingredients, drinkee.Ingredient{
+			ID:          int(pb.GetId()),
+			Name:        pb.GetName(),
+			DisplayName: pb.GetDisplayName(),
+		})
+	}
+	return ingredients
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func unmarshalGenerateOptions(req *drinkeepb.GenerateDrinksRequest) drinkee.GenerateOptions {
+	opts := drinkee.GenerateOptions{
+		MinCoverage: req.GetMinCoverage(),
+		Rank:        req.GetRank(),
+		Limit:       int(req.GetLimit()),
+		Skip:        int(req.GetSkip()),
+	}
+	if req.MaxMissing != nil {
+		maxMissing := int(req.GetMaxMissing())
+		opts.MaxMissing = &maxMissing
+	}
+	return opts
+}
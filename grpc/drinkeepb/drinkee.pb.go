@@ -0,0 +1,2093 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: drinkee/v1/drinkee.proto
+
+package drinkeepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Ingredient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName    string  `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Abv            float64 `protobuf:"fixed64,4,opt,name=abv,proto3" json:"abv,omitempty"`
+	SugarPer_100Ml float64 `protobuf:"fixed64,5,opt,name=sugar_per_100ml,json=sugarPer100ml,proto3" json:"sugar_per_100ml,omitempty"`
+	// calories_per_100ml is unset when worked out from abv and sugar.
+	CaloriesPer_100Ml *float64 `protobuf:"fixed64,6,opt,name=calories_per_100ml,json=caloriesPer100ml,proto3,oneof" json:"calories_per_100ml,omitempty"`
+	// unit_ml is the volume of one of a counted ingredient, 0 when unknown.
+	UnitMl float64 `protobuf:"fixed64,7,opt,name=unit_ml,json=unitMl,proto3" json:"unit_ml,omitempty"`
+	// category is one of spirit, liqueur, wine, beer, bitters, syrup, juice,
+	// mixer, dairy, produce or other.
+	Category string `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+	// slug is unique, derived from display_name.
+	Slug string `protobuf:"bytes,9,opt,name=slug,proto3" json:"slug,omitempty"`
+}
+
+func (x *Ingredient) Reset() {
+	*x = Ingredient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ingredient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ingredient) ProtoMessage() {}
+
+func (x *Ingredient) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ingredient.ProtoReflect.Descriptor instead.
+func (*Ingredient) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ingredient) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Ingredient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Ingredient) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Ingredient) GetAbv() float64 {
+	if x != nil {
+		return x.Abv
+	}
+	return 0
+}
+
+func (x *Ingredient) GetSugarPer_100Ml() float64 {
+	if x != nil {
+		return x.SugarPer_100Ml
+	}
+	return 0
+}
+
+func (x *Ingredient) GetCaloriesPer_100Ml() float64 {
+	if x != nil && x.CaloriesPer_100Ml != nil {
+		return *x.CaloriesPer_100Ml
+	}
+	return 0
+}
+
+func (x *Ingredient) GetUnitMl() float64 {
+	if x != nil {
+		return x.UnitMl
+	}
+	return 0
+}
+
+func (x *Ingredient) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Ingredient) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type DrinkIngredient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Measurement string `protobuf:"bytes,3,opt,name=measurement,proto3" json:"measurement,omitempty"`
+	// quantity is unset when the measurement has no amount, like "top up".
+	Quantity *Quantity `protobuf:"bytes,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// optional ingredients don't keep a drink out of strict generation.
+	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
+	// role is base, modifier or garnish, empty when unknown.
+	Role string `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *DrinkIngredient) Reset() {
+	*x = DrinkIngredient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrinkIngredient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrinkIngredient) ProtoMessage() {}
+
+func (x *DrinkIngredient) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrinkIngredient.ProtoReflect.Descriptor instead.
+func (*DrinkIngredient) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DrinkIngredient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DrinkIngredient) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *DrinkIngredient) GetMeasurement() string {
+	if x != nil {
+		return x.Measurement
+	}
+	return ""
+}
+
+func (x *DrinkIngredient) GetQuantity() *Quantity {
+	if x != nil {
+		return x.Quantity
+	}
+	return nil
+}
+
+func (x *DrinkIngredient) GetOptional() bool {
+	if x != nil {
+		return x.Optional
+	}
+	return false
+}
+
+func (x *DrinkIngredient) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type Quantity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Amount float64 `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	// unit is normalized, e.g. "oz" for "fl oz", and empty for plain counts.
+	Unit string `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	// ml is unset for counts of unknown volume.
+	Ml *float64 `protobuf:"fixed64,3,opt,name=ml,proto3,oneof" json:"ml,omitempty"`
+}
+
+func (x *Quantity) Reset() {
+	*x = Quantity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Quantity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quantity) ProtoMessage() {}
+
+func (x *Quantity) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quantity.ProtoReflect.Descriptor instead.
+func (*Quantity) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Quantity) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Quantity) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Quantity) GetMl() float64 {
+	if x != nil && x.Ml != nil {
+		return *x.Ml
+	}
+	return 0
+}
+
+// DrinkStats are estimates from a drink's measurements, after dilution.
+type DrinkStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// volume_ml includes melted ice.
+	VolumeMl       float64 `protobuf:"fixed64,1,opt,name=volume_ml,json=volumeMl,proto3" json:"volume_ml,omitempty"`
+	Abv            float64 `protobuf:"fixed64,2,opt,name=abv,proto3" json:"abv,omitempty"`
+	StandardDrinks float64 `protobuf:"fixed64,3,opt,name=standard_drinks,json=standardDrinks,proto3" json:"standard_drinks,omitempty"`
+	Calories       float64 `protobuf:"fixed64,4,opt,name=calories,proto3" json:"calories,omitempty"`
+	// unmeasured names ingredients left out for lack of a volume.
+	Unmeasured []string `protobuf:"bytes,5,rep,name=unmeasured,proto3" json:"unmeasured,omitempty"`
+}
+
+func (x *DrinkStats) Reset() {
+	*x = DrinkStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrinkStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrinkStats) ProtoMessage() {}
+
+func (x *DrinkStats) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrinkStats.ProtoReflect.Descriptor instead.
+func (*DrinkStats) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DrinkStats) GetVolumeMl() float64 {
+	if x != nil {
+		return x.VolumeMl
+	}
+	return 0
+}
+
+func (x *DrinkStats) GetAbv() float64 {
+	if x != nil {
+		return x.Abv
+	}
+	return 0
+}
+
+func (x *DrinkStats) GetStandardDrinks() float64 {
+	if x != nil {
+		return x.StandardDrinks
+	}
+	return 0
+}
+
+func (x *DrinkStats) GetCalories() float64 {
+	if x != nil {
+		return x.Calories
+	}
+	return 0
+}
+
+func (x *DrinkStats) GetUnmeasured() []string {
+	if x != nil {
+		return x.Unmeasured
+	}
+	return nil
+}
+
+type Drink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               int64              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name             string             `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName      string             `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Description      string             `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Instructions     string             `protobuf:"bytes,5,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	DrinkIngredients []*DrinkIngredient `protobuf:"bytes,6,rep,name=drink_ingredients,json=drinkIngredients,proto3" json:"drink_ingredients,omitempty"`
+	// image is unset for drinks without one.
+	Image *Image `protobuf:"bytes,7,opt,name=image,proto3" json:"image,omitempty"`
+	// The metadata fields are empty, or 0 for year, when unknown.
+	Glass string `protobuf:"bytes,8,opt,name=glass,proto3" json:"glass,omitempty"`
+	// method is one of shaken, stirred, built, blended or thrown.
+	Method  string `protobuf:"bytes,9,opt,name=method,proto3" json:"method,omitempty"`
+	Ice     string `protobuf:"bytes,10,opt,name=ice,proto3" json:"ice,omitempty"`
+	Garnish string `protobuf:"bytes,11,opt,name=garnish,proto3" json:"garnish,omitempty"`
+	Origin  string `protobuf:"bytes,12,opt,name=origin,proto3" json:"origin,omitempty"`
+	Creator string `protobuf:"bytes,13,opt,name=creator,proto3" json:"creator,omitempty"`
+	Year    int32  `protobuf:"varint,14,opt,name=year,proto3" json:"year,omitempty"`
+	// stats is unset when no ingredient has a known volume.
+	Stats *DrinkStats `protobuf:"bytes,15,opt,name=stats,proto3" json:"stats,omitempty"`
+	// rating is out of 5, 0 when unrated.
+	Rating float64 `protobuf:"fixed64,16,opt,name=rating,proto3" json:"rating,omitempty"`
+	// slug is unique, derived from display_name when the drink is created.
+	Slug string `protobuf:"bytes,17,opt,name=slug,proto3" json:"slug,omitempty"`
+}
+
+func (x *Drink) Reset() {
+	*x = Drink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Drink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Drink) ProtoMessage() {}
+
+func (x *Drink) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Drink.ProtoReflect.Descriptor instead.
+func (*Drink) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Drink) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Drink) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Drink) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Drink) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Drink) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *Drink) GetDrinkIngredients() []*DrinkIngredient {
+	if x != nil {
+		return x.DrinkIngredients
+	}
+	return nil
+}
+
+func (x *Drink) GetImage() *Image {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *Drink) GetGlass() string {
+	if x != nil {
+		return x.Glass
+	}
+	return ""
+}
+
+func (x *Drink) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Drink) GetIce() string {
+	if x != nil {
+		return x.Ice
+	}
+	return ""
+}
+
+func (x *Drink) GetGarnish() string {
+	if x != nil {
+		return x.Garnish
+	}
+	return ""
+}
+
+func (x *Drink) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Drink) GetCreator() string {
+	if x != nil {
+		return x.Creator
+	}
+	return ""
+}
+
+func (x *Drink) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Drink) GetStats() *DrinkStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Drink) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Drink) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type Image struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url         string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Width       int32  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height      int32  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	// thumbnails maps a size name, such as "small", to its URL.
+	Thumbnails map[string]string `protobuf:"bytes,5,rep,name=thumbnails,proto3" json:"thumbnails,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Image) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Image) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Image) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Image) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Image) GetThumbnails() map[string]string {
+	if x != nil {
+		return x.Thumbnails
+	}
+	return nil
+}
+
+type NonStrictDrink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Drink                  *Drink `protobuf:"bytes,1,opt,name=drink,proto3" json:"drink,omitempty"`
+	MissingIngredientCount int32  `protobuf:"varint,2,opt,name=missing_ingredient_count,json=missingIngredientCount,proto3" json:"missing_ingredient_count,omitempty"`
+	HaveIngredientCount    int32  `protobuf:"varint,3,opt,name=have_ingredient_count,json=haveIngredientCount,proto3" json:"have_ingredient_count,omitempty"`
+	// coverage is the share of the drink's ingredients on hand, from 0 to 1.
+	Coverage           float64            `protobuf:"fixed64,4,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	MatchedIngredients []*DrinkIngredient `protobuf:"bytes,5,rep,name=matched_ingredients,json=matchedIngredients,proto3" json:"matched_ingredients,omitempty"`
+	MissingIngredients []*DrinkIngredient `protobuf:"bytes,6,rep,name=missing_ingredients,json=missingIngredients,proto3" json:"missing_ingredients,omitempty"`
+}
+
+func (x *NonStrictDrink) Reset() {
+	*x = NonStrictDrink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NonStrictDrink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NonStrictDrink) ProtoMessage() {}
+
+func (x *NonStrictDrink) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NonStrictDrink.ProtoReflect.Descriptor instead.
+func (*NonStrictDrink) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NonStrictDrink) GetDrink() *Drink {
+	if x != nil {
+		return x.Drink
+	}
+	return nil
+}
+
+func (x *NonStrictDrink) GetMissingIngredientCount() int32 {
+	if x != nil {
+		return x.MissingIngredientCount
+	}
+	return 0
+}
+
+func (x *NonStrictDrink) GetHaveIngredientCount() int32 {
+	if x != nil {
+		return x.HaveIngredientCount
+	}
+	return 0
+}
+
+func (x *NonStrictDrink) GetCoverage() float64 {
+	if x != nil {
+		return x.Coverage
+	}
+	return 0
+}
+
+func (x *NonStrictDrink) GetMatchedIngredients() []*DrinkIngredient {
+	if x != nil {
+		return x.MatchedIngredients
+	}
+	return nil
+}
+
+func (x *NonStrictDrink) GetMissingIngredients() []*DrinkIngredient {
+	if x != nil {
+		return x.MissingIngredients
+	}
+	return nil
+}
+
+type DrinkFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit  int32   `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Skip   int32   `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Name   *string `protobuf:"bytes,3,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Id     *int64  `protobuf:"varint,4,opt,name=id,proto3,oneof" json:"id,omitempty"`
+	Glass  *string `protobuf:"bytes,5,opt,name=glass,proto3,oneof" json:"glass,omitempty"`
+	Method *string `protobuf:"bytes,6,opt,name=method,proto3,oneof" json:"method,omitempty"`
+	Ice    *string `protobuf:"bytes,7,opt,name=ice,proto3,oneof" json:"ice,omitempty"`
+	// garnish, origin and creator match case insensitive substrings.
+	Garnish *string `protobuf:"bytes,8,opt,name=garnish,proto3,oneof" json:"garnish,omitempty"`
+	Origin  *string `protobuf:"bytes,9,opt,name=origin,proto3,oneof" json:"origin,omitempty"`
+	Creator *string `protobuf:"bytes,10,opt,name=creator,proto3,oneof" json:"creator,omitempty"`
+	Year    *int32  `protobuf:"varint,11,opt,name=year,proto3,oneof" json:"year,omitempty"`
+	// min_abv, max_abv and max_calories never match drinks without stats.
+	MinAbv      *float64 `protobuf:"fixed64,12,opt,name=min_abv,json=minAbv,proto3,oneof" json:"min_abv,omitempty"`
+	MaxAbv      *float64 `protobuf:"fixed64,13,opt,name=max_abv,json=maxAbv,proto3,oneof" json:"max_abv,omitempty"`
+	MaxCalories *float64 `protobuf:"fixed64,14,opt,name=max_calories,json=maxCalories,proto3,oneof" json:"max_calories,omitempty"`
+}
+
+func (x *DrinkFilter) Reset() {
+	*x = DrinkFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrinkFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrinkFilter) ProtoMessage() {}
+
+func (x *DrinkFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrinkFilter.ProtoReflect.Descriptor instead.
+func (*DrinkFilter) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DrinkFilter) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *DrinkFilter) GetSkip() int32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *DrinkFilter) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *DrinkFilter) GetGlass() string {
+	if x != nil && x.Glass != nil {
+		return *x.Glass
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetMethod() string {
+	if x != nil && x.Method != nil {
+		return *x.Method
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetIce() string {
+	if x != nil && x.Ice != nil {
+		return *x.Ice
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetGarnish() string {
+	if x != nil && x.Garnish != nil {
+		return *x.Garnish
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetOrigin() string {
+	if x != nil && x.Origin != nil {
+		return *x.Origin
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetCreator() string {
+	if x != nil && x.Creator != nil {
+		return *x.Creator
+	}
+	return ""
+}
+
+func (x *DrinkFilter) GetYear() int32 {
+	if x != nil && x.Year != nil {
+		return *x.Year
+	}
+	return 0
+}
+
+func (x *DrinkFilter) GetMinAbv() float64 {
+	if x != nil && x.MinAbv != nil {
+		return *x.MinAbv
+	}
+	return 0
+}
+
+func (x *DrinkFilter) GetMaxAbv() float64 {
+	if x != nil && x.MaxAbv != nil {
+		return *x.MaxAbv
+	}
+	return 0
+}
+
+func (x *DrinkFilter) GetMaxCalories() float64 {
+	if x != nil && x.MaxCalories != nil {
+		return *x.MaxCalories
+	}
+	return 0
+}
+
+type GetDrinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetDrinkRequest) Reset() {
+	*x = GetDrinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDrinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDrinkRequest) ProtoMessage() {}
+
+func (x *GetDrinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDrinkRequest.ProtoReflect.Descriptor instead.
+func (*GetDrinkRequest) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDrinkRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListDrinksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Drinks []*Drink `protobuf:"bytes,1,rep,name=drinks,proto3" json:"drinks,omitempty"`
+}
+
+func (x *ListDrinksResponse) Reset() {
+	*x = ListDrinksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDrinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDrinksResponse) ProtoMessage() {}
+
+func (x *ListDrinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDrinksResponse.ProtoReflect.Descriptor instead.
+func (*ListDrinksResponse) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDrinksResponse) GetDrinks() []*Drink {
+	if x != nil {
+		return x.Drinks
+	}
+	return nil
+}
+
+type CreateDrinkIngredient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Measurement string `protobuf:"bytes,2,opt,name=measurement,proto3" json:"measurement,omitempty"`
+	// garnishes are always optional.
+	Optional bool   `protobuf:"varint,3,opt,name=optional,proto3" json:"optional,omitempty"`
+	Role     string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *CreateDrinkIngredient) Reset() {
+	*x = CreateDrinkIngredient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateDrinkIngredient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDrinkIngredient) ProtoMessage() {}
+
+func (x *CreateDrinkIngredient) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDrinkIngredient.ProtoReflect.Descriptor instead.
+func (*CreateDrinkIngredient) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateDrinkIngredient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDrinkIngredient) GetMeasurement() string {
+	if x != nil {
+		return x.Measurement
+	}
+	return ""
+}
+
+func (x *CreateDrinkIngredient) GetOptional() bool {
+	if x != nil {
+		return x.Optional
+	}
+	return false
+}
+
+func (x *CreateDrinkIngredient) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type CreateDrinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name             string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName      string                   `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Description      string                   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Instructions     string                   `protobuf:"bytes,4,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	DrinkIngredients []*CreateDrinkIngredient `protobuf:"bytes,5,rep,name=drink_ingredients,json=drinkIngredients,proto3" json:"drink_ingredients,omitempty"`
+	Glass            string                   `protobuf:"bytes,6,opt,name=glass,proto3" json:"glass,omitempty"`
+	Method           string                   `protobuf:"bytes,7,opt,name=method,proto3" json:"method,omitempty"`
+	Ice              string                   `protobuf:"bytes,8,opt,name=ice,proto3" json:"ice,omitempty"`
+	Garnish          string                   `protobuf:"bytes,9,opt,name=garnish,proto3" json:"garnish,omitempty"`
+	Origin           string                   `protobuf:"bytes,10,opt,name=origin,proto3" json:"origin,omitempty"`
+	Creator          string                   `protobuf:"bytes,11,opt,name=creator,proto3" json:"creator,omitempty"`
+	Year             int32                    `protobuf:"varint,12,opt,name=year,proto3" json:"year,omitempty"`
+	Rating           float64                  `protobuf:"fixed64,13,opt,name=rating,proto3" json:"rating,omitempty"`
+}
+
+func (x *CreateDrinkRequest) Reset() {
+	*x = CreateDrinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateDrinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDrinkRequest) ProtoMessage() {}
+
+func (x *CreateDrinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDrinkRequest.ProtoReflect.Descriptor instead.
+func (*CreateDrinkRequest) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateDrinkRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetDrinkIngredients() []*CreateDrinkIngredient {
+	if x != nil {
+		return x.DrinkIngredients
+	}
+	return nil
+}
+
+func (x *CreateDrinkRequest) GetGlass() string {
+	if x != nil {
+		return x.Glass
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetIce() string {
+	if x != nil {
+		return x.Ice
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetGarnish() string {
+	if x != nil {
+		return x.Garnish
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetCreator() string {
+	if x != nil {
+		return x.Creator
+	}
+	return ""
+}
+
+func (x *CreateDrinkRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CreateDrinkRequest) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+type CreateDrinkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Drink *Drink `protobuf:"bytes,1,opt,name=drink,proto3" json:"drink,omitempty"`
+}
+
+func (x *CreateDrinkResponse) Reset() {
+	*x = CreateDrinkResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateDrinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDrinkResponse) ProtoMessage() {}
+
+func (x *CreateDrinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDrinkResponse.ProtoReflect.Descriptor instead.
+func (*CreateDrinkResponse) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateDrinkResponse) GetDrink() *Drink {
+	if x != nil {
+		return x.Drink
+	}
+	return nil
+}
+
+type ListIngredientsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListIngredientsRequest) Reset() {
+	*x = ListIngredientsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListIngredientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIngredientsRequest) ProtoMessage() {}
+
+func (x *ListIngredientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIngredientsRequest.ProtoReflect.Descriptor instead.
+func (*ListIngredientsRequest) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{13}
+}
+
+type ListIngredientsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ingredients []*Ingredient `protobuf:"bytes,1,rep,name=ingredients,proto3" json:"ingredients,omitempty"`
+}
+
+func (x *ListIngredientsResponse) Reset() {
+	*x = ListIngredientsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListIngredientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIngredientsResponse) ProtoMessage() {}
+
+func (x *ListIngredientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIngredientsResponse.ProtoReflect.Descriptor instead.
+func (*ListIngredientsResponse) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListIngredientsResponse) GetIngredients() []*Ingredient {
+	if x != nil {
+		return x.Ingredients
+	}
+	return nil
+}
+
+type GenerateDrinksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ingredients []*Ingredient `protobuf:"bytes,1,rep,name=ingredients,proto3" json:"ingredients,omitempty"`
+	// The rest only apply to non-strict generation. max_missing is unset for
+	// no cap, and rank is one of missing, coverage or rating, missing when
+	// empty. A limit of 0 returns every match.
+	MaxMissing  *int32  `protobuf:"varint,2,opt,name=max_missing,json=maxMissing,proto3,oneof" json:"max_missing,omitempty"`
+	MinCoverage float64 `protobuf:"fixed64,3,opt,name=min_coverage,json=minCoverage,proto3" json:"min_coverage,omitempty"`
+	Rank        string  `protobuf:"bytes,4,opt,name=rank,proto3" json:"rank,omitempty"`
+	Limit       int32   `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Skip        int32   `protobuf:"varint,6,opt,name=skip,proto3" json:"skip,omitempty"`
+}
+
+func (x *GenerateDrinksRequest) Reset() {
+	*x = GenerateDrinksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateDrinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateDrinksRequest) ProtoMessage() {}
+
+func (x *GenerateDrinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateDrinksRequest.ProtoReflect.Descriptor instead.
+func (*GenerateDrinksRequest) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GenerateDrinksRequest) GetIngredients() []*Ingredient {
+	if x != nil {
+		return x.Ingredients
+	}
+	return nil
+}
+
+func (x *GenerateDrinksRequest) GetMaxMissing() int32 {
+	if x != nil && x.MaxMissing != nil {
+		return *x.MaxMissing
+	}
+	return 0
+}
+
+func (x *GenerateDrinksRequest) GetMinCoverage() float64 {
+	if x != nil {
+		return x.MinCoverage
+	}
+	return 0
+}
+
+func (x *GenerateDrinksRequest) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
+func (x *GenerateDrinksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GenerateDrinksRequest) GetSkip() int32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+type GenerateDrinksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Drinks []*Drink `protobuf:"bytes,1,rep,name=drinks,proto3" json:"drinks,omitempty"`
+}
+
+func (x *GenerateDrinksResponse) Reset() {
+	*x = GenerateDrinksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateDrinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateDrinksResponse) ProtoMessage() {}
+
+func (x *GenerateDrinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateDrinksResponse.ProtoReflect.Descriptor instead.
+func (*GenerateDrinksResponse) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GenerateDrinksResponse) GetDrinks() []*Drink {
+	if x != nil {
+		return x.Drinks
+	}
+	return nil
+}
+
+type GenerateNonStrictDrinksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Drinks []*NonStrictDrink `protobuf:"bytes,1,rep,name=drinks,proto3" json:"drinks,omitempty"`
+}
+
+func (x *GenerateNonStrictDrinksResponse) Reset() {
+	*x = GenerateNonStrictDrinksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drinkee_v1_drinkee_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateNonStrictDrinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateNonStrictDrinksResponse) ProtoMessage() {}
+
+func (x *GenerateNonStrictDrinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drinkee_v1_drinkee_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateNonStrictDrinksResponse.ProtoReflect.Descriptor instead.
+func (*GenerateNonStrictDrinksResponse) Descriptor() ([]byte, []int) {
+	return file_drinkee_v1_drinkee_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GenerateNonStrictDrinksResponse) GetDrinks() []*NonStrictDrink {
+	if x != nil {
+		return x.Drinks
+	}
+	return nil
+}
+
+var File_drinkee_v1_drinkee_proto protoreflect.FileDescriptor
+
+var file_drinkee_v1_drinkee_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x72, 0x69,
+	0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x64, 0x72, 0x69, 0x6e,
+	0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x22, 0xa0, 0x02, 0x0a, 0x0a, 0x49, 0x6e, 0x67, 0x72, 0x65,
+	0x64, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73,
+	0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x61, 0x62, 0x76, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x61, 0x62, 0x76, 0x12, 0x26,
+	0x0a, 0x0f, 0x73, 0x75, 0x67, 0x61, 0x72, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x31, 0x30, 0x30, 0x6d,
+	0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x73, 0x75, 0x67, 0x61, 0x72, 0x50, 0x65,
+	0x72, 0x31, 0x30, 0x30, 0x6d, 0x6c, 0x12, 0x31, 0x0a, 0x12, 0x63, 0x61, 0x6c, 0x6f, 0x72, 0x69,
+	0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x31, 0x30, 0x30, 0x6d, 0x6c, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x00, 0x52, 0x10, 0x63, 0x61, 0x6c, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x50, 0x65,
+	0x72, 0x31, 0x30, 0x30, 0x6d, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x6e, 0x69,
+	0x74, 0x5f, 0x6d, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x74,
+	0x4d, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x6c, 0x75, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6c,
+	0x75, 0x67, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x63, 0x61, 0x6c, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f,
+	0x70, 0x65, 0x72, 0x5f, 0x31, 0x30, 0x30, 0x6d, 0x6c, 0x22, 0xcc, 0x01, 0x0a, 0x0f, 0x44, 0x72,
+	0x69, 0x6e, 0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x65, 0x61, 0x73, 0x75,
+	0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b,
+	0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08,
+	0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x52, 0x0a, 0x08, 0x51, 0x75, 0x61, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74,
+	0x12, 0x13, 0x0a, 0x02, 0x6d, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x02,
+	0x6d, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x6d, 0x6c, 0x22, 0xa0, 0x01, 0x0a,
+	0x0a, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x76,
+	0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x6d, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4d, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x62, 0x76, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x61, 0x62, 0x76, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74,
+	0x61, 0x6e, 0x64, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x61, 0x72, 0x64, 0x44, 0x72, 0x69,
+	0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x6c, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x63, 0x61, 0x6c, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x6e, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x64, 0x22,
+	0x81, 0x04, 0x0a, 0x05, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x48, 0x0a, 0x11, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x5f,
+	0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x72, 0x69, 0x6e, 0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x10,
+	0x64, 0x72, 0x69, 0x6e, 0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x27, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x6c, 0x61,
+	0x73, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x6c, 0x61, 0x73, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x63, 0x65, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x69, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x61, 0x72,
+	0x6e, 0x69, 0x73, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x61, 0x72, 0x6e,
+	0x69, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b,
+	0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e,
+	0x67, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x6c, 0x75, 0x67, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73,
+	0x6c, 0x75, 0x67, 0x22, 0xec, 0x01, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x41, 0x0a, 0x0a, 0x74, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61, 0x69,
+	0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61,
+	0x69, 0x6c, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x54, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61, 0x69, 0x6c,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xdf, 0x02, 0x0a, 0x0e, 0x4e, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x44, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x27, 0x0a, 0x05, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x38,
+	0x0a, 0x18, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64,
+	0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x16, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x68, 0x61, 0x76, 0x65,
+	0x5f, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x68, 0x61, 0x76, 0x65, 0x49, 0x6e, 0x67,
+	0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x4c, 0x0a, 0x13, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x64, 0x5f, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65,
+	0x6e, 0x74, 0x52, 0x12, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x49, 0x6e, 0x67, 0x72, 0x65,
+	0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x4c, 0x0a, 0x13, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e,
+	0x67, 0x5f, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74,
+	0x52, 0x12, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0x8e, 0x04, 0x0a, 0x0b, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6b,
+	0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x12, 0x17,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05,
+	0x67, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x05, 0x67,
+	0x6c, 0x61, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x69, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x04, 0x52, 0x03, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x67,
+	0x61, 0x72, 0x6e, 0x69, 0x73, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x05, 0x52, 0x07,
+	0x67, 0x61, 0x72, 0x6e, 0x69, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06, 0x52, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x6f, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x07, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x08, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x1c, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x62, 0x76, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x09, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x41, 0x62, 0x76, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a,
+	0x07, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x62, 0x76, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0a,
+	0x52, 0x06, 0x6d, 0x61, 0x78, 0x41, 0x62, 0x76, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6d,
+	0x61, 0x78, 0x5f, 0x63, 0x61, 0x6c, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x0b, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x43, 0x61, 0x6c, 0x6f, 0x72, 0x69, 0x65, 0x73,
+	0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x05, 0x0a, 0x03,
+	0x5f, 0x69, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x6c, 0x61, 0x73, 0x73, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x69, 0x63, 0x65,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x67, 0x61, 0x72, 0x6e, 0x69, 0x73, 0x68, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x6f, 0x72, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x62, 0x76, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6d, 0x61, 0x78,
+	0x5f, 0x61, 0x62, 0x76, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x61, 0x6c,
+	0x6f, 0x72, 0x69, 0x65, 0x73, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x44, 0x72, 0x69, 0x6e,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3f, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29,
+	0x0a, 0x06, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e,
+	0x6b, 0x52, 0x06, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x22, 0x7d, 0x0a, 0x15, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x65, 0x61,
+	0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x99, 0x03, 0x0a, 0x12, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c,
+	0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x4e, 0x0a, 0x11,
+	0x64, 0x72, 0x69, 0x6e, 0x6b, 0x5f, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b,
+	0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x10, 0x64, 0x72, 0x69, 0x6e,
+	0x6b, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x67, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x6c, 0x61,
+	0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x63,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x69, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x67, 0x61, 0x72, 0x6e, 0x69, 0x73, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67,
+	0x61, 0x72, 0x6e, 0x69, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61,
+	0x74, 0x69, 0x6e, 0x67, 0x22, 0x3e, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x72,
+	0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x64,
+	0x72, 0x69, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x72, 0x69,
+	0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x64,
+	0x72, 0x69, 0x6e, 0x6b, 0x22, 0x18, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x67, 0x72,
+	0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x53,
+	0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x69, 0x6e, 0x67,
+	0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x67, 0x72,
+	0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x22, 0xe8, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a,
+	0x0b, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x69, 0x6e, 0x67, 0x72,
+	0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a,
+	0x6d, 0x61, 0x78, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x72, 0x61, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6b,
+	0x69, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22, 0x43,
+	0x0a, 0x16, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x64, 0x72, 0x69, 0x6e,
+	0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b,
+	0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x06, 0x64, 0x72, 0x69,
+	0x6e, 0x6b, 0x73, 0x22, 0x55, 0x0a, 0x1f, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4e,
+	0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x44, 0x72, 0x69,
+	0x6e, 0x6b, 0x52, 0x06, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x32, 0xdb, 0x04, 0x0a, 0x0c, 0x44,
+	0x72, 0x69, 0x6e, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3a, 0x0a, 0x08, 0x47,
+	0x65, 0x74, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x1b, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x45, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x44,
+	0x72, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x17, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x1e,
+	0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e,
+	0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x1e, 0x2e,
+	0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a,
+	0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x22, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x64, 0x69, 0x65, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x0e, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x21, 0x2e, 0x64,
+	0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e,
+	0x65, 0x72, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x17, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4e,
+	0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x21,
+	0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x2b, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58,
+	0x0a, 0x15, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4e, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63,
+	0x74, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x21, 0x2e, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x44, 0x72, 0x69,
+	0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x72, 0x69,
+	0x6e, 0x6b, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x69, 0x63,
+	0x74, 0x44, 0x72, 0x69, 0x6e, 0x6b, 0x30, 0x01, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x79, 0x6c, 0x61, 0x6e, 0x63, 0x6f, 0x6e, 0x6e,
+	0x6f, 0x6c, 0x6c, 0x79, 0x2f, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x2f, 0x64, 0x72, 0x69, 0x6e, 0x6b, 0x65, 0x65, 0x70, 0x62, 0x3b, 0x64, 0x72, 0x69, 0x6e,
+	0x6b, 0x65, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_drinkee_v1_drinkee_proto_rawDescOnce sync.Once
+	file_drinkee_v1_drinkee_proto_rawDescData = file_drinkee_v1_drinkee_proto_rawDesc
+)
+
+func file_drinkee_v1_drinkee_proto_rawDescGZIP() []byte {
+	file_drinkee_v1_drinkee_proto_rawDescOnce.Do(func() {
+		file_drinkee_v1_drinkee_proto_rawDescData = protoimpl.X.CompressGZIP(file_drinkee_v1_drinkee_proto_rawDescData)
+	})
+	return file_drinkee_v1_drinkee_proto_rawDescData
+}
+
+var file_drinkee_v1_drinkee_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_drinkee_v1_drinkee_proto_goTypes = []interface{}{
+	(*Ingredient)(nil),                      // 0: drinkee.v1.Ingredient
+	(*DrinkIngredient)(nil),                 // 1: drinkee.v1.DrinkIngredient
+	(*Quantity)(nil),                        // 2: drinkee.v1.Quantity
+	(*DrinkStats)(nil),                      // 3: drinkee.v1.DrinkStats
+	(*Drink)(nil),                           // 4: drinkee.v1.Drink
+	(*Image)(nil),                           // 5: drinkee.v1.Image
+	(*NonStrictDrink)(nil),                  // 6: drinkee.v1.NonStrictDrink
+	(*DrinkFilter)(nil),                     // 7: drinkee.v1.DrinkFilter
+	(*GetDrinkRequest)(nil),                 // 8: drinkee.v1.GetDrinkRequest
+	(*ListDrinksResponse)(nil),              // 9: drinkee.v1.ListDrinksResponse
+	(*CreateDrinkIngredient)(nil),           // 10: drinkee.v1.CreateDrinkIngredient
+	(*CreateDrinkRequest)(nil),              // 11: drinkee.v1.CreateDrinkRequest
+	(*CreateDrinkResponse)(nil),             // 12: drinkee.v1.CreateDrinkResponse
+	(*ListIngredientsRequest)(nil),          // 13: drinkee.v1.ListIngredientsRequest
+	(*ListIngredientsResponse)(nil),         // 14: drinkee.v1.ListIngredientsResponse
+	(*GenerateDrinksRequest)(nil),           // 15: drinkee.v1.GenerateDrinksRequest
+	(*GenerateDrinksResponse)(nil),          // 16: drinkee.v1.GenerateDrinksResponse
+	(*GenerateNonStrictDrinksResponse)(nil), // 17: drinkee.v1.GenerateNonStrictDrinksResponse
+	nil,                                     // 18: drinkee.v1.Image.ThumbnailsEntry
+}
+var file_drinkee_v1_drinkee_proto_depIdxs = []int32{
+	2,  // 0: drinkee.v1.DrinkIngredient.quantity:type_name -> drinkee.v1.Quantity
+	1,  // 1: drinkee.v1.Drink.drink_ingredients:type_name -> drinkee.v1.DrinkIngredient
+	5,  // 2: drinkee.v1.Drink.image:type_name -> drinkee.v1.Image
+	3,  // 3: drinkee.v1.Drink.stats:type_name -> drinkee.v1.DrinkStats
+	18, // 4: drinkee.v1.Image.thumbnails:type_name -> drinkee.v1.Image.ThumbnailsEntry
+	4,  // 5: drinkee.v1.NonStrictDrink.drink:type_name -> drinkee.v1.Drink
+	1,  // 6: drinkee.v1.NonStrictDrink.matched_ingredients:type_name -> drinkee.v1.DrinkIngredient
+	1,  // 7: drinkee.v1.NonStrictDrink.missing_ingredients:type_name -> drinkee.v1.DrinkIngredient
+	4,  // 8: drinkee.v1.ListDrinksResponse.drinks:type_name -> drinkee.v1.Drink
+	10, // 9: drinkee.v1.CreateDrinkRequest.drink_ingredients:type_name -> drinkee.v1.CreateDrinkIngredient
+	4,  // 10: drinkee.v1.CreateDrinkResponse.drink:type_name -> drinkee.v1.Drink
+	0,  // 11: drinkee.v1.ListIngredientsResponse.ingredients:type_name -> drinkee.v1.Ingredient
+	0,  // 12: drinkee.v1.GenerateDrinksRequest.ingredients:type_name -> drinkee.v1.Ingredient
+	4,  // 13: drinkee.v1.GenerateDrinksResponse.drinks:type_name -> drinkee.v1.Drink
+	6,  // 14: drinkee.v1.GenerateNonStrictDrinksResponse.drinks:type_name -> drinkee.v1.NonStrictDrink
+	8,  // 15: drinkee.v1.DrinkService.GetDrink:input_type -> drinkee.v1.GetDrinkRequest
+	7,  // 16: drinkee.v1.DrinkService.ListDrinks:input_type -> drinkee.v1.DrinkFilter
+	11, // 17: drinkee.v1.DrinkService.CreateDrink:input_type -> drinkee.v1.CreateDrinkRequest
+	13, // 18: drinkee.v1.DrinkService.ListIngredients:input_type -> drinkee.v1.ListIngredientsRequest
+	15, // 19: drinkee.v1.DrinkService.GenerateDrinks:input_type -> drinkee.v1.GenerateDrinksRequest
+	15, // 20: drinkee.v1.DrinkService.GenerateNonStrictDrinks:input_type -> drinkee.v1.GenerateDrinksRequest
+	15, // 21: drinkee.v1.DrinkService.StreamNonStrictDrinks:input_type -> drinkee.v1.GenerateDrinksRequest
+	4,  // 22: drinkee.v1.DrinkService.GetDrink:output_type -> drinkee.v1.Drink
+	9,  // 23: drinkee.v1.DrinkService.ListDrinks:output_type -> drinkee.v1.ListDrinksResponse
+	12, // 24: drinkee.v1.DrinkService.CreateDrink:output_type -> drinkee.v1.CreateDrinkResponse
+	14, // 25: drinkee.v1.DrinkService.ListIngredients:output_type -> drinkee.v1.ListIngredientsResponse
+	16, // 26: drinkee.v1.DrinkService.GenerateDrinks:output_type -> drinkee.v1.GenerateDrinksResponse
+	17, // 27: drinkee.v1.DrinkService.GenerateNonStrictDrinks:output_type -> drinkee.v1.GenerateNonStrictDrinksResponse
+	6,  // 28: drinkee.v1.DrinkService.StreamNonStrictDrinks:output_type -> drinkee.v1.NonStrictDrink
+	22, // [22:29] is the sub-list for method output_type
+	15, // [15:22] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_drinkee_v1_drinkee_proto_init() }
+func file_drinkee_v1_drinkee_proto_init() {
+	if File_drinkee_v1_drinkee_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_drinkee_v1_drinkee_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ingredient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrinkIngredient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Quantity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrinkStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Drink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Image); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NonStrictDrink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrinkFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDrinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDrinksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateDrinkIngredient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateDrinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateDrinkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListIngredientsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListIngredientsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateDrinksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateDrinksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drinkee_v1_drinkee_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateNonStrictDrinksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_drinkee_v1_drinkee_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_drinkee_v1_drinkee_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	file_drinkee_v1_drinkee_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_drinkee_v1_drinkee_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_drinkee_v1_drinkee_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_drinkee_v1_drinkee_proto_goTypes,
+		DependencyIndexes: file_drinkee_v1_drinkee_proto_depIdxs,
+		MessageInfos:      file_drinkee_v1_drinkee_proto_msgTypes,
+	}.Build()
+	File_drinkee_v1_drinkee_proto = out.File
+	file_drinkee_v1_drinkee_proto_rawDesc = nil
+	file_drinkee_v1_drinkee_proto_goTypes = nil
+	file_drinkee_v1_drinkee_proto_depIdxs = nil
+}
@@ -0,0 +1,369 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: drinkee/v1/drinkee.proto
+
+package drinkeepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DrinkService_GetDrink_FullMethodName                = "/drinkee.v1.DrinkService/GetDrink"
+	DrinkService_ListDrinks_FullMethodName              = "/drinkee.v1.DrinkService/ListDrinks"
+	DrinkService_CreateDrink_FullMethodName             = "/drinkee.v1.DrinkService/CreateDrink"
+	DrinkService_ListIngredients_FullMethodName         = "/drinkee.v1.DrinkService/ListIngredients"
+	DrinkService_GenerateDrinks_FullMethodName          = "/drinkee.v1.DrinkService/GenerateDrinks"
+	DrinkService_GenerateNonStrictDrinks_FullMethodName = "/drinkee.v1.DrinkService/GenerateNonStrictDrinks"
+	DrinkService_StreamNonStrictDrinks_FullMethodName   = "/drinkee.v1.DrinkService/StreamNonStrictDrinks"
+)
+
+// DrinkServiceClient is the client API for DrinkService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DrinkServiceClient interface {
+	GetDrink(ctx context.Context, in *GetDrinkRequest, opts ...grpc.CallOption) (*Drink, error)
+	ListDrinks(ctx context.Context, in *DrinkFilter, opts ...grpc.CallOption) (*ListDrinksResponse, error)
+	CreateDrink(ctx context.Context, in *CreateDrinkRequest, opts ...grpc.CallOption) (*CreateDrinkResponse, error)
+	ListIngredients(ctx context.Context, in *ListIngredientsRequest, opts ...grpc.CallOption) (*ListIngredientsResponse, error)
+	// GenerateDrinks returns drinks that can be made from only the given ingredients.
+	GenerateDrinks(ctx context.Context, in *GenerateDrinksRequest, opts ...grpc.CallOption) (*GenerateDrinksResponse, error)
+	// GenerateNonStrictDrinks returns drinks using at least one of the given
+	// ingredients, fewest missing ingredients first.
+	GenerateNonStrictDrinks(ctx context.Context, in *GenerateDrinksRequest, opts ...grpc.CallOption) (*GenerateNonStrictDrinksResponse, error)
+	// StreamNonStrictDrinks is GenerateNonStrictDrinks delivered one drink at a
+	// time, best match first, so callers can stop reading early.
+	StreamNonStrictDrinks(ctx context.Context, in *GenerateDrinksRequest, opts ...grpc.CallOption) (DrinkService_StreamNonStrictDrinksClient, error)
+}
+
+type drinkServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDrinkServiceClient(cc grpc.ClientConnInterface) DrinkServiceClient {
+	return &drinkServiceClient{cc}
+}
+
+func (c *drinkServiceClient) GetDrink(ctx context.Context, in *GetDrinkRequest, opts ...grpc.CallOption) (*Drink, error) {
+	out := new(Drink)
+	err := c.cc.Invoke(ctx, DrinkService_GetDrink_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drinkServiceClient) ListDrinks(ctx context.Context, in *DrinkFilter, opts ...grpc.CallOption) (*ListDrinksResponse, error) {
+	out := new(ListDrinksResponse)
+	err := c.cc.Invoke(ctx, DrinkService_ListDrinks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drinkServiceClient) CreateDrink(ctx context.Context, in *CreateDrinkRequest, opts ...grpc.CallOption) (*CreateDrinkResponse, error) {
+	out := new(CreateDrinkResponse)
+	err := c.cc.Invoke(ctx, DrinkService_CreateDrink_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drinkServiceClient) ListIngredients(ctx context.Context, in *ListIngredientsRequest, opts ...grpc.CallOption) (*ListIngredientsResponse, error) {
+	out := new(ListIngredientsResponse)
+	err := c.cc.Invoke(ctx, DrinkService_ListIngredients_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drinkServiceClient) GenerateDrinks(ctx context.Context, in *GenerateDrinksRequest, opts ...grpc.CallOption) (*GenerateDrinksResponse, error) {
+	out := new(GenerateDrinksResponse)
+	err := c.cc.Invoke(ctx, DrinkService_GenerateDrinks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drinkServiceClient) GenerateNonStrictDrinks(ctx context.Context, in *GenerateDrinksRequest, opts ...grpc.CallOption) (*GenerateNonStrictDrinksResponse, error) {
+	out := new(GenerateNonStrictDrinksResponse)
+	err := c.cc.Invoke(ctx, DrinkService_GenerateNonStrictDrinks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drinkServiceClient) StreamNonStrictDrinks(ctx context.Context, in *GenerateDrinksRequest, opts ...grpc.CallOption) (DrinkService_StreamNonStrictDrinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DrinkService_ServiceDesc.Streams[0], DrinkService_StreamNonStrictDrinks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &drinkServiceStreamNonStrictDrinksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DrinkService_StreamNonStrictDrinksClient interface {
+	Recv() (*NonStrictDrink, error)
+	grpc.ClientStream
+}
+
+type drinkServiceStreamNonStrictDrinksClient struct {
+	grpc.ClientStream
+}
+
+func (x *drinkServiceStreamNonStrictDrinksClient) Recv() (*NonStrictDrink, error) {
+	m := new(NonStrictDrink)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DrinkServiceServer is the server API for DrinkService service.
+// All implementations must embed UnimplementedDrinkServiceServer
+// for forward compatibility
+type DrinkServiceServer interface {
+	GetDrink(context.Context, *GetDrinkRequest) (*Drink, error)
+	ListDrinks(context.Context, *DrinkFilter) (*ListDrinksResponse, error)
+	CreateDrink(context.Context, *CreateDrinkRequest) (*CreateDrinkResponse, error)
+	ListIngredients(context.Context, *ListIngredientsRequest) (*ListIngredientsResponse, error)
+	// GenerateDrinks returns drinks that can be made from only the given ingredients.
+	GenerateDrinks(context.Context, *GenerateDrinksRequest) (*GenerateDrinksResponse, error)
+	// GenerateNonStrictDrinks returns drinks using at least one of the given
+	// ingredients, fewest missing ingredients first.
+	GenerateNonStrictDrinks(context.Context, *GenerateDrinksRequest) (*GenerateNonStrictDrinksResponse, error)
+	// StreamNonStrictDrinks is GenerateNonStrictDrinks delivered one drink at a
+	// time, best match first, so callers can stop reading early.
+	StreamNonStrictDrinks(*GenerateDrinksRequest, DrinkService_StreamNonStrictDrinksServer) error
+	mustEmbedUnimplementedDrinkServiceServer()
+}
+
+// UnimplementedDrinkServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDrinkServiceServer struct {
+}
+
+func (UnimplementedDrinkServiceServer) GetDrink(context.Context, *GetDrinkRequest) (*Drink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDrink not implemented")
+}
+func (UnimplementedDrinkServiceServer) ListDrinks(context.Context, *DrinkFilter) (*ListDrinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDrinks not implemented")
+}
+func (UnimplementedDrinkServiceServer) CreateDrink(context.Context, *CreateDrinkRequest) (*CreateDrinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDrink not implemented")
+}
+func (UnimplementedDrinkServiceServer) ListIngredients(context.Context, *ListIngredientsRequest) (*ListIngredientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIngredients not implemented")
+}
+func (UnimplementedDrinkServiceServer) GenerateDrinks(context.Context, *GenerateDrinksRequest) (*GenerateDrinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateDrinks not implemented")
+}
+func (UnimplementedDrinkServiceServer) GenerateNonStrictDrinks(context.Context, *GenerateDrinksRequest) (*GenerateNonStrictDrinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateNonStrictDrinks not implemented")
+}
+func (UnimplementedDrinkServiceServer) StreamNonStrictDrinks(*GenerateDrinksRequest, DrinkService_StreamNonStrictDrinksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamNonStrictDrinks not implemented")
+}
+func (UnimplementedDrinkServiceServer) mustEmbedUnimplementedDrinkServiceServer() {}
+
+// UnsafeDrinkServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DrinkServiceServer will
+// result in compilation errors.
+type UnsafeDrinkServiceServer interface {
+	mustEmbedUnimplementedDrinkServiceServer()
+}
+
+func RegisterDrinkServiceServer(s grpc.ServiceRegistrar, srv DrinkServiceServer) {
+	s.RegisterService(&DrinkService_ServiceDesc, srv)
+}
+
+func _DrinkService_GetDrink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDrinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrinkServiceServer).GetDrink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrinkService_GetDrink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrinkServiceServer).GetDrink(ctx, req.(*GetDrinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DrinkService_ListDrinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrinkFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrinkServiceServer).ListDrinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrinkService_ListDrinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrinkServiceServer).ListDrinks(ctx, req.(*DrinkFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DrinkService_CreateDrink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDrinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrinkServiceServer).CreateDrink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrinkService_CreateDrink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrinkServiceServer).CreateDrink(ctx, req.(*CreateDrinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DrinkService_ListIngredients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIngredientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrinkServiceServer).ListIngredients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrinkService_ListIngredients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrinkServiceServer).ListIngredients(ctx, req.(*ListIngredientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DrinkService_GenerateDrinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateDrinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrinkServiceServer).GenerateDrinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrinkService_GenerateDrinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrinkServiceServer).GenerateDrinks(ctx, req.(*GenerateDrinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DrinkService_GenerateNonStrictDrinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateDrinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrinkServiceServer).GenerateNonStrictDrinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrinkService_GenerateNonStrictDrinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrinkServiceServer).GenerateNonStrictDrinks(ctx, req.(*GenerateDrinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DrinkService_StreamNonStrictDrinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateDrinksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DrinkServiceServer).StreamNonStrictDrinks(m, &drinkServiceStreamNonStrictDrinksServer{stream})
+}
+
+type DrinkService_StreamNonStrictDrinksServer interface {
+	Send(*NonStrictDrink) error
+	grpc.ServerStream
+}
+
+type drinkServiceStreamNonStrictDrinksServer struct {
+	grpc.ServerStream
+}
+
+func (x *drinkServiceStreamNonStrictDrinksServer) Send(m *NonStrictDrink) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DrinkService_ServiceDesc is the grpc.ServiceDesc for DrinkService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DrinkService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "drinkee.v1.DrinkService",
+	HandlerType: (*DrinkServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDrink",
+			Handler:    _DrinkService_GetDrink_Handler,
+		},
+		{
+			MethodName: "ListDrinks",
+			Handler:    _DrinkService_ListDrinks_Handler,
+		},
+		{
+			MethodName: "CreateDrink",
+			Handler:    _DrinkService_CreateDrink_Handler,
+		},
+		{
+			MethodName: "ListIngredients",
+			Handler:    _DrinkService_ListIngredients_Handler,
+		},
+		{
+			MethodName: "GenerateDrinks",
+			Handler:    _DrinkService_GenerateDrinks_Handler,
+		},
+		{
+			MethodName: "GenerateNonStrictDrinks",
+			Handler:    _DrinkService_GenerateNonStrictDrinks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamNonStrictDrinks",
+			Handler:       _DrinkService_StreamNonStrictDrinks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "drinkee/v1/drinkee.proto",
+}
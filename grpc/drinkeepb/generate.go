@@ -0,0 +1,4 @@
+// Package drinkeepb holds the code generated from proto/drinkee/v1/drinkee.proto.
+package drinkeepb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/dylanconnolly/drinkee --go-grpc_out=../.. --go-grpc_opt=module=github.com/dylanconnolly/drinkee drinkee/v1/drinkee.proto
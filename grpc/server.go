@@ -0,0 +1,174 @@
+// Package grpc serves drinkee.DrinkService over gRPC alongside the REST API.
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/grpc/drinkeepb"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	requestIDMetadataKey   = "x-request-id"
+	actorMetadataKey       = "x-actor"
+	traceparentMetadataKey = "traceparent"
+)
+
+type Server struct {
+	server *grpc.Server
+	ln     net.Listener
+
+	DrinkService drinkee.DrinkService
+	Logger       logger.Logger
+}
+
+func NewServer() *Server {
+	s := &Server{Logger: logger.NewNop()}
+
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	drinkeepb.RegisterDrinkServiceServer(s.server, &drinkServiceServer{s: s})
+	reflection.Register(s.server)
+
+	return s
+}
+
+// Open starts serving on addr in the background.
+func (s *Server) Open(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+
+	go s.server.Serve(ln)
+	return nil
+}
+
+// Addr is the bound address, useful when Open was given port 0.
+func (s *Server) Addr() net.Addr {
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+// Close stops accepting new RPCs and waits for in-flight ones until ctx is
+// done, after which remaining streams are cut off.
+func (s *Server) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// requestContext gives RPCs the same request ID, trace and request-scoped
+// logger that the gin middleware gives HTTP requests.
+func (s *Server) requestContext(ctx context.Context, method string) (context.Context, *trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := first(md.Get(requestIDMetadataKey))
+	if id == "" || len(id) > 128 {
+		id = newRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, id))
+	ctx = drinkee.NewContextWithRequestID(ctx, id)
+	if actor := strings.TrimSpace(first(md.Get(actorMetadataKey))); actor != "" && len(actor) <= 128 {
+		ctx = drinkee.NewContextWithActor(ctx, actor)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			ctx = drinkee.NewContextWithClientIP(ctx, host)
+		}
+	}
+
+	parent, _ := trace.ParseTraceparent(first(md.Get(traceparentMetadataKey)))
+	ctx, span := trace.StartServer(ctx, method, parent,
+		trace.String("rpc.system", "grpc"),
+		trace.String("rpc.method", method),
+		trace.String("request.id", id),
+	)
+
+	l := s.Logger.With(logger.F("requestId", id), logger.F("traceId", span.SpanContext.TraceID.String()))
+	return logger.NewContext(ctx, l), span
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx, span := s.requestContext(ctx, info.FullMethod)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	s.logRPC(ctx, info.FullMethod, start, err)
+	span.RecordError(err)
+	return resp, err
+}
+
+func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, span := s.requestContext(ss.Context(), info.FullMethod)
+	defer span.End()
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	s.logRPC(ctx, info.FullMethod, start, err)
+	span.RecordError(err)
+	return err
+}
+
+func (s *Server) logRPC(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []logger.Field{
+		logger.F("method", method),
+		logger.F("code", code.String()),
+		logger.F("latencyMs", float64(time.Since(start).Microseconds())/1000),
+	}
+
+	l := logger.FromContext(ctx, s.Logger)
+	switch code {
+	case codes.OK:
+		l.Info("rpc", fields...)
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		l.Error("rpc", append(fields, logger.Err(err))...)
+	default:
+		l.Warn("rpc", append(fields, logger.Err(err))...)
+	}
+}
+
+// serverStream swaps in the request-scoped context built by the interceptor.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
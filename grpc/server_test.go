@@ -0,0 +1,127 @@
+package grpc_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeegrpc "github.com/dylanconnolly/drinkee/grpc"
+	"github.com/dylanconnolly/drinkee/grpc/drinkeepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type stubDrinkService struct {
+	drinkee.DrinkService
+}
+
+func (s *stubDrinkService) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	if id != 1 {
+		return nil, sql.ErrNoRows
+	}
+	return &drinkee.Drink{ID: 1, Name: "negroni", DisplayName: "Negroni"}, nil
+}
+
+func (s *stubDrinkService) GenerateNonStrictDrinks(ctx context.Context, i []drinkee.Ingredient, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
+	if opts.Rank != "" && opts.Rank != drinkee.RankMissing {
+		return nil, fmt.Errorf("%w: unknown rank %q", drinkee.ErrInvalidGenerateOptions, opts.Rank)
+	}
+	drinks := []*drinkee.NonStrictDrink{
+		{ID: 1, Name: "negroni", MissingIngredientCount: 0, HaveIngredientCount: 3, Coverage: 1},
+		{ID: 2, Name: "boulevardier", MissingIngredientCount: 1, HaveIngredientCount: 2, Coverage: 0.6667,
+			MissingIngredients: []drinkee.DrinkIngredient{{Name: "bourbon", DisplayName: "Bourbon", Measurement: "1 1/4 oz"}}},
+	}
+	if opts.MaxMissing != nil {
+		return drinks[:1], nil
+	}
+	return drinks, nil
+}
+
+func startServer(t *testing.T) drinkeepb.DrinkServiceClient {
+	s := drinkeegrpc.NewServer()
+	s.DrinkService = &stubDrinkService{}
+	if err := s.Open("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.Close(ctx)
+	})
+
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return drinkeepb.NewDrinkServiceClient(conn)
+}
+
+func TestGetDrink(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	drink, err := c.GetDrink(ctx, &drinkeepb.GetDrinkRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "Negroni", drink.GetDisplayName())
+
+	_, err = c.GetDrink(ctx, &drinkeepb.GetDrinkRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestStreamNonStrictDrinks(t *testing.T) {
+	c := startServer(t)
+
+	stream, err := c.StreamNonStrictDrinks(context.Background(), &drinkeepb.GenerateDrinksRequest{
+		Ingredients: []*drinkeepb.Ingredient{{Id: 1}, {Id: 2}},
+	})
+	assert.NoError(t, err)
+
+	var names []string
+	for {
+		d, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		names = append(names, d.GetDrink().GetName())
+	}
+
+	assert.Equal(t, []string{"negroni", "boulevardier"}, names)
+}
+
+func TestGenerateNonStrictDrinksOptions(t *testing.T) {
+	c := startServer(t)
+
+	resp, err := c.GenerateNonStrictDrinks(context.Background(), &drinkeepb.GenerateDrinksRequest{
+		Ingredients: []*drinkeepb.Ingredient{{Id: 1}, {Id: 2}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetDrinks(), 2)
+	assert.Equal(t, 0.6667, resp.GetDrinks()[1].GetCoverage())
+	assert.Equal(t, "bourbon", resp.GetDrinks()[1].GetMissingIngredients()[0].GetName())
+
+	maxMissing := int32(0)
+	resp, err = c.GenerateNonStrictDrinks(context.Background(), &drinkeepb.GenerateDrinksRequest{
+		Ingredients: []*drinkeepb.Ingredient{{Id: 1}, {Id: 2}},
+		MaxMissing:  &maxMissing,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetDrinks(), 1)
+
+	_, err = c.GenerateNonStrictDrinks(context.Background(), &drinkeepb.GenerateDrinksRequest{
+		Ingredients: []*drinkeepb.Ingredient{{Id: 1}},
+		Rank:        "popularity",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
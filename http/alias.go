@@ -0,0 +1,65 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleGetIngredientAliases(c *gin.Context) {
+	aliases, err := s.IngredientAliasService.FindIngredientAliases(c)
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting aliases: %s", err))
+		return
+	}
+	if aliases == nil {
+		aliases = []*drinkee.IngredientAlias{}
+	}
+
+	renderData(c, http.StatusOK, aliases, &Meta{Count: len(aliases)})
+}
+
+func (s *Server) handleCreateIngredientAlias(c *gin.Context) {
+	var ca drinkee.CreateIngredientAlias
+	if err := bindJSON(c, &ca); err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("invalid alias: %s", err))
+		return
+	}
+
+	alias, err := s.IngredientAliasService.CreateIngredientAlias(c, &ca)
+	if errors.Is(err, drinkee.ErrInvalidAlias) {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error creating alias: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusCreated, alias, nil)
+}
+
+func (s *Server) handleDeleteIngredientAlias(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid alias id")
+		return
+	}
+
+	err = s.IngredientAliasService.DeleteIngredientAlias(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no alias with id %d", id))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error deleting alias: %s", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
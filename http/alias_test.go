@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type aliases struct {
+	drinkee.IngredientAliasService
+}
+
+func (aliases) CreateIngredientAlias(ctx context.Context, ca *drinkee.CreateIngredientAlias) (*drinkee.IngredientAlias, error) {
+	if ca.Ingredient != "orange juice" {
+		return nil, fmt.Errorf("%w: unknown ingredient %q", drinkee.ErrInvalidAlias, ca.Ingredient)
+	}
+	return &drinkee.IngredientAlias{ID: 1, Alias: ca.Alias, Ingredient: ca.Ingredient, IngredientID: 4}, nil
+}
+
+func (aliases) DeleteIngredientAlias(ctx context.Context, id int) error {
+	if id != 1 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func TestIngredientAliases(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.IngredientAliasService = aliases{}
+
+	w, env := serveV2(s, "POST", "/api/v2/ingredients/aliases", `{"alias": "OJ", "ingredient": "orange juice"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "OJ", env.Data.(map[string]interface{})["alias"])
+
+	w, env = serveV2(s, "POST", "/api/v2/ingredients/aliases", `{"alias": "OJ", "ingredient": "orange squash"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `invalid alias: unknown ingredient "orange squash"`, env.Errors[0].Message)
+
+	w, _ = serveV2(s, "POST", "/api/v2/ingredients/aliases", `{"alias": "OJ"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = serveV2(s, "DELETE", "/api/v2/ingredients/aliases/1", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w, _ = serveV2(s, "DELETE", "/api/v2/ingredients/aliases/2", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ambiguousCatalog can't tell which orange liqueur is meant.
+type ambiguousCatalog struct {
+	drinkee.DrinkService
+}
+
+var errOrangeLiqueur = &drinkee.AmbiguousIngredientError{Name: "orange liqueur", Ingredients: []string{"cointreau", "triple sec"}}
+
+func (ambiguousCatalog) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	return nil, errOrangeLiqueur
+}
+
+func (ambiguousCatalog) GenerateDrinks(ctx context.Context, i []drinkee.Ingredient) ([]*drinkee.Drink, error) {
+	return nil, errOrangeLiqueur
+}
+
+func TestAmbiguousIngredient(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = ambiguousCatalog{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks?ingredients=orange%20liqueur", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "ambiguous_ingredient", env.Errors[0].Code)
+	assert.Equal(t, `ambiguous ingredient "orange liqueur" could be any of cointreau, triple sec`, env.Errors[0].Message)
+
+	w, env = serveV2(s, "POST", "/api/v2/drinks/generate?strict=true", `{"ingredients": [{"name": "orange liqueur"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "ambiguous_ingredient", env.Errors[0].Code)
+
+	w = getDrinks(s, "?ingredients=orange%20liqueur", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// unknownCatalog has never heard of the ingredients asked for.
+type unknownCatalog struct {
+	drinkee.DrinkService
+}
+
+func (unknownCatalog) GenerateNonStrictDrinks(ctx context.Context, i []drinkee.Ingredient, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
+	return nil, &drinkee.UnknownIngredientError{Names: []string{"gni", "campar"}}
+}
+
+func TestUnknownIngredient(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = unknownCatalog{}
+
+	w, env := serveV2(s, "POST", "/api/v2/drinks/generate", `{"ingredients": [{"name": "gni"}, {"name": "campar"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "bad_request", env.Errors[0].Code)
+	assert.Equal(t, `unknown ingredient "gni", "campar"`, env.Errors[0].Message)
+
+	w, _ = serveV2(s, "POST", "/api/v1/generateDrinks", `{"ingredients": [{"name": "gni"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
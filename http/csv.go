@@ -0,0 +1,88 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+var (
+	drinkCSVHeader          = []string{"drinkId", "drinkName", "drinkDisplayName", "ingredientName", "ingredientDisplayName", "measurement"}
+	nonStrictDrinkCSVHeader = append(append([]string{}, drinkCSVHeader...), "missingIngredientCount", "haveIngredientCount")
+	ingredientCSVHeader     = []string{"id", "name", "displayName"}
+	errorCSVHeader          = []string{"code", "message"}
+)
+
+// encodeCSV flattens drinks to one row per drink ingredient. Only catalog
+// types have a tabular form; anything else is not acceptable as CSV. v2
+// envelopes are written as their data, or as their errors on failure.
+func encodeCSV(obj interface{}) ([]byte, error) {
+	var records [][]string
+
+	switch v := obj.(type) {
+	case Envelope:
+		if len(v.Errors) == 0 {
+			return encodeCSV(v.Data)
+		}
+		records = append(records, errorCSVHeader)
+		for _, e := range v.Errors {
+			records = append(records, []string{e.Code, e.Message})
+		}
+	case *drinkee.Drink:
+		records = append(records, drinkCSVHeader)
+		records = append(records, drinkRecords(v)...)
+	case []*drinkee.Drink:
+		records = append(records, drinkCSVHeader)
+		for _, d := range v {
+			records = append(records, drinkRecords(d)...)
+		}
+	case []*drinkee.NonStrictDrink:
+		records = append(records, nonStrictDrinkCSVHeader)
+		for _, d := range v {
+			records = append(records, nonStrictDrinkRecords(d)...)
+		}
+	case []*drinkee.Ingredient:
+		records = append(records, ingredientCSVHeader)
+		for _, i := range v {
+			records = append(records, []string{strconv.Itoa(i.ID), i.Name, i.DisplayName})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T has no CSV representation", errNotAcceptable, obj)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drinkRecords(d *drinkee.Drink) [][]string {
+	return flattenDrink(d.ID, d.Name, d.DisplayName, d.DrinkIngredients)
+}
+
+func nonStrictDrinkRecords(d *drinkee.NonStrictDrink) [][]string {
+	records := flattenDrink(d.ID, d.Name, d.DisplayName, d.DrinkIngredients)
+	for i := range records {
+		records[i] = append(records[i], strconv.Itoa(d.MissingIngredientCount), strconv.Itoa(d.HaveIngredientCount))
+	}
+	return records
+}
+
+// flattenDrink writes a drink without ingredients as a single row with empty
+// ingredient columns so it doesn't disappear from the export.
+func flattenDrink(id int, name, displayName string, ingredients drinkee.DrinkIngredientSlice) [][]string {
+	if len(ingredients) == 0 {
+		return [][]string{{strconv.Itoa(id), name, displayName, "", "", ""}}
+	}
+
+	records := make([][]string, 0, len(ingredients))
+	for _, di := range ingredients {
+		records = append(records, []string{strconv.Itoa(id), name, displayName, di.Name, di.DisplayName, di.Measurement})
+	}
+	return records
+}
@@ -1,11 +1,16 @@
 package http
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/metrics"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,15 +21,19 @@ type IngredientListRequest struct {
 func (s *Server) handleGetDrinks(c *gin.Context) {
 	f := buildFilter(c)
 
-	fmt.Printf("filter binding: %+v", f)
+	logger.FromContext(c, s.Logger).Debug("finding drinks", logger.F("filter", f))
 
 	drinks, err := s.DrinkService.FindDrinks(c, f)
+	if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		c.String(http.StatusBadRequest, "%s", err)
+		return
+	}
 	if err != nil {
 		c.String(http.StatusInternalServerError, "error getting drinks: %s", err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, drinks)
+	renderETag(c, http.StatusOK, drinks)
 }
 
 func (s *Server) handleGetDrinkByID(c *gin.Context) {
@@ -35,23 +44,63 @@ func (s *Server) handleGetDrinkByID(c *gin.Context) {
 	}
 
 	drink, err := s.DrinkService.FindDrinkByID(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.String(http.StatusNotFound, "No drink with id %d", id)
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error fetching drink: %s", err)
+		return
+	}
+
+	drink, err = scaleDrink(c, drink)
+	if err != nil {
+		c.String(http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	renderETag(c, http.StatusOK, drink)
+}
+
+func (s *Server) handleGetDrinkBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	drink, err := s.DrinkService.FindDrinkBySlug(c, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.String(http.StatusNotFound, "No drink with slug %q", slug)
+		return
+	}
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error fetching drink: %s", err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, drink)
+	drink, err = scaleDrink(c, drink)
+	if err != nil {
+		c.String(http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	renderETag(c, http.StatusOK, drink)
 }
 
 func (s *Server) handleCreateDrink(c *gin.Context) {
 	var createDrink drinkee.CreateDrink
 
-	if err := c.ShouldBindJSON(&createDrink); err != nil {
+	if err := bindJSON(c, &createDrink); err != nil {
 		c.String(http.StatusBadRequest, "invalid JSON in request body: %s", err)
 		return
 	}
 
-	err := s.DrinkService.CreateDrink(c, &createDrink)
+	_, err := s.DrinkService.CreateDrink(c, &createDrink)
+	if errors.Is(err, drinkee.ErrInvalidDrink) || errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		c.String(http.StatusBadRequest, "%s", err)
+		return
+	}
+	if errors.Is(err, drinkee.ErrConflict) {
+		c.String(http.StatusConflict, "%s", err)
+		return
+	}
 	if err != nil {
 		c.String(http.StatusInternalServerError, "error creating drink: %s", err)
 		return
@@ -62,9 +111,9 @@ func (s *Server) handleCreateDrink(c *gin.Context) {
 
 func (s *Server) handleGenerateDrinks(c *gin.Context) {
 	var ingredientList IngredientListRequest
-	err := c.ShouldBindJSON(&ingredientList)
+	err := bindJSON(c, &ingredientList)
 	if err != nil {
-		c.String(http.StatusBadRequest, "couldn't bind to list of ingredients", err)
+		c.String(http.StatusBadRequest, "couldn't bind to list of ingredients: %s", err)
 		return
 	}
 
@@ -72,20 +121,36 @@ func (s *Server) handleGenerateDrinks(c *gin.Context) {
 
 	strict := c.Query("strict")
 	if strict == "true" {
+		metrics.GenerateRequests.WithLabelValues("strict").Inc()
 		drinks, err := s.DrinkService.GenerateDrinks(c, ingredients)
+		if errors.Is(err, drinkee.ErrAmbiguousIngredient) || errors.Is(err, drinkee.ErrUnknownIngredient) {
+			c.String(http.StatusBadRequest, "%s", err)
+			return
+		}
 		if err != nil {
 			c.String(http.StatusInternalServerError, "error generating drinks: %s", err)
 			return
 		}
 
-		c.IndentedJSON(http.StatusAccepted, drinks)
+		render(c, http.StatusAccepted, drinks)
+		return
+	}
+	metrics.GenerateRequests.WithLabelValues("non_strict").Inc()
+	opts, err := buildGenerateOptions(c, 0)
+	if err != nil {
+		c.String(http.StatusBadRequest, "%s", err)
+		return
+	}
+	drinks, err := s.DrinkService.GenerateNonStrictDrinks(c, ingredients, opts)
+	if errors.Is(err, drinkee.ErrInvalidGenerateOptions) || errors.Is(err, drinkee.ErrAmbiguousIngredient) || errors.Is(err, drinkee.ErrUnknownIngredient) {
+		c.String(http.StatusBadRequest, "%s", err)
 		return
 	}
-	drinks, err := s.DrinkService.GenerateNonStrictDrinks(c, ingredients)
 	if err != nil {
 		c.String(http.StatusInternalServerError, "error generating non strict drinks: %s", err)
+		return
 	}
-	c.IndentedJSON(http.StatusAccepted, drinks)
+	render(c, http.StatusAccepted, drinks)
 }
 
 func (s *Server) handleGetIngredients(c *gin.Context) {
@@ -95,7 +160,7 @@ func (s *Server) handleGetIngredients(c *gin.Context) {
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, ingredients)
+	renderETag(c, http.StatusOK, ingredients)
 }
 
 func buildFilter(c *gin.Context) drinkee.DrinkFilter {
@@ -122,5 +187,78 @@ func buildFilter(c *gin.Context) drinkee.DrinkFilter {
 		ID:    f.ID,
 	}
 
+	// query parameters take precedence over the legacy JSON body
+	if name := c.Query("name"); name != "" {
+		filter.Name = &name
+	}
+	if id, err := strconv.Atoi(c.Query("id")); err == nil {
+		filter.ID = &id
+	}
+	if ingredients := c.Query("ingredients"); ingredients != "" {
+		filter.Ingredients = strings.Split(ingredients, ",")
+	}
+	for param, field := range map[string]**string{
+		"glass":   &filter.Glass,
+		"method":  &filter.Method,
+		"ice":     &filter.Ice,
+		"garnish": &filter.Garnish,
+		"origin":  &filter.Origin,
+		"creator": &filter.Creator,
+	} {
+		if v := c.Query(param); v != "" {
+			*field = &v
+		}
+	}
+	if year, err := strconv.Atoi(c.Query("year")); err == nil {
+		filter.Year = &year
+	}
+	for param, field := range map[string]**float64{
+		"minAbv":      &filter.MinABV,
+		"maxAbv":      &filter.MaxABV,
+		"maxCalories": &filter.MaxCalories,
+	} {
+		if v, err := strconv.ParseFloat(c.Query(param), 64); err == nil {
+			*field = &v
+		}
+	}
+
 	return filter
 }
+
+// buildGenerateOptions reads the maxMissing, minCoverage, rank, limit and skip
+// query parameters for non-strict generation. Unlike buildFilter it rejects
+// values it can't read, and any error is the client's. Without a limit
+// parameter the results are capped at defaultLimit; v1 passes 0 and returns
+// every match, v2 pages and so doesn't take limit=0 either.
+func buildGenerateOptions(c *gin.Context, defaultLimit int) (drinkee.GenerateOptions, error) {
+	opts := drinkee.GenerateOptions{Rank: c.Query("rank"), Limit: defaultLimit}
+
+	if v := c.Query("maxMissing"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.New("maxMissing must be a whole number")
+		}
+		opts.MaxMissing = &n
+	}
+	if v := c.Query("minCoverage"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return opts, errors.New("minCoverage must be a number")
+		}
+		opts.MinCoverage = f
+	}
+	for param, field := range map[string]*int{"limit": &opts.Limit, "skip": &opts.Skip} {
+		if v := c.Query(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return opts, fmt.Errorf("%s must be a whole number", param)
+			}
+			*field = n
+		}
+	}
+	if defaultLimit > 0 && opts.Limit == 0 {
+		return opts, errors.New("limit must be at least 1")
+	}
+
+	return opts, opts.Validate()
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleGetDrinksV2(c *gin.Context) {
+	f := buildFilter(c)
+
+	drinks, err := s.DrinkService.FindDrinks(c, f)
+	if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		renderError(c, http.StatusBadRequest, codeAmbiguousIngredient, err.Error())
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting drinks: %s", err))
+		return
+	}
+	if drinks == nil {
+		drinks = []*drinkee.Drink{}
+	}
+
+	renderETag(c, http.StatusOK, Envelope{
+		Data:   drinks,
+		Meta:   &Meta{Count: len(drinks), Limit: f.Limit, Skip: f.Skip},
+		Errors: []APIError{},
+	})
+}
+
+func (s *Server) handleGetDrinkV2(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid drink id")
+		return
+	}
+
+	drink, err := s.DrinkService.FindDrinkByID(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no drink with id %d", id))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting drink: %s", err))
+		return
+	}
+
+	drink, err = scaleDrink(c, drink)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	renderETag(c, http.StatusOK, Envelope{Data: drink, Errors: []APIError{}})
+}
+
+func (s *Server) handleGetDrinkBySlugV2(c *gin.Context) {
+	slug := c.Param("slug")
+
+	drink, err := s.DrinkService.FindDrinkBySlug(c, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no drink with slug %q", slug))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting drink: %s", err))
+		return
+	}
+
+	drink, err = scaleDrink(c, drink)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	renderETag(c, http.StatusOK, Envelope{Data: drink, Errors: []APIError{}})
+}
+
+func (s *Server) handleCreateDrinkV2(c *gin.Context) {
+	var cd drinkee.CreateDrink
+	if err := bindJSON(c, &cd); err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("invalid drink: %s", err))
+		return
+	}
+
+	drink, err := s.DrinkService.CreateDrink(c, &cd)
+	if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		renderError(c, http.StatusBadRequest, codeAmbiguousIngredient, err.Error())
+		return
+	}
+	if errors.Is(err, drinkee.ErrInvalidDrink) {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, drinkee.ErrConflict) {
+		renderError(c, http.StatusConflict, codeConflict, err.Error())
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error creating drink: %s", err))
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/v2/drinks/%d", drink.ID))
+	renderData(c, http.StatusCreated, drink, nil)
+}
+
+func (s *Server) handleGenerateDrinksV2(c *gin.Context) {
+	var req IngredientListRequest
+	if err := bindJSON(c, &req); err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("invalid ingredient list: %s", err))
+		return
+	}
+
+	if c.Query("strict") == "true" {
+		metrics.GenerateRequests.WithLabelValues("strict").Inc()
+		drinks, err := s.DrinkService.GenerateDrinks(c, req.Ingredients)
+		if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+			renderError(c, http.StatusBadRequest, codeAmbiguousIngredient, err.Error())
+			return
+		}
+		if errors.Is(err, drinkee.ErrUnknownIngredient) {
+			renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error generating drinks: %s", err))
+			return
+		}
+		if drinks == nil {
+			drinks = []*drinkee.Drink{}
+		}
+		renderData(c, http.StatusOK, drinks, &Meta{Count: len(drinks)})
+		return
+	}
+
+	metrics.GenerateRequests.WithLabelValues("non_strict").Inc()
+	opts, err := buildGenerateOptions(c, 100)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	drinks, err := s.DrinkService.GenerateNonStrictDrinks(c, req.Ingredients, opts)
+	if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		renderError(c, http.StatusBadRequest, codeAmbiguousIngredient, err.Error())
+		return
+	}
+	if errors.Is(err, drinkee.ErrInvalidGenerateOptions) || errors.Is(err, drinkee.ErrUnknownIngredient) {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error generating drinks: %s", err))
+		return
+	}
+	if drinks == nil {
+		drinks = []*drinkee.NonStrictDrink{}
+	}
+
+	renderData(c, http.StatusOK, drinks, &Meta{Count: len(drinks), Limit: opts.Limit, Skip: opts.Skip})
+}
+
+func (s *Server) handleGetIngredientsV2(c *gin.Context) {
+	ingredients, err := s.DrinkService.FindIngredients(c)
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting ingredients: %s", err))
+		return
+	}
+	if ingredients == nil {
+		ingredients = []*drinkee.Ingredient{}
+	}
+
+	renderETag(c, http.StatusOK, Envelope{
+		Data:   ingredients,
+		Meta:   &Meta{Count: len(ingredients)},
+		Errors: []APIError{},
+	})
+}
@@ -0,0 +1,255 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func (catalog) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	if id != 1 {
+		return nil, sql.ErrNoRows
+	}
+	return &drinkee.Drink{ID: 1, Name: "negroni", DisplayName: "Negroni"}, nil
+}
+
+func (catalog) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	if cd.Glass == "goblet" {
+		return nil, fmt.Errorf("%w: unknown glass %q", drinkee.ErrInvalidDrink, cd.Glass)
+	}
+	return &drinkee.Drink{ID: 2, Name: cd.Name, DisplayName: cd.DisplayName, Instructions: cd.Instructions, DrinkIngredients: cd.DrinkIngredients, DrinkMetadata: cd.DrinkMetadata}, nil
+}
+
+func TestV2CreateAcceptsIngredientRoles(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	w, env := serveV2(s, "POST", "/api/v2/drinks", `{
+		"name": "dirty_martini",
+		"displayName": "Dirty Martini",
+		"instructions": "Stir with ice.",
+		"drinkIngredients": [
+			{"name": "gin", "measurement": "2 1/2 oz", "role": "base"},
+			{"name": "olive brine", "measurement": "1/2 oz", "role": "modifier", "optional": true},
+			{"name": "lemon", "measurement": "1 wedge", "role": "garnish"}
+		]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	ingredients := env.Data.(map[string]interface{})["drinkIngredients"].([]interface{})
+	assert.Equal(t, "base", ingredients[0].(map[string]interface{})["role"])
+	assert.NotContains(t, ingredients[0], "optional")
+	assert.Equal(t, true, ingredients[1].(map[string]interface{})["optional"])
+
+	w, env = serveV2(s, "POST", "/api/v2/drinks", `{
+		"name": "dirty_martini",
+		"displayName": "Dirty Martini",
+		"instructions": "Stir with ice.",
+		"drinkIngredients": [{"name": "gin", "measurement": "2 1/2 oz", "role": "spirit"}]
+	}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, env.Errors[0].Message, "Role")
+}
+
+// filters records the filter of each FindDrinks call.
+type filters struct {
+	catalog
+	got []drinkee.DrinkFilter
+}
+
+func (f *filters) FindDrinks(ctx context.Context, filter drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	f.got = append(f.got, filter)
+	return nil, nil
+}
+
+func serveV2(s *drinkeehttp.Server, method, path, body string) (*httptest.ResponseRecorder, drinkeehttp.Envelope) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	s.Router.ServeHTTP(w, req)
+
+	var env drinkeehttp.Envelope
+	json.Unmarshal(w.Body.Bytes(), &env)
+	return w, env
+}
+
+func TestV2ListsAreEnveloped(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks?limit=10&skip=5", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, env.Data, 1)
+	assert.Equal(t, &drinkeehttp.Meta{Count: 1, Limit: 10, Skip: 5}, env.Meta)
+	assert.NotNil(t, env.Errors)
+	assert.Empty(t, env.Errors)
+}
+
+func TestV2CreateReturnsCreatedDrink(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	w, env := serveV2(s, "POST", "/api/v2/drinks", `{
+		"name": "boulevardier",
+		"displayName": "Boulevardier",
+		"instructions": "Stir with ice.",
+		"drinkIngredients": [{"name": "bourbon", "measurement": "1 oz"}]
+	}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/v2/drinks/2", w.Header().Get("Location"))
+	assert.Equal(t, "Boulevardier", env.Data.(map[string]interface{})["displayName"])
+
+	w, env = serveV2(s, "POST", "/api/v2/drinks", `{"name": "boulevardier"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, env.Data)
+	assert.Equal(t, "bad_request", env.Errors[0].Code)
+}
+
+func TestV2CreateAcceptsMetadata(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	create := func(metadata string) (*httptest.ResponseRecorder, drinkeehttp.Envelope) {
+		return serveV2(s, "POST", "/api/v2/drinks", `{
+			"name": "boulevardier",
+			"displayName": "Boulevardier",
+			"instructions": "Stir with ice.",
+			"drinkIngredients": [{"name": "bourbon", "measurement": "1 oz"}],
+			`+metadata+`
+		}`)
+	}
+
+	w, env := create(`"glass": "coupe", "method": "stirred", "garnish": "orange peel", "creator": "Erskine Gwynne", "year": 1927`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	data := env.Data.(map[string]interface{})
+	assert.Equal(t, "stirred", data["method"])
+	assert.Equal(t, float64(1927), data["year"])
+	assert.NotContains(t, data, "ice")
+
+	w, env = create(`"method": "muddled"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, env.Errors[0].Message, "Method")
+
+	w, env = create(`"glass": "goblet"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `invalid drink: unknown glass "goblet"`, env.Errors[0].Message)
+}
+
+func TestDrinkFilterMetadata(t *testing.T) {
+	f := &filters{}
+	s := drinkeehttp.NewServer()
+	s.DrinkService = f
+
+	serveV2(s, "GET", "/api/v2/drinks?glass=coupe&method=stirred&garnish=orange&year=1927", "")
+	serveV2(s, "GET", "/api/v1/drinks?ice=crushed&year=old", "")
+
+	assert.Equal(t, "coupe", *f.got[0].Glass)
+	assert.Equal(t, "stirred", *f.got[0].Method)
+	assert.Equal(t, "orange", *f.got[0].Garnish)
+	assert.Equal(t, 1927, *f.got[0].Year)
+	assert.Nil(t, f.got[0].Ice)
+
+	assert.Equal(t, "crushed", *f.got[1].Ice)
+	assert.Nil(t, f.got[1].Year)
+}
+
+func TestDrinkFilterStats(t *testing.T) {
+	f := &filters{}
+	s := drinkeehttp.NewServer()
+	s.DrinkService = f
+
+	serveV2(s, "GET", "/api/v2/drinks?maxAbv=10&maxCalories=150.5", "")
+	serveV2(s, "GET", "/api/v1/drinks?minAbv=strong", "")
+
+	assert.Equal(t, 10.0, *f.got[0].MaxABV)
+	assert.Equal(t, 150.5, *f.got[0].MaxCalories)
+	assert.Nil(t, f.got[0].MinABV)
+
+	assert.Nil(t, f.got[1].MinABV)
+}
+
+// generated records the options of each GenerateNonStrictDrinks call.
+type generated struct {
+	catalog
+	got []drinkee.GenerateOptions
+}
+
+func (g *generated) GenerateNonStrictDrinks(ctx context.Context, i []drinkee.Ingredient, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
+	g.got = append(g.got, opts)
+	return []*drinkee.NonStrictDrink{{
+		ID: 1, Name: "negroni", HaveIngredientCount: 2, MissingIngredientCount: 1, Coverage: 0.6667,
+		MatchedIngredients: drinkee.DrinkIngredientSlice{{Name: "gin"}, {Name: "campari"}},
+		MissingIngredients: drinkee.DrinkIngredientSlice{{Name: "sweet vermouth"}},
+	}}, nil
+}
+
+func TestGenerateOptions(t *testing.T) {
+	g := &generated{}
+	s := drinkeehttp.NewServer()
+	s.DrinkService = g
+	body := `{"ingredients": [{"id": 1}, {"id": 2}]}`
+
+	w, env := serveV2(s, "POST", "/api/v2/drinks/generate?maxMissing=1&minCoverage=0.5&rank=coverage&limit=20&skip=40", body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	maxMissing := 1
+	assert.Equal(t, drinkee.GenerateOptions{MaxMissing: &maxMissing, MinCoverage: 0.5, Rank: drinkee.RankCoverage, Limit: 20, Skip: 40}, g.got[0])
+	assert.Equal(t, &drinkeehttp.Meta{Count: 1, Limit: 20, Skip: 40}, env.Meta)
+	drink := env.Data.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 0.6667, drink["coverage"])
+	assert.Len(t, drink["matchedIngredients"], 2)
+	assert.Equal(t, "sweet vermouth", drink["missingIngredients"].([]interface{})[0].(map[string]interface{})["name"])
+
+	// v1 returns every match unless asked to page
+	w, _ = serveV2(s, "POST", "/api/v1/generateDrinks", body)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, drinkee.GenerateOptions{}, g.got[1])
+	w, _ = serveV2(s, "POST", "/api/v1/generateDrinks?limit=0", body)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, drinkee.GenerateOptions{}, g.got[2])
+	w, _ = serveV2(s, "POST", "/api/v1/generateDrinks", `{"ingredients": "gin"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "couldn't bind to list of ingredients: "))
+	assert.NotContains(t, w.Body.String(), "%!")
+
+	for _, query := range []string{"rank=popularity", "maxMissing=-1", "maxMissing=few", "minCoverage=1.5", "limit=-5", "limit=0"} {
+		w, env = serveV2(s, "POST", "/api/v2/drinks/generate?"+query, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, "bad_request", env.Errors[0].Code, query)
+	}
+	assert.Len(t, g.got, 3)
+}
+
+func TestV2ErrorsAreEnveloped(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/42", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, []drinkeehttp.APIError{{Code: "not_found", Message: "no drink with id 42"}}, env.Errors)
+
+	// validation failures use the envelope on v2 and plain text on v1
+	if err := s.EnableRequestValidation(); err != nil {
+		t.Fatal(err)
+	}
+	w, env = serveV2(s, "GET", "/api/v2/drinks/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "bad_request", env.Errors[0].Code)
+
+	w, _ = serveV2(s, "GET", "/api/v1/drinks/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid request")
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Envelope wraps every /api/v2 response. Data is null when Errors is not
+// empty, and Meta is only set on list responses.
+type Envelope struct {
+	Data   interface{} `json:"data"`
+	Meta   *Meta       `json:"meta"`
+	Errors []APIError  `json:"errors"`
+}
+
+type Meta struct {
+	Count int `json:"count"`
+	Limit int `json:"limit,omitempty"`
+	Skip  int `json:"skip,omitempty"`
+}
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	codeBadRequest           = "bad_request"
+	codeAmbiguousIngredient  = "ambiguous_ingredient"
+	codeNotFound             = "not_found"
+	codeConflict             = "conflict"
+	codePayloadTooLarge      = "payload_too_large"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codeInternalError        = "internal_error"
+)
+
+// errorCode is the code for an error status, for handlers that share their
+// error handling with v1.
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return codeBadRequest
+	case http.StatusNotFound:
+		return codeNotFound
+	case http.StatusConflict:
+		return codeConflict
+	case http.StatusRequestEntityTooLarge:
+		return codePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return codeUnsupportedMediaType
+	default:
+		return codeInternalError
+	}
+}
+
+// renderData writes data in an envelope with no errors.
+func renderData(c *gin.Context, status int, data interface{}, meta *Meta) {
+	render(c, status, Envelope{Data: data, Meta: meta, Errors: []APIError{}})
+}
+
+// renderError writes a single error in an envelope and aborts the chain.
+func renderError(c *gin.Context, status int, code, message string) {
+	render(c, status, Envelope{Errors: []APIError{{Code: code, Message: message}}})
+	c.Abort()
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventRetry is the reconnect delay sent to EventSource clients.
+	eventRetry = 3 * time.Second
+	// eventHeartbeat keeps proxies from timing out idle streams.
+	eventHeartbeat = 15 * time.Second
+)
+
+// handleEvents streams catalog events as server-sent events. Clients resume
+// with the Last-Event-ID header, which EventSource sends on reconnect, or the
+// lastEventId query parameter. A reset event tells a client that events since
+// its last ID are gone from the replay buffer and it should refetch.
+func (s *Server) handleEvents(c *gin.Context) {
+	var lastEventID int64
+	if id := c.GetHeader("Last-Event-ID"); id != "" {
+		lastEventID, _ = strconv.ParseInt(id, 10, 64)
+	} else if id := c.Query("lastEventId"); id != "" {
+		lastEventID, _ = strconv.ParseInt(id, 10, 64)
+	}
+
+	events, err := s.subscribeEvents(c, lastEventID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "error subscribing to events: %s", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// stop nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventRetry.Milliseconds())
+	if events.reset {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case e, ok := <-events.ch:
+			if !ok {
+				// dropped for falling behind, the client reconnects and resumes
+				return
+			}
+			if err := writeEvent(c.Writer, e); err != nil {
+				logger.FromContext(c, s.Logger).Warn("error writing event", logger.Err(err))
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		case <-s.shutdown:
+			return
+		}
+	}
+}
+
+type eventStream struct {
+	ch    <-chan drinkee.Event
+	reset bool
+}
+
+// subscribeEvents subscribes to events after lastEventID, falling back to only new
+// events when those have expired from the replay buffer.
+func (s *Server) subscribeEvents(c *gin.Context, lastEventID int64) (eventStream, error) {
+	ch, err := s.EventService.SubscribeEvents(c.Request.Context(), lastEventID)
+	if errors.Is(err, drinkee.ErrEventsExpired) {
+		ch, err = s.EventService.SubscribeEvents(c.Request.Context(), 0)
+		return eventStream{ch: ch, reset: true}, err
+	}
+	return eventStream{ch: ch}, err
+}
+
+func writeEvent(w gin.ResponseWriter, e drinkee.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
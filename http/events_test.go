@@ -0,0 +1,53 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/events"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// streamEvents reads the event stream until the request times out.
+func streamEvents(s *drinkeehttp.Server, lastEventID string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/events", nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestEventsReplayAfterLastEventID(t *testing.T) {
+	broker := events.NewBroker(2)
+	for id := int64(1); id <= 3; id++ {
+		broker.Publish(drinkee.Event{ID: id, Type: drinkee.EventDrinkCreated, Data: json.RawMessage(`{"id":1}`)})
+	}
+
+	s := drinkeehttp.NewServer()
+	s.EventService = broker
+
+	w := streamEvents(s, "2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"), body)
+	assert.Contains(t, body, "id: 3\nevent: drink.created\ndata: {\"id\":3,")
+	assert.NotContains(t, body, "id: 2\n")
+
+	// event 1 has left the buffer
+	w = streamEvents(s, "1")
+	assert.Contains(t, w.Body.String(), "event: reset\n")
+	assert.NotContains(t, w.Body.String(), "id: 3\n")
+}
@@ -0,0 +1,11 @@
+package http
+
+import (
+	"github.com/dylanconnolly/drinkee/graphql"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleGraphQL(c *gin.Context) {
+	h := &graphql.Handler{DrinkService: s.DrinkService}
+	h.ServeHTTP(c.Writer, c.Request)
+}
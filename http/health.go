@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (s *Server) handleHealthz(c *gin.Context) {
+	render(c, http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (s *Server) handleReadyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	resp := ReadinessResponse{
+		Status: "ok",
+		Checks: map[string]string{"database": "ok", "migrations": "ok"},
+	}
+
+	if s.HealthService == nil {
+		resp.Checks["database"] = "not configured"
+		resp.Checks["migrations"] = "not configured"
+		resp.Status = "unavailable"
+		render(c, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	if err := s.HealthService.Ping(ctx); err != nil {
+		resp.Checks["database"] = err.Error()
+		resp.Status = "unavailable"
+	}
+
+	version, dirty, err := s.HealthService.MigrationVersion(ctx)
+	switch {
+	case err != nil:
+		resp.Checks["migrations"] = err.Error()
+		resp.Status = "unavailable"
+	case dirty:
+		resp.Checks["migrations"] = "schema is dirty"
+		resp.Status = "unavailable"
+	case version < s.MigrationVersion:
+		resp.Checks["migrations"] = "schema is behind the binary"
+		resp.Status = "unavailable"
+	}
+
+	if resp.Status != "ok" {
+		render(c, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	render(c, http.StatusOK, resp)
+}
+
+func (s *Server) handleVersion(c *gin.Context) {
+	render(c, http.StatusOK, s.BuildInfo)
+}
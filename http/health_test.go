@@ -0,0 +1,133 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// health reports a fixed database state.
+type health struct {
+	pingErr error
+	version uint
+	dirty   bool
+	err     error
+}
+
+func (h health) Ping(ctx context.Context) error {
+	return h.pingErr
+}
+
+func (h health) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return h.version, h.dirty, h.err
+}
+
+func serveHealth(s *drinkeehttp.Server, path string) (*httptest.ResponseRecorder, drinkeehttp.ReadinessResponse) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	s.Router.ServeHTTP(w, req)
+
+	var resp drinkeehttp.ReadinessResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name   string
+		health drinkee.HealthService
+		code   int
+		checks map[string]string
+	}{
+		{
+			name:   "ready",
+			health: health{version: 15},
+			code:   http.StatusOK,
+			checks: map[string]string{"database": "ok", "migrations": "ok"},
+		},
+		{
+			name:   "ahead of the binary",
+			health: health{version: 16},
+			code:   http.StatusOK,
+			checks: map[string]string{"database": "ok", "migrations": "ok"},
+		},
+		{
+			name:   "ping fails",
+			health: health{pingErr: errors.New("connection refused"), version: 15},
+			code:   http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "connection refused", "migrations": "ok"},
+		},
+		{
+			name:   "dirty",
+			health: health{version: 15, dirty: true},
+			code:   http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "ok", "migrations": "schema is dirty"},
+		},
+		{
+			name:   "behind",
+			health: health{version: 14},
+			code:   http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "ok", "migrations": "schema is behind the binary"},
+		},
+		{
+			name:   "version unreadable",
+			health: health{err: errors.New("no schema_migrations")},
+			code:   http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "ok", "migrations": "no schema_migrations"},
+		},
+		{
+			name:   "not configured",
+			code:   http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "not configured", "migrations": "not configured"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := drinkeehttp.NewServer()
+			s.HealthService = tt.health
+			s.MigrationVersion = 15
+
+			w, resp := serveHealth(s, "/readyz")
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.checks, resp.Checks)
+			if tt.code == http.StatusOK {
+				assert.Equal(t, "ok", resp.Status)
+			} else {
+				assert.Equal(t, "unavailable", resp.Status)
+			}
+		})
+	}
+}
+
+func TestHealthzDoesNotTouchTheDatabase(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.HealthService = health{pingErr: errors.New("connection refused")}
+
+	w, resp := serveHealth(s, "/healthz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", resp.Status)
+}
+
+func TestVersion(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.BuildInfo = drinkee.BuildInfo{Commit: "abc123", BuildTime: "2026-10-19T12:00:00Z"}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/version", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var info drinkee.BuildInfo
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, s.BuildInfo, info)
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/gin-gonic/gin"
+)
+
+// uploadOverhead allows for the multipart framing around an image.
+const uploadOverhead = 64 << 10
+
+func (s *Server) handleSetDrinkImage(c *gin.Context) {
+	img, status, err := s.setDrinkImage(c)
+	if err != nil {
+		c.String(status, "%s", err)
+		return
+	}
+
+	render(c, http.StatusCreated, img)
+}
+
+func (s *Server) handleSetDrinkImageV2(c *gin.Context) {
+	img, status, err := s.setDrinkImage(c)
+	if err != nil {
+		renderError(c, status, errorCode(status), err.Error())
+		return
+	}
+
+	renderData(c, http.StatusCreated, img, nil)
+}
+
+// setDrinkImage stores the "image" field of a multipart upload, returning the
+// status to respond with when it fails.
+func (s *Server) setDrinkImage(c *gin.Context) (*drinkee.Image, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid drink id")
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, drinkee.MaxImageSize+uploadOverhead)
+	fh, err := c.FormFile("image")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return nil, http.StatusRequestEntityTooLarge, drinkee.ErrImageTooLarge
+	}
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("expected a multipart/form-data upload with an image field: %w", err)
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error reading upload: %w", err)
+	}
+	defer f.Close()
+
+	img, err := s.ImageService.SetDrinkImage(c, id, f)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, http.StatusNotFound, fmt.Errorf("no drink with id %d", id)
+	case errors.Is(err, drinkee.ErrImageTooLarge):
+		return nil, http.StatusRequestEntityTooLarge, err
+	case errors.Is(err, drinkee.ErrImageType):
+		return nil, http.StatusUnsupportedMediaType, err
+	case err != nil:
+		return nil, http.StatusInternalServerError, fmt.Errorf("error storing image: %w", err)
+	}
+	return img, http.StatusCreated, nil
+}
+
+// handleImageFile serves images kept in a local blob.FS. Their keys change
+// with their content, so they can be cached indefinitely.
+func (s *Server) handleImageFile(c *gin.Context) {
+	if s.ImageFS == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.FileFromFS(c.Param("key"), s.ImageFS)
+}
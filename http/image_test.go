@@ -0,0 +1,70 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type imageStore struct{}
+
+func (imageStore) SetDrinkImage(ctx context.Context, drinkID int, r io.Reader) (*drinkee.Image, error) {
+	b, _ := io.ReadAll(r)
+	switch {
+	case drinkID != 1:
+		return nil, sql.ErrNoRows
+	case string(b) != "jpeg bytes":
+		return nil, drinkee.ErrImageType
+	}
+	return &drinkee.Image{URL: "/images/drinks/1/ab/original.jpg", ContentType: "image/jpeg"}, nil
+}
+
+func uploadImage(s *drinkeehttp.Server, path, field, content string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile(field, "photo.jpg")
+	fw.Write([]byte(content))
+	mw.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSetDrinkImage(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.ImageService = imageStore{}
+	if err := s.EnableRequestValidation(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := uploadImage(s, "/api/v1/drinks/1/image", "image", "jpeg bytes")
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var img drinkee.Image
+	json.Unmarshal(w.Body.Bytes(), &img)
+	assert.Equal(t, "/images/drinks/1/ab/original.jpg", img.URL)
+
+	w = uploadImage(s, "/api/v1/drinks/1/image", "image", "GIF89a")
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = uploadImage(s, "/api/v1/drinks/1/image", "photo", "jpeg bytes")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = uploadImage(s, "/api/v2/drinks/2/image", "image", "jpeg bytes")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var env drinkeehttp.Envelope
+	json.Unmarshal(w.Body.Bytes(), &env)
+	assert.Equal(t, "not_found", env.Errors[0].Code)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// ActorHeader names who is making a request, recorded on the drink revisions
+// it makes. Nothing authenticates it, it is taken on trust, so revisions
+// record the client IP next to it.
+const ActorHeader = "X-Actor"
+
+// requestContext accepts an incoming X-Request-ID (or creates one), echoes it
+// on the response and stores it alongside a request-scoped logger in the
+// request context so the postgres layer can correlate its entries. The
+// client IP and an X-Actor of up to 128 bytes are stored too.
+func (s *Server) requestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		l := s.Logger.With(logger.F("requestId", id))
+		ctx := drinkee.NewContextWithRequestID(c.Request.Context(), id)
+		ctx = drinkee.NewContextWithClientIP(ctx, c.ClientIP())
+		if actor := strings.TrimSpace(c.GetHeader(ActorHeader)); actor != "" && len(actor) <= 128 {
+			l = l.With(logger.F("actor", actor))
+			ctx = drinkee.NewContextWithActor(ctx, actor)
+		}
+		ctx = logger.NewContext(ctx, l)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// accessLog replaces gin's text formatter with one structured entry per request.
+func (s *Server) accessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		fields := []logger.Field{
+			logger.F("method", c.Request.Method),
+			logger.F("route", c.FullPath()),
+			logger.F("path", c.Request.URL.Path),
+			logger.F("status", c.Writer.Status()),
+			logger.F("latencyMs", float64(time.Since(start).Microseconds())/1000),
+			logger.F("bytes", c.Writer.Size()),
+			logger.F("clientIp", c.ClientIP()),
+			logger.F("userAgent", c.Request.UserAgent()),
+		}
+		if user := c.GetString(gin.AuthUserKey); user != "" {
+			fields = append(fields, logger.F("user", user))
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypeAny).String(); errs != "" {
+			fields = append(fields, logger.F("errors", errs))
+		}
+
+		l := logger.FromContext(c.Request.Context(), s.Logger)
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			l.Error("request", fields...)
+		case status >= 400:
+			l.Warn("request", fields...)
+		default:
+			l.Info("request", fields...)
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,98 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// logEntries decodes the JSON lines a logger wrote.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestContextAndAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	s := drinkeehttp.NewServer()
+	s.Logger = logger.NewJSONLogger(&buf, logger.DebugLevel)
+
+	var requestID, actor string
+	s.Router.GET("/teapot/:id", func(c *gin.Context) {
+		requestID = drinkee.RequestIDFromContext(c)
+		actor = drinkee.ActorFromContext(c)
+		logger.FromContext(c, nil).Info("brewing")
+		c.String(http.StatusTeapot, "short and stout")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/teapot/1", nil)
+	req.Header.Set(drinkeehttp.RequestIDHeader, "req-123")
+	req.Header.Set(drinkeehttp.ActorHeader, "bartender")
+	req.Header.Set("User-Agent", "drinkee-test")
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-123", w.Header().Get(drinkeehttp.RequestIDHeader))
+	assert.Equal(t, "req-123", requestID)
+	assert.Equal(t, "bartender", actor)
+
+	entries := logEntries(t, &buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "brewing", entries[0]["msg"])
+	assert.Equal(t, "req-123", entries[0]["requestId"])
+	assert.Equal(t, "bartender", entries[0]["actor"])
+
+	access := entries[1]
+	assert.Equal(t, "request", access["msg"])
+	assert.Equal(t, "warn", access["level"])
+	assert.Equal(t, "req-123", access["requestId"])
+	assert.Equal(t, "GET", access["method"])
+	assert.Equal(t, "/teapot/:id", access["route"])
+	assert.Equal(t, "/teapot/1", access["path"])
+	assert.Equal(t, float64(http.StatusTeapot), access["status"])
+	assert.Equal(t, float64(len("short and stout")), access["bytes"])
+	assert.Equal(t, "drinkee-test", access["userAgent"])
+	for _, field := range []string{"latencyMs", "clientIp"} {
+		assert.Contains(t, access, field)
+	}
+}
+
+func TestRequestContextCreatesRequestIDs(t *testing.T) {
+	var buf bytes.Buffer
+	s := drinkeehttp.NewServer()
+	s.Logger = logger.NewJSONLogger(&buf, logger.InfoLevel)
+
+	ids := map[string]bool{}
+	for _, header := range []string{"", strings.Repeat("x", 129)} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/healthz", nil)
+		if header != "" {
+			req.Header.Set(drinkeehttp.RequestIDHeader, header)
+		}
+		s.Router.ServeHTTP(w, req)
+
+		id := w.Header().Get(drinkeehttp.RequestIDHeader)
+		assert.Len(t, id, 32)
+		ids[id] = true
+	}
+	assert.Len(t, ids, 2)
+
+	for _, entry := range logEntries(t, &buf) {
+		assert.True(t, ids[entry["requestId"].(string)])
+		assert.NotContains(t, entry, "actor")
+	}
+}
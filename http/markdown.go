@@ -0,0 +1,67 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+// categoryHeadings title the sections of a printed shopping list.
+var categoryHeadings = map[string]string{
+	drinkee.CategorySpirit:  "Spirits",
+	drinkee.CategoryLiqueur: "Liqueurs",
+	drinkee.CategoryWine:    "Wine & Vermouth",
+	drinkee.CategoryBeer:    "Beer",
+	drinkee.CategoryBitters: "Bitters",
+	drinkee.CategorySyrup:   "Syrups",
+	drinkee.CategoryJuice:   "Juices",
+	drinkee.CategoryMixer:   "Mixers",
+	drinkee.CategoryDairy:   "Dairy & Eggs",
+	drinkee.CategoryProduce: "Produce",
+	drinkee.CategoryOther:   "Other",
+}
+
+// encodeMarkdown prints shopping lists as a checklist. Nothing else has a
+// printable form. v2 envelopes are written as their data, or as their errors
+// on failure.
+func encodeMarkdown(obj interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch v := obj.(type) {
+	case Envelope:
+		if len(v.Errors) == 0 {
+			return encodeMarkdown(v.Data)
+		}
+		buf.WriteString("# Errors\n\n")
+		for _, e := range v.Errors {
+			fmt.Fprintf(&buf, "- `%s`: %s\n", e.Code, e.Message)
+		}
+	case *drinkee.ShoppingList:
+		buf.WriteString("# Shopping List\n")
+		for _, category := range v.Categories {
+			fmt.Fprintf(&buf, "\n## %s\n\n", categoryHeadings[category.Category])
+			for _, item := range category.Items {
+				fmt.Fprintf(&buf, "- [ ] **%s**: %s", markdownEscape(item.DisplayName), item.Buy)
+				if item.Buy != item.Needed && item.Needed != "" {
+					fmt.Fprintf(&buf, " (%s needed)", item.Needed)
+				}
+				fmt.Fprintf(&buf, " _for %s_\n", markdownEscape(strings.Join(item.Drinks, ", ")))
+			}
+		}
+		if len(v.Covered) > 0 {
+			fmt.Fprintf(&buf, "\nAlready in the pantry: %s\n", markdownEscape(strings.Join(v.Covered, ", ")))
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T has no Markdown representation", errNotAcceptable, obj)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dylanconnolly/drinkee/http/openapi"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec)
+}
+
+func (s *Server) handleDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+}
+
+// EnableRequestValidation makes the /api routes reject requests that do not
+// match the OpenAPI document with a 400 before they reach a handler.
+func (s *Server) EnableRequestValidation() error {
+	doc, err := openapi.Load()
+	if err != nil {
+		return err
+	}
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return err
+	}
+
+	s.openAPIRouter = router
+	return nil
+}
+
+// validateRequest checks requests against the OpenAPI document once
+// EnableRequestValidation has been called. invalid writes the 400 response so
+// each API version can keep its own error format.
+func (s *Server) validateRequest(invalid func(c *gin.Context, err error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.openAPIRouter == nil {
+			c.Next()
+			return
+		}
+
+		route, pathParams, err := s.openAPIRouter.FindRoute(c.Request)
+		if errors.Is(err, routers.ErrPathNotFound) || errors.Is(err, routers.ErrMethodNotAllowed) {
+			// leave unknown routes to gin's 404/405 handling
+			c.Next()
+			return
+		}
+		if err != nil {
+			invalid(c, err)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			invalid(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func invalidRequestV1(c *gin.Context, err error) {
+	c.String(http.StatusBadRequest, "invalid request: %s", err)
+	c.Abort()
+}
+
+func invalidRequestV2(c *gin.Context, err error) {
+	renderError(c, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("invalid request: %s", err))
+}
@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/dylanconnolly/drinkee/http/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIDocumentsEveryAPIRoute(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("invalid OpenAPI document: %s", err)
+	}
+
+	s := drinkeehttp.NewServer()
+	for _, r := range s.Router.Routes() {
+		if !strings.HasPrefix(r.Path, "/api/") {
+			continue
+		}
+
+		// gin uses :param, OpenAPI uses {param}
+		segments := strings.Split(r.Path, "/")
+		for i, seg := range segments {
+			if strings.HasPrefix(seg, ":") {
+				segments[i] = "{" + seg[1:] + "}"
+			}
+		}
+
+		item := doc.Paths.Find(strings.Join(segments, "/"))
+		if assert.NotNil(t, item, "%s is not documented", r.Path) {
+			assert.NotNil(t, item.GetOperation(r.Method), "%s %s is not documented", r.Method, r.Path)
+		}
+	}
+}
+
+func TestRequestValidationRejectsInvalidBodies(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	if err := s.EnableRequestValidation(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/drinks", bytes.NewBufferString(`{"name": "negroni", "drinkIngredients": []}`))
+	req.Header.Set("Content-Type", "application/json")
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "displayName")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/drinks/abc", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOpenAPISpecIsServed(t *testing.T) {
+	s := drinkeehttp.NewServer()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, openapi.Spec, w.Body.Bytes())
+}
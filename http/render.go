@@ -0,0 +1,191 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// format is a response encoding a client can ask for with the format query
+// parameter or the Accept header.
+type format string
+
+const (
+	formatJSON       format = "json"
+	formatPrettyJSON format = "pretty"
+	formatYAML       format = "yaml"
+	formatCSV        format = "csv"
+	formatMsgPack    format = "msgpack"
+	formatMarkdown   format = "markdown"
+)
+
+var contentTypes = map[format]string{
+	formatJSON:       "application/json; charset=utf-8",
+	formatPrettyJSON: "application/json; charset=utf-8",
+	formatYAML:       "application/yaml; charset=utf-8",
+	formatCSV:        "text/csv; charset=utf-8",
+	formatMsgPack:    "application/msgpack",
+	formatMarkdown:   "text/markdown; charset=utf-8",
+}
+
+// mediaTypes maps the media types accepted in an Accept header to formats.
+var mediaTypes = map[string]format{
+	"*/*":                     formatJSON,
+	"application/*":           formatJSON,
+	"application/json":        formatJSON,
+	"application/yaml":        formatYAML,
+	"application/x-yaml":      formatYAML,
+	"text/yaml":               formatYAML,
+	"text/csv":                formatCSV,
+	"application/msgpack":     formatMsgPack,
+	"application/x-msgpack":   formatMsgPack,
+	"application/vnd.msgpack": formatMsgPack,
+	"text/markdown":           formatMarkdown,
+}
+
+// errNotAcceptable is returned when a response can't be encoded in any of the
+// formats the client asked for.
+var errNotAcceptable = errors.New("not acceptable")
+
+// negotiateFormat picks the response format. The format query parameter wins
+// over the Accept header, and compact JSON is the default.
+func negotiateFormat(c *gin.Context) (format, error) {
+	if q, ok := c.GetQuery("format"); ok {
+		f := format(strings.ToLower(q))
+		if _, known := contentTypes[f]; !known {
+			return "", fmt.Errorf("%w: unknown format %q", errNotAcceptable, q)
+		}
+		return f, nil
+	}
+
+	accept := c.GetHeader("Accept")
+	if accept == "" {
+		return formatJSON, nil
+	}
+
+	type candidate struct {
+		format format
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		f, ok := mediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{f, q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: no supported media type in %q", errNotAcceptable, accept)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].format, nil
+}
+
+// encode serializes obj in the given format. CSV is only available for the
+// catalog types encodeCSV knows how to flatten, and Markdown for shopping
+// lists.
+func encode(f format, obj interface{}) ([]byte, error) {
+	switch f {
+	case formatPrettyJSON:
+		return json.MarshalIndent(obj, "", "    ")
+	case formatYAML:
+		return encodeYAML(obj)
+	case formatCSV:
+		return encodeCSV(obj)
+	case formatMarkdown:
+		return encodeMarkdown(obj)
+	case formatMsgPack:
+		var buf bytes.Buffer
+		h := &codec.MsgpackHandle{WriteExt: true}
+		err := codec.NewEncoder(&buf, h).Encode(obj)
+		return buf.Bytes(), err
+	default:
+		return json.Marshal(obj)
+	}
+}
+
+// encodeYAML goes through JSON so the YAML keys and omitted fields match the
+// json struct tags, then decodes into a yaml.Node to keep the field order.
+func encodeYAML(obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	blockStyle(&doc)
+
+	return yaml.Marshal(&doc)
+}
+
+// blockStyle clears the flow and quoting styles the JSON input left on n.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
+
+// negotiate encodes obj in the format the client asked for and returns the
+// body and its content type. It answers the request itself on failure.
+func negotiate(c *gin.Context, obj interface{}) ([]byte, string, bool) {
+	c.Header("Vary", "Accept")
+
+	f, err := negotiateFormat(c)
+	if err != nil {
+		c.String(http.StatusNotAcceptable, "%s, supported formats are json, pretty, yaml, csv, msgpack and markdown", err)
+		return nil, "", false
+	}
+
+	body, err := encode(f, obj)
+	if errors.Is(err, errNotAcceptable) {
+		c.String(http.StatusNotAcceptable, "%s", err)
+		return nil, "", false
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "error encoding response: %s", err)
+		return nil, "", false
+	}
+
+	return body, contentTypes[f], true
+}
+
+// render writes obj in the negotiated format.
+func render(c *gin.Context, status int, obj interface{}) {
+	body, contentType, ok := negotiate(c, obj)
+	if !ok {
+		return
+	}
+
+	c.Data(status, contentType, body)
+}
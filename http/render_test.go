@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+type catalog struct {
+	drinkee.DrinkService
+}
+
+func (catalog) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	return []*drinkee.Drink{
+		{
+			ID:           1,
+			Name:         "negroni",
+			DisplayName:  "Negroni",
+			Instructions: "Stir with ice.",
+			DrinkIngredients: drinkee.DrinkIngredientSlice{
+				{Name: "gin", DisplayName: "Gin", Measurement: "1 oz"},
+				{Name: "campari", DisplayName: "Campari", Measurement: "1 oz"},
+			},
+		},
+	}, nil
+}
+
+func getDrinks(s *drinkeehttp.Server, query, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/drinks"+query, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRenderDefaultsToCompactJSON(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	w := getDrinks(s, "", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	assert.NotContains(t, w.Body.String(), "\n")
+
+	w = getDrinks(s, "?format=pretty", "")
+	assert.Contains(t, w.Body.String(), "\n    ")
+}
+
+func TestRenderNegotiatesAcceptHeader(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	w := getDrinks(s, "", "text/html, application/yaml;q=0.5, application/json;q=0.4")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "- id: 1\n  name: negroni\n  displayName: Negroni\n")
+
+	w = getDrinks(s, "", "application/msgpack")
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+	var drinks []drinkee.Drink
+	err := codec.NewDecoder(w.Body, &codec.MsgpackHandle{}).Decode(&drinks)
+	assert.NoError(t, err)
+	assert.Equal(t, "Negroni", drinks[0].DisplayName)
+	assert.Len(t, drinks[0].DrinkIngredients, 2)
+
+	w = getDrinks(s, "", "text/html")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
+
+func TestRenderFlattensDrinksToCSV(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	// the query parameter wins over the Accept header
+	w := getDrinks(s, "?format=csv", "application/json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"drinkId", "drinkName", "drinkDisplayName", "ingredientName", "ingredientDisplayName", "measurement"},
+		{"1", "negroni", "Negroni", "gin", "Gin", "1 oz"},
+		{"1", "negroni", "Negroni", "campari", "Campari", "1 oz"},
+	}, records)
+
+	// non-catalog responses have no tabular form
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthz?format=csv", nil)
+	s.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
+
+func TestETagDependsOnFormat(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = catalog{}
+
+	json := getDrinks(s, "", "")
+	yaml := getDrinks(s, "?format=yaml", "")
+	assert.NotEqual(t, json.Header().Get("ETag"), yaml.Header().Get("ETag"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/drinks?format=yaml", nil)
+	req.Header.Set("If-None-Match", yaml.Header().Get("ETag"))
+	s.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleGetDrinkRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid drink id")
+		return
+	}
+
+	revisions, err := s.DrinkRevisionService.FindDrinkRevisions(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no revisions of drink %d", id))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting revisions: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusOK, revisions, &Meta{Count: len(revisions)})
+}
+
+func (s *Server) handleGetDrinkRevision(c *gin.Context) {
+	id, number, err := revisionParams(c)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	revision, status, err := s.findDrinkRevision(c, id, number)
+	if err != nil {
+		renderError(c, status, errorCode(status), err.Error())
+		return
+	}
+
+	renderData(c, http.StatusOK, revision, nil)
+}
+
+func (s *Server) handleDiffDrinkRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid drink id")
+		return
+	}
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "from and to must be revision numbers")
+		return
+	}
+
+	a, status, err := s.findDrinkRevision(c, id, from)
+	if err != nil {
+		renderError(c, status, errorCode(status), err.Error())
+		return
+	}
+	b, status, err := s.findDrinkRevision(c, id, to)
+	if err != nil {
+		renderError(c, status, errorCode(status), err.Error())
+		return
+	}
+
+	renderData(c, http.StatusOK, drinkee.DiffDrinkRevisions(a, b), nil)
+}
+
+func (s *Server) handleRevertDrink(c *gin.Context) {
+	id, number, err := revisionParams(c)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	drink, err := s.DrinkRevisionService.RevertDrink(c, id, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("drink %d has no revision %d", id, number))
+		return
+	}
+	if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		renderError(c, http.StatusBadRequest, codeAmbiguousIngredient, err.Error())
+		return
+	}
+	if errors.Is(err, drinkee.ErrInvalidDrink) {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, drinkee.ErrConflict) {
+		renderError(c, http.StatusConflict, codeConflict, err.Error())
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error reverting drink: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusOK, drink, nil)
+}
+
+// findDrinkRevision returns the status to respond with when it fails.
+func (s *Server) findDrinkRevision(c *gin.Context, id, number int) (*drinkee.DrinkRevision, int, error) {
+	revision, err := s.DrinkRevisionService.FindDrinkRevision(c, id, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, http.StatusNotFound, fmt.Errorf("drink %d has no revision %d", id, number)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error getting revision: %w", err)
+	}
+	return revision, 0, nil
+}
+
+func revisionParams(c *gin.Context) (id, number int, err error) {
+	if id, err = strconv.Atoi(c.Param("id")); err != nil {
+		return 0, 0, errors.New("invalid drink id")
+	}
+	if number, err = strconv.Atoi(c.Param("number")); err != nil {
+		return 0, 0, errors.New("invalid revision number")
+	}
+	return id, number, nil
+}
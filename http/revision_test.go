@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// revisions holds two revisions of drink 1, a negroni made sweeter.
+type revisions struct {
+	actors    []string
+	clientIPs []string
+}
+
+var negroniRevisions = []*drinkee.DrinkRevision{
+	{DrinkID: 1, Number: 1, Action: drinkee.RevisionCreated, Actor: "ana", Drink: drinkee.DrinkSnapshot{
+		Name: "negroni", DisplayName: "Negroni", Instructions: "Stir with ice.",
+		DrinkIngredients: drinkee.DrinkIngredientSlice{
+			{Name: "gin", Measurement: "1 oz"},
+			{Name: "campari", Measurement: "1 oz"},
+			{Name: "sweet vermouth", Measurement: "1 oz"},
+		},
+	}},
+	{DrinkID: 1, Number: 2, Action: drinkee.RevisionUpdated, Drink: drinkee.DrinkSnapshot{
+		Name: "negroni", DisplayName: "Negroni", Instructions: "Stir with ice, strain over a large cube.",
+		DrinkIngredients: drinkee.DrinkIngredientSlice{
+			{Name: "gin", Measurement: "1 oz"},
+			{Name: "sweet vermouth", Measurement: "1 1/2 oz"},
+			{Name: "orange", Measurement: "1 twist", Role: drinkee.RoleGarnish, Optional: true},
+		},
+		DrinkMetadata: drinkee.DrinkMetadata{Ice: "large cube"},
+	}},
+}
+
+func (r *revisions) FindDrinkRevisions(ctx context.Context, drinkID int) ([]*drinkee.DrinkRevision, error) {
+	if drinkID != 1 {
+		return nil, sql.ErrNoRows
+	}
+	return []*drinkee.DrinkRevision{negroniRevisions[1], negroniRevisions[0]}, nil
+}
+
+func (r *revisions) FindDrinkRevision(ctx context.Context, drinkID, number int) (*drinkee.DrinkRevision, error) {
+	if drinkID != 1 || number < 1 || number > len(negroniRevisions) {
+		return nil, sql.ErrNoRows
+	}
+	return negroniRevisions[number-1], nil
+}
+
+func (r *revisions) RevertDrink(ctx context.Context, drinkID, number int) (*drinkee.Drink, error) {
+	rev, err := r.FindDrinkRevision(ctx, drinkID, number)
+	if err != nil {
+		return nil, err
+	}
+	r.actors = append(r.actors, drinkee.ActorFromContext(ctx))
+	r.clientIPs = append(r.clientIPs, drinkee.ClientIPFromContext(ctx))
+	return &drinkee.Drink{ID: 1, Name: rev.Drink.Name, DisplayName: rev.Drink.DisplayName, Slug: "negroni", DrinkIngredients: rev.Drink.DrinkIngredients}, nil
+}
+
+func TestDrinkRevisions(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkRevisionService = &revisions{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/1/revisions", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, env.Meta.Count)
+	assert.Equal(t, float64(2), env.Data.([]interface{})[0].(map[string]interface{})["number"])
+
+	w, _ = serveV2(s, "GET", "/api/v2/drinks/2/revisions", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, env = serveV2(s, "GET", "/api/v2/drinks/1/revisions/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ana", env.Data.(map[string]interface{})["actor"])
+
+	w, env = serveV2(s, "GET", "/api/v2/drinks/1/revisions/3", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "drink 1 has no revision 3", env.Errors[0].Message)
+}
+
+func TestDiffDrinkRevisions(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkRevisionService = &revisions{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/1/revisions/diff?from=1&to=2", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	diff := env.Data.(map[string]interface{})
+
+	var fields []string
+	for _, f := range diff["fields"].([]interface{}) {
+		fields = append(fields, f.(map[string]interface{})["field"].(string))
+	}
+	assert.Equal(t, []string{"instructions", "ice"}, fields)
+
+	changes := map[string]map[string]interface{}{}
+	for _, c := range diff["ingredients"].([]interface{}) {
+		change := c.(map[string]interface{})
+		changes[change["name"].(string)] = change
+	}
+	assert.Len(t, changes, 3)
+	assert.Equal(t, "1 1/2 oz", changes["sweet vermouth"]["to"].(map[string]interface{})["measurement"])
+	assert.Nil(t, changes["orange"]["from"])
+	assert.Nil(t, changes["campari"]["to"])
+
+	w, _ = serveV2(s, "GET", "/api/v2/drinks/1/revisions/diff?from=1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = serveV2(s, "GET", "/api/v2/drinks/1/revisions/diff?from=1&to=9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevertDrink(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	svc := &revisions{}
+	s.DrinkRevisionService = svc
+
+	req, _ := http.NewRequest("POST", "/api/v2/drinks/1/revisions/1/revert", nil)
+	req.Header.Set(drinkeehttp.ActorHeader, "ben")
+	req.RemoteAddr = "203.0.113.7:51234"
+	// not from a trusted proxy, so ignored
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	w := httptest.NewRecorder()
+	s.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"ben"}, svc.actors)
+	assert.Equal(t, []string{"203.0.113.7"}, svc.clientIPs)
+
+	w, _ = serveV2(s, "POST", "/api/v2/drinks/1/revisions/7/revert", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, _ = serveV2(s, "POST", "/api/v2/drinks/1/revisions/latest/revert", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
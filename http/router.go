@@ -1,29 +1,144 @@
 package http
 
 import (
+	"github.com/dylanconnolly/drinkee/metrics"
 	"github.com/gin-gonic/gin"
 )
 
 func (s *Server) GenerateRoutes(r *gin.Engine) {
+	r.GET("/healthz", func(c *gin.Context) {
+		s.handleHealthz(c)
+	})
+	r.GET("/readyz", func(c *gin.Context) {
+		s.handleReadyz(c)
+	})
+	r.GET("/version", func(c *gin.Context) {
+		s.handleVersion(c)
+	})
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	r.GET("/openapi.json", func(c *gin.Context) {
+		s.handleOpenAPISpec(c)
+	})
+	r.GET("/docs", func(c *gin.Context) {
+		s.handleDocs(c)
+	})
+	r.GET("/graphql", func(c *gin.Context) {
+		s.handleGraphQL(c)
+	})
+	r.POST("/graphql", func(c *gin.Context) {
+		s.handleGraphQL(c)
+	})
+	r.GET("/images/*key", func(c *gin.Context) {
+		s.handleImageFile(c)
+	})
+
 	api := r.Group("/api")
 	{
 		v1 := api.Group("/v1")
+		v1.Use(s.validateRequest(invalidRequestV1))
 		{
 			v1.GET("/drinks/:id", func(c *gin.Context) {
 				s.handleGetDrinkByID(c)
 			})
+			v1.GET("/drinks/by-slug/:slug", func(c *gin.Context) {
+				s.handleGetDrinkBySlug(c)
+			})
 			v1.GET("/drinks", func(c *gin.Context) {
 				s.handleGetDrinks(c)
 			})
 			v1.POST("/drinks", func(c *gin.Context) {
 				s.handleCreateDrink(c)
 			})
+			v1.POST("/drinks/:id/image", func(c *gin.Context) {
+				s.handleSetDrinkImage(c)
+			})
+			v1.GET("/drinks/:id/similar", func(c *gin.Context) {
+				s.handleGetSimilarDrinks(c)
+			})
 			v1.POST("/generateDrinks", func(c *gin.Context) {
 				s.handleGenerateDrinks(c)
 			})
 			v1.GET("/ingredients", func(c *gin.Context) {
 				s.handleGetIngredients(c)
 			})
+			v1.POST("/shoppingList", func(c *gin.Context) {
+				s.handleShoppingList(c)
+			})
+			v1.GET("/events", func(c *gin.Context) {
+				s.handleEvents(c)
+			})
+		}
+
+		v2 := api.Group("/v2")
+		v2.Use(s.validateRequest(invalidRequestV2))
+		{
+			v2.GET("/drinks/:id", func(c *gin.Context) {
+				s.handleGetDrinkV2(c)
+			})
+			v2.GET("/drinks/by-slug/:slug", func(c *gin.Context) {
+				s.handleGetDrinkBySlugV2(c)
+			})
+			v2.GET("/drinks", func(c *gin.Context) {
+				s.handleGetDrinksV2(c)
+			})
+			v2.POST("/drinks", func(c *gin.Context) {
+				s.handleCreateDrinkV2(c)
+			})
+			v2.POST("/drinks/:id/image", func(c *gin.Context) {
+				s.handleSetDrinkImageV2(c)
+			})
+			v2.GET("/drinks/:id/similar", func(c *gin.Context) {
+				s.handleGetSimilarDrinksV2(c)
+			})
+			v2.GET("/drinks/:id/revisions", func(c *gin.Context) {
+				s.handleGetDrinkRevisions(c)
+			})
+			v2.GET("/drinks/:id/revisions/diff", func(c *gin.Context) {
+				s.handleDiffDrinkRevisions(c)
+			})
+			v2.GET("/drinks/:id/revisions/:number", func(c *gin.Context) {
+				s.handleGetDrinkRevision(c)
+			})
+			v2.POST("/drinks/:id/revisions/:number/revert", func(c *gin.Context) {
+				s.handleRevertDrink(c)
+			})
+			v2.POST("/drinks/generate", func(c *gin.Context) {
+				s.handleGenerateDrinksV2(c)
+			})
+			v2.GET("/ingredients", func(c *gin.Context) {
+				s.handleGetIngredientsV2(c)
+			})
+			v2.GET("/ingredients/aliases", func(c *gin.Context) {
+				s.handleGetIngredientAliases(c)
+			})
+			v2.POST("/ingredients/aliases", func(c *gin.Context) {
+				s.handleCreateIngredientAlias(c)
+			})
+			v2.DELETE("/ingredients/aliases/:id", func(c *gin.Context) {
+				s.handleDeleteIngredientAlias(c)
+			})
+			v2.POST("/shopping-list", func(c *gin.Context) {
+				s.handleShoppingListV2(c)
+			})
+
+			v2.POST("/webhooks/subscriptions", func(c *gin.Context) {
+				s.handleCreateWebhookSubscription(c)
+			})
+			v2.GET("/webhooks/subscriptions", func(c *gin.Context) {
+				s.handleGetWebhookSubscriptions(c)
+			})
+			v2.DELETE("/webhooks/subscriptions/:id", func(c *gin.Context) {
+				s.handleDeleteWebhookSubscription(c)
+			})
+			v2.GET("/webhooks/deliveries", func(c *gin.Context) {
+				s.handleGetWebhookDeliveries(c)
+			})
+			v2.GET("/webhooks/deliveries/:id", func(c *gin.Context) {
+				s.handleGetWebhookDelivery(c)
+			})
+			v2.POST("/webhooks/deliveries/:id/redeliver", func(c *gin.Context) {
+				s.handleRedeliverWebhook(c)
+			})
 		}
 	}
 }
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/gin-gonic/gin"
+)
+
+// maxServings keeps scaled recipes to something a bar could batch.
+const maxServings = 1000
+
+var errServings = errors.New("servings must be a number above 0 and at most 1000")
+
+// scaleDrink applies the servings or batchVolume, units and dilute query
+// parameters, returning d as it is when none are given. Any error is the
+// client's.
+func scaleDrink(c *gin.Context, d *drinkee.Drink) (*drinkee.Drink, error) {
+	servingsParam, batchParam := c.Query("servings"), c.Query("batchVolume")
+	units, diluteParam := c.Query("units"), c.Query("dilute")
+	if servingsParam == "" && batchParam == "" && units == "" && diluteParam == "" {
+		return d, nil
+	}
+
+	if servingsParam != "" && batchParam != "" {
+		return nil, errors.New("servings and batchVolume can't be combined")
+	}
+	if units != "" && units != measure.Metric && units != measure.US {
+		return nil, errors.New("units must be metric or us")
+	}
+	dilute := diluteParam == "true"
+
+	servings := 1.0
+	switch {
+	case servingsParam != "":
+		n, err := strconv.ParseFloat(servingsParam, 64)
+		if err != nil {
+			return nil, errServings
+		}
+		servings = n
+	case batchParam != "":
+		ml, ok := measure.ParseVolume(batchParam)
+		if !ok {
+			return nil, errors.New("batchVolume must be a volume such as 1500ml, 2l or 64oz")
+		}
+		n, err := measure.BatchServings(d, ml, dilute)
+		if err != nil {
+			return nil, err
+		}
+		servings = n
+	}
+	// written so NaN fails too
+	if !(servings > 0 && servings <= maxServings) {
+		return nil, errServings
+	}
+
+	return measure.ScaleDrink(d, measure.Scaling{Servings: servings, Units: units, Dilute: dilute})
+}
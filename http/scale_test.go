@@ -0,0 +1,69 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// measured serves a negroni with parsed quantities and stats.
+type measured struct {
+	catalog
+}
+
+func (measured) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	ml := 30.0
+	ingredient := func(name string) drinkee.DrinkIngredient {
+		return drinkee.DrinkIngredient{Name: name, Measurement: "30ml", Quantity: &drinkee.Quantity{Amount: 30, Unit: "ml", ML: &ml}}
+	}
+	return &drinkee.Drink{
+		ID:   1,
+		Name: "negroni",
+		DrinkIngredients: drinkee.DrinkIngredientSlice{
+			ingredient("gin"), ingredient("campari"), ingredient("sweet vermouth"),
+			{Name: "orange", Measurement: "1 twist", Quantity: &drinkee.Quantity{Amount: 1, Unit: "twist"}},
+		},
+		Stats: &drinkee.DrinkStats{Volume: 108, ABV: 24.1, StandardDrinks: 1.5, Calories: 180},
+	}, nil
+}
+
+func TestScaleDrink(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = measured{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/1?servings=8&units=us", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	data := env.Data.(map[string]interface{})
+	ingredients := data["drinkIngredients"].([]interface{})
+	assert.Equal(t, "8 oz", ingredients[0].(map[string]interface{})["measurement"])
+	assert.Equal(t, "8 twists", ingredients[3].(map[string]interface{})["measurement"])
+	assert.Equal(t, map[string]interface{}{"servings": 8.0, "units": "us", "volumeMl": 720.0}, data["scale"])
+
+	w, env = serveV2(s, "GET", "/api/v2/drinks/1?batchVolume=1.08l&dilute=true", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	data = env.Data.(map[string]interface{})
+	ingredients = data["drinkIngredients"].([]interface{})
+	assert.Len(t, ingredients, 5)
+	assert.Equal(t, "180 ml", ingredients[4].(map[string]interface{})["measurement"])
+	assert.Equal(t, 10.0, data["scale"].(map[string]interface{})["servings"])
+}
+
+func TestScaleDrinkRejectsBadParameters(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = measured{}
+
+	for _, query := range []string{"servings=0", "servings=NaN", "servings=nan", "servings=-Inf", "servings=Inf", "servings=lots", "servings=2&batchVolume=1l", "batchVolume=a+bucket", "units=imperial"} {
+		w, env := serveV2(s, "GET", "/api/v2/drinks/1?"+query, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, "bad_request", env.Errors[0].Code, query)
+	}
+
+	s.DrinkService = catalog{}
+	w, _ := serveV2(s, "GET", "/api/v1/drinks/1?batchVolume=2l", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "drink has no known volume", w.Body.String())
+}
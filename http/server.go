@@ -1,13 +1,16 @@
 package http
 
 import (
-	"fmt"
-	"io"
+	"context"
+	"net"
 	"net/http"
 	"os"
-	"time"
+	"sync"
 
 	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/metrics"
+	"github.com/getkin/kin-openapi/routers"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -16,44 +19,81 @@ type Server struct {
 	server       *http.Server
 	Router       *gin.Engine
 	DrinkService drinkee.DrinkService
+	Logger       logger.Logger
+
+	WebhookService drinkee.WebhookService
+	// AllowHTTPWebhooks lets subscriptions use plain http URLs, https only
+	// otherwise.
+	AllowHTTPWebhooks bool
+	EventService      drinkee.EventService
+	ImageService      drinkee.ImageService
+	// ImageFS serves /images when images are kept in a local blob.FS.
+	ImageFS http.FileSystem
+
+	ShoppingListService    drinkee.ShoppingListService
+	SimilarityService      drinkee.SimilarityService
+	IngredientAliasService drinkee.IngredientAliasService
+	DrinkRevisionService   drinkee.DrinkRevisionService
+
+	HealthService drinkee.HealthService
+	// MigrationVersion is the schema version this binary expects; /readyz fails below it.
+	MigrationVersion uint
+	BuildInfo        drinkee.BuildInfo
+
+	// set by EnableRequestValidation
+	openAPIRouter routers.Router
+
+	// closed on Close so long-lived event streams end instead of holding up
+	// the shutdown
+	shutdown chan struct{}
 }
 
 func NewServer() *Server {
 	s := &Server{
 		server: &http.Server{},
 		Router: gin.New(),
+		Logger: logger.NewJSONLogger(os.Stdout, logger.InfoLevel),
+
+		shutdown: make(chan struct{}),
 	}
+	var shutdownOnce sync.Once
+	s.server.RegisterOnShutdown(func() {
+		shutdownOnce.Do(func() { close(s.shutdown) })
+	})
+
+	// let handlers pass *gin.Context as a context.Context and still see values
+	// stored on the request context by middleware
+	s.Router.ContextWithFallback = true
+	// client IPs are recorded on revisions, so X-Forwarded-For is ignored
+	// unless the proxies sending it are configured
+	s.Router.SetTrustedProxies(nil)
 
+	s.Router.Use(s.requestContext())
+	s.Router.Use(s.tracing())
+	s.Router.Use(s.accessLog())
 	s.Router.Use(cors.Default())
+	// outside Recovery, so panics are counted as the 500s they become
+	s.Router.Use(metrics.Middleware())
 	s.Router.Use(gin.Recovery())
-	s.SetLogOutputDest()
-	s.ApplyLogFormat()
 
 	s.GenerateRoutes(s.Router)
 
 	return s
 }
 
-func (s *Server) Serve() {
-	s.Router.Run()
-}
+// Open starts serving on addr in the background.
+func (s *Server) Open(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
 
-func (s *Server) SetLogOutputDest() {
-	file, _ := os.Create("router.log")
-	gin.DefaultWriter = io.MultiWriter(file, os.Stdout)
+	s.server.Handler = s.Router
+	go s.server.Serve(ln)
+	return nil
 }
 
-func (s *Server) ApplyLogFormat() {
-	s.Router.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s \"%s %s %s %d %s\" \"%s\" \"%s\"\n",
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	}))
+// Close stops accepting connections and waits for in-flight requests until ctx is done.
+func (s *Server) Close(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
 }
@@ -0,0 +1,29 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/dylanconnolly/drinkee/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicsAreCountedAsServerErrors(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.Router.GET("/panics", func(c *gin.Context) {
+		panic("boom")
+	})
+	counter := metrics.HTTPRequests.WithLabelValues("GET", "/panics", "500")
+	before := testutil.ToFloat64(counter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/panics", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
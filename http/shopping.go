@@ -0,0 +1,47 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleShoppingList(c *gin.Context) {
+	list, status, err := s.buildShoppingList(c)
+	if err != nil {
+		c.String(status, "%s", err)
+		return
+	}
+
+	render(c, http.StatusOK, list)
+}
+
+func (s *Server) handleShoppingListV2(c *gin.Context) {
+	list, status, err := s.buildShoppingList(c)
+	if err != nil {
+		renderError(c, status, errorCode(status), err.Error())
+		return
+	}
+
+	renderData(c, http.StatusOK, list, nil)
+}
+
+// buildShoppingList returns the status to respond with when it fails.
+func (s *Server) buildShoppingList(c *gin.Context) (*drinkee.ShoppingList, int, error) {
+	var req drinkee.ShoppingListRequest
+	if err := bindJSON(c, &req); err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid JSON in request body: %w", err)
+	}
+
+	list, err := s.ShoppingListService.BuildShoppingList(c, &req)
+	if errors.Is(err, drinkee.ErrInvalidShoppingList) {
+		return nil, http.StatusBadRequest, err
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error building shopping list: %w", err)
+	}
+	return list, http.StatusOK, nil
+}
@@ -0,0 +1,95 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type shoppingLists struct{}
+
+func (shoppingLists) BuildShoppingList(ctx context.Context, r *drinkee.ShoppingListRequest) (*drinkee.ShoppingList, error) {
+	if r.Drinks[0].ID != 1 {
+		return nil, fmt.Errorf("%w: no drink with id %d", drinkee.ErrInvalidShoppingList, r.Drinks[0].ID)
+	}
+	buy := 1650.0
+	return &drinkee.ShoppingList{
+		Categories: []drinkee.ShoppingCategory{
+			{Category: drinkee.CategorySpirit, Items: []drinkee.ShoppingItem{{
+				Name: "gin", DisplayName: "Gin", Needed: "1.65 l", Buy: "1 × 1000 ml + 1 × 700 ml", BuyML: &buy,
+				Bottles: []drinkee.Bottle{{Size: 1000, Count: 1}, {Size: 700, Count: 1}},
+				Drinks:  []string{"Gimlet", "Tom_Collins"},
+			}}},
+			{Category: drinkee.CategoryProduce, Items: []drinkee.ShoppingItem{{
+				Name: "lime", DisplayName: "Lime", Needed: "30 wheels", Buy: "30 wheels", Drinks: []string{"Gimlet"},
+			}}},
+		},
+		Covered: []string{"simple syrup"},
+	}, nil
+}
+
+func postShoppingList(s *drinkeehttp.Server, path, body, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestShoppingList(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.ShoppingListService = shoppingLists{}
+
+	w, env := serveV2(s, "POST", "/api/v2/shopping-list", `{"drinks": [{"id": 1, "servings": 20}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	categories := env.Data.(map[string]interface{})["categories"].([]interface{})
+	assert.Equal(t, "spirit", categories[0].(map[string]interface{})["category"])
+
+	w = postShoppingList(s, "/api/v1/shoppingList", `{"drinks": [{"id": 1, "servings": 20}]}`, "text/markdown")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `# Shopping List
+
+## Spirits
+
+- [ ] **Gin**: 1 × 1000 ml + 1 × 700 ml (1.65 l needed) _for Gimlet, Tom\_Collins_
+
+## Produce
+
+- [ ] **Lime**: 30 wheels _for Gimlet_
+
+Already in the pantry: simple syrup
+`, w.Body.String())
+}
+
+func TestShoppingListErrors(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.ShoppingListService = shoppingLists{}
+
+	w, env := serveV2(s, "POST", "/api/v2/shopping-list", `{"drinks": [{"id": 9, "servings": 1}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid shopping list: no drink with id 9", env.Errors[0].Message)
+
+	w, _ = serveV2(s, "POST", "/api/v2/shopping-list", `{"drinks": [{"id": 1, "servings": 0}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = serveV2(s, "POST", "/api/v2/shopping-list", `{"drinks": [{"id": 1, "servings": 1}], "units": "imperial"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Markdown is only for shopping lists
+	s.DrinkService = catalog{}
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/drinks?format=markdown", nil)
+	s.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
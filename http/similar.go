@@ -0,0 +1,80 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+)
+
+func (s *Server) handleGetSimilarDrinks(c *gin.Context) {
+	drinks, status, err := s.findSimilarDrinks(c)
+	if err != nil {
+		c.String(status, "%s", err)
+		return
+	}
+
+	renderETag(c, http.StatusOK, drinks)
+}
+
+func (s *Server) handleGetSimilarDrinksV2(c *gin.Context) {
+	drinks, status, err := s.findSimilarDrinks(c)
+	if err != nil {
+		renderError(c, status, errorCode(status), err.Error())
+		return
+	}
+
+	renderETag(c, http.StatusOK, Envelope{
+		Data:   drinks,
+		Meta:   &Meta{Count: len(drinks), Limit: similarLimit(c)},
+		Errors: []APIError{},
+	})
+}
+
+// findSimilarDrinks returns the status to respond with when it fails.
+func (s *Server) findSimilarDrinks(c *gin.Context) ([]*drinkee.SimilarDrink, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid drink id")
+	}
+
+	limit := similarLimit(c)
+	if limit < 1 || limit > maxSimilarLimit {
+		return nil, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxSimilarLimit)
+	}
+
+	drinks, err := s.SimilarityService.FindSimilarDrinks(c, id, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, http.StatusNotFound, fmt.Errorf("no drink with id %d", id)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error finding similar drinks: %w", err)
+	}
+	if drinks == nil {
+		drinks = []*drinkee.SimilarDrink{}
+	}
+	return drinks, http.StatusOK, nil
+}
+
+// similarLimit reads the limit query parameter, defaultSimilarLimit when it's
+// missing and 0 when it isn't a number.
+func similarLimit(c *gin.Context) int {
+	param := c.Query("limit")
+	if param == "" {
+		return defaultSimilarLimit
+	}
+	limit, err := strconv.Atoi(param)
+	if err != nil {
+		return 0
+	}
+	return limit
+}
@@ -0,0 +1,79 @@
+package http_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// similarDrinks knows drink 1, a gimlet, and records the limit asked for.
+type similarDrinks struct {
+	limit *int
+}
+
+func (s similarDrinks) FindSimilarDrinks(ctx context.Context, id int, limit int) ([]*drinkee.SimilarDrink, error) {
+	*s.limit = limit
+	if id != 1 {
+		return nil, sql.ErrNoRows
+	}
+	return []*drinkee.SimilarDrink{{
+		Drink: drinkee.Drink{ID: 2, Name: "gin_rickey", DisplayName: "Gin Rickey"},
+		Similarity: drinkee.Similarity{
+			Score:   0.4,
+			Shared:  []drinkee.WeightedIngredient{{Name: "gin", DisplayName: "Gin", Weight: 1.1}, {Name: "lime juice", DisplayName: "Lime Juice", Weight: 0.9}},
+			Missing: []drinkee.WeightedIngredient{{Name: "simple syrup", DisplayName: "Simple Syrup", Weight: 0.5}},
+			Extra:   []drinkee.WeightedIngredient{{Name: "soda water", DisplayName: "Soda Water", Weight: 2.5}},
+		},
+	}}, nil
+}
+
+func TestSimilarDrinks(t *testing.T) {
+	var limit int
+	s := drinkeehttp.NewServer()
+	s.SimilarityService = similarDrinks{limit: &limit}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/drinks/1/similar", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 10, limit)
+	var drinks []drinkee.SimilarDrink
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &drinks))
+	assert.Equal(t, "gin_rickey", drinks[0].Name)
+	assert.Equal(t, 0.4, drinks[0].Similarity.Score)
+	assert.Equal(t, "soda water", drinks[0].Similarity.Extra[0].Name)
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/1/similar?limit=3", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, limit)
+	assert.Equal(t, &drinkeehttp.Meta{Count: 1, Limit: 3}, env.Meta)
+}
+
+func TestSimilarDrinksErrors(t *testing.T) {
+	var limit int
+	s := drinkeehttp.NewServer()
+	s.SimilarityService = similarDrinks{limit: &limit}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/9/similar", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "no drink with id 9", env.Errors[0].Message)
+
+	for _, path := range []string{"/api/v2/drinks/1/similar?limit=0", "/api/v2/drinks/1/similar?limit=51", "/api/v2/drinks/1/similar?limit=ten", "/api/v2/drinks/gimlet/similar"} {
+		w, _ = serveV2(s, "GET", path, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/drinks/9/similar", nil)
+	s.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "no drink with id 9", w.Body.String())
+}
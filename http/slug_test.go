@@ -0,0 +1,77 @@
+package http_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// slugCatalog already has a piña colada.
+type slugCatalog struct {
+	drinkee.DrinkService
+}
+
+var pinaColada = &drinkee.Drink{ID: 7, Name: "pina colada", DisplayName: "Piña Colada", Slug: "pina-colada"}
+
+func (slugCatalog) FindDrinkBySlug(ctx context.Context, slug string) (*drinkee.Drink, error) {
+	if slug != pinaColada.Slug {
+		return nil, sql.ErrNoRows
+	}
+	return pinaColada, nil
+}
+
+func (slugCatalog) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	if drinkee.Slugify(cd.DisplayName) == pinaColada.Slug {
+		return nil, &drinkee.ConflictError{Resource: "drink", ID: pinaColada.ID, Name: pinaColada.Name, Slug: pinaColada.Slug}
+	}
+	return &drinkee.Drink{ID: 8, Name: cd.Name, DisplayName: cd.DisplayName, Slug: drinkee.Slugify(cd.DisplayName)}, nil
+}
+
+func TestGetDrinkBySlug(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = slugCatalog{}
+
+	w, env := serveV2(s, "GET", "/api/v2/drinks/by-slug/pina-colada", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, float64(7), env.Data.(map[string]interface{})["id"])
+
+	w, env = serveV2(s, "GET", "/api/v2/drinks/by-slug/mai-tai", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `no drink with slug "mai-tai"`, env.Errors[0].Message)
+
+	w, _ = serveV2(s, "GET", "/api/v1/drinks/by-slug/pina-colada", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCreateDrinkConflict(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.DrinkService = slugCatalog{}
+
+	body := `{
+		"name": "piña colada",
+		"displayName": "PIÑA  COLADA!",
+		"instructions": "Blend with ice.",
+		"drinkIngredients": [{"name": "white rum", "measurement": "2 oz"}]
+	}`
+	w, env := serveV2(s, "POST", "/api/v2/drinks", body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "conflict", env.Errors[0].Code)
+	assert.Equal(t, `drink "pina colada" already exists with id 7 and slug "pina-colada"`, env.Errors[0].Message)
+
+	w, _ = serveV2(s, "POST", "/api/v1/drinks", body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, env = serveV2(s, "POST", "/api/v2/drinks", `{
+		"name": "gin and tonic",
+		"displayName": "Gin & Tonic",
+		"instructions": "Build over ice.",
+		"drinkIngredients": [{"name": "gin", "measurement": "2 oz"}]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "gin-and-tonic", env.Data.(map[string]interface{})["slug"])
+}
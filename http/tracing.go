@@ -0,0 +1,61 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/gin-gonic/gin"
+)
+
+const TraceparentHeader = "traceparent"
+
+// tracing continues the caller's trace from a W3C traceparent header (or
+// starts a new one) and wraps the rest of the handler chain in a server span.
+// It must run after requestContext so the span can carry the request ID.
+func (s *Server) tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		parent, _ := trace.ParseTraceparent(c.GetHeader(TraceparentHeader))
+		ctx, span := trace.StartServer(c.Request.Context(), c.Request.Method+" "+route, parent,
+			trace.String("http.method", c.Request.Method),
+			trace.String("http.route", route),
+			trace.String("http.target", c.Request.URL.RequestURI()),
+			trace.String("request.id", drinkee.RequestIDFromContext(c.Request.Context())),
+		)
+		defer span.End()
+
+		l := logger.FromContext(ctx, s.Logger).With(logger.F("traceId", span.SpanContext.TraceID.String()))
+		c.Request = c.Request.WithContext(logger.NewContext(ctx, l))
+		c.Header(TraceparentHeader, span.SpanContext.Traceparent())
+
+		c.Next()
+
+		span.SetAttributes(trace.Int("http.status_code", c.Writer.Status()))
+		if c.Writer.Status() >= 500 {
+			span.RecordError(errStatus(c.Writer.Status()))
+		}
+	}
+}
+
+// bindJSON binds the request body inside its own span, which makes slow or
+// oversized payloads visible in traces.
+func bindJSON(c *gin.Context, obj interface{}) error {
+	_, span := trace.Start(c, "http.BindJSON")
+	defer span.End()
+
+	err := c.ShouldBindJSON(obj)
+	span.RecordError(err)
+	return err
+}
+
+type errStatus int
+
+func (e errStatus) Error() string {
+	return "http status " + strconv.Itoa(int(e))
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleCreateWebhookSubscription(c *gin.Context) {
+	var cs drinkee.CreateWebhookSubscription
+	if err := bindJSON(c, &cs); err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("invalid subscription: %s", err))
+		return
+	}
+	if err := cs.Validate(s.AllowHTTPWebhooks); err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	if cs.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error generating secret: %s", err))
+			return
+		}
+		cs.Secret = secret
+	}
+
+	sub, err := s.WebhookService.CreateWebhookSubscription(c, &cs)
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error creating subscription: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusCreated, sub, nil)
+}
+
+func (s *Server) handleGetWebhookSubscriptions(c *gin.Context) {
+	subs, err := s.WebhookService.FindWebhookSubscriptions(c)
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting subscriptions: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusOK, subs, &Meta{Count: len(subs)})
+}
+
+func (s *Server) handleDeleteWebhookSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid subscription id")
+		return
+	}
+
+	err = s.WebhookService.DeleteWebhookSubscription(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no subscription with id %d", id))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error deleting subscription: %s", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) handleGetWebhookDeliveries(c *gin.Context) {
+	f := drinkee.WebhookDeliveryFilter{Limit: 100}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		f.Limit = limit
+	}
+	if skip, err := strconv.Atoi(c.Query("skip")); err == nil {
+		f.Skip = skip
+	}
+	if id, err := strconv.Atoi(c.Query("subscriptionId")); err == nil {
+		f.SubscriptionID = &id
+	}
+	if status := c.Query("status"); status != "" {
+		f.Status = &status
+	}
+
+	deliveries, err := s.WebhookService.FindWebhookDeliveries(c, f)
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting deliveries: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusOK, deliveries, &Meta{Count: len(deliveries), Limit: f.Limit, Skip: f.Skip})
+}
+
+func (s *Server) handleGetWebhookDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid delivery id")
+		return
+	}
+
+	d, err := s.WebhookService.FindWebhookDeliveryByID(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no delivery with id %d", id))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error getting delivery: %s", err))
+		return
+	}
+
+	renderData(c, http.StatusOK, d, nil)
+}
+
+func (s *Server) handleRedeliverWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		renderError(c, http.StatusBadRequest, codeBadRequest, "invalid delivery id")
+		return
+	}
+
+	d, err := s.WebhookService.RedeliverWebhook(c, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		renderError(c, http.StatusNotFound, codeNotFound, fmt.Sprintf("no delivery with id %d", id))
+		return
+	}
+	if err != nil {
+		renderError(c, http.StatusInternalServerError, codeInternalError, fmt.Sprintf("error redelivering: %s", err))
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/v2/webhooks/deliveries/%d", d.ID))
+	renderData(c, http.StatusCreated, d, nil)
+}
@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	drinkeehttp "github.com/dylanconnolly/drinkee/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type webhooks struct {
+	drinkee.WebhookService
+	created *drinkee.CreateWebhookSubscription
+}
+
+func (w *webhooks) CreateWebhookSubscription(ctx context.Context, cs *drinkee.CreateWebhookSubscription) (*drinkee.WebhookSubscription, error) {
+	w.created = cs
+	return &drinkee.WebhookSubscription{ID: 1, URL: cs.URL, Secret: cs.Secret, Events: cs.Events, Active: true}, nil
+}
+
+func (w *webhooks) RedeliverWebhook(ctx context.Context, id int64) (*drinkee.WebhookDelivery, error) {
+	if id != 5 {
+		return nil, sql.ErrNoRows
+	}
+	return &drinkee.WebhookDelivery{ID: 6, SubscriptionID: 1, Status: drinkee.DeliveryPending}, nil
+}
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	wh := &webhooks{}
+	s := drinkeehttp.NewServer()
+	s.WebhookService = wh
+
+	w, env := serveV2(s, "POST", "/api/v2/webhooks/subscriptions", `{"url": "https://example.com/hook", "events": ["drink.created"]}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.True(t, strings.HasPrefix(wh.created.Secret, "whsec_"), "expected a generated secret, got %q", wh.created.Secret)
+	assert.Equal(t, wh.created.Secret, env.Data.(map[string]interface{})["secret"])
+
+	w, env = serveV2(s, "POST", "/api/v2/webhooks/subscriptions", `{"url": "https://example.com/hook", "events": ["drink.renamed"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "bad_request", env.Errors[0].Code)
+
+	w, _ = serveV2(s, "POST", "/api/v2/webhooks/subscriptions", `{"url": "not a url"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookSubscriptionsNeedHTTPS(t *testing.T) {
+	wh := &webhooks{}
+	s := drinkeehttp.NewServer()
+	s.WebhookService = wh
+
+	for _, url := range []string{"http://example.com/hook", "ftp://example.com/hook", "file:///etc/passwd"} {
+		w, env := serveV2(s, "POST", "/api/v2/webhooks/subscriptions", `{"url": "`+url+`"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		assert.Equal(t, "bad_request", env.Errors[0].Code, url)
+	}
+	assert.Nil(t, wh.created)
+
+	s.AllowHTTPWebhooks = true
+	w, _ := serveV2(s, "POST", "/api/v2/webhooks/subscriptions", `{"url": "http://example.com/hook"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w, _ = serveV2(s, "POST", "/api/v2/webhooks/subscriptions", `{"url": "ftp://example.com/hook"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	s := drinkeehttp.NewServer()
+	s.WebhookService = &webhooks{}
+
+	w, env := serveV2(s, "POST", "/api/v2/webhooks/deliveries/5/redeliver", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/v2/webhooks/deliveries/6", w.Header().Get("Location"))
+	assert.Equal(t, "pending", env.Data.(map[string]interface{})["status"])
+
+	w, _ = serveV2(s, "POST", "/api/v2/webhooks/deliveries/9/redeliver", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
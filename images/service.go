@@ -0,0 +1,165 @@
+// Package images validates uploaded drink images, generates their thumbnails
+// and keeps them in a blob.Store.
+package images
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"github.com/dylanconnolly/drinkee/blob"
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/trace"
+
+	// register the WebP decoder with image.Decode
+	_ "golang.org/x/image/webp"
+)
+
+// extensions are the accepted upload types, as sniffed by
+// http.DetectContentType.
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// maxPixels caps the decoded size of an upload at about 160MB of RGBA.
+const maxPixels = 40_000_000
+
+// DrinkImageStore records which image a drink has, implemented by
+// postgres.DrinkService.
+type DrinkImageStore interface {
+	SetDrinkImage(ctx context.Context, id int, img *drinkee.Image) error
+}
+
+var _ drinkee.ImageService = (*Service)(nil)
+
+type Service struct {
+	blobs  blob.Store
+	drinks DrinkImageStore
+	Logger logger.Logger
+
+	// OnChange is called after a drink's image changes, to drop cached drinks.
+	OnChange func(ctx context.Context)
+}
+
+func NewService(blobs blob.Store, drinks DrinkImageStore) *Service {
+	return &Service{blobs: blobs, drinks: drinks, Logger: logger.NewNop()}
+}
+
+func (s *Service) SetDrinkImage(ctx context.Context, drinkID int, r io.Reader) (*drinkee.Image, error) {
+	ctx, span := trace.Start(ctx, "ImageService.SetDrinkImage", trace.Int("drink.id", drinkID))
+	defer span.End()
+
+	data, err := io.ReadAll(io.LimitReader(r, drinkee.MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > drinkee.MaxImageSize {
+		return nil, drinkee.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return nil, drinkee.ErrImageType
+	}
+	// check the dimensions before decoding, a small file can still expand
+	// into more pixels than fit in memory
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", drinkee.ErrImageType, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, drinkee.ErrImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", drinkee.ErrImageType, err)
+	}
+
+	// keys are named after the content so a new image never reuses the URL
+	// of one a client may have cached
+	sum := sha256.Sum256(data)
+	prefix := fmt.Sprintf("drinks/%d/%s/", drinkID, hex.EncodeToString(sum[:8]))
+
+	img, keys, err := s.store(ctx, prefix, data, src, contentType)
+	if err == nil {
+		err = s.drinks.SetDrinkImage(ctx, drinkID, img)
+	}
+	if err != nil {
+		span.RecordError(err)
+		s.cleanUp(ctx, keys)
+		return nil, err
+	}
+
+	if s.OnChange != nil {
+		s.OnChange(ctx)
+	}
+	return img, nil
+}
+
+// store puts the original upload and its thumbnails under prefix, returning
+// the keys written even when it fails part way.
+func (s *Service) store(ctx context.Context, prefix string, data []byte, src image.Image, contentType string) (*drinkee.Image, []string, error) {
+	img := &drinkee.Image{
+		ContentType: contentType,
+		Width:       src.Bounds().Dx(),
+		Height:      src.Bounds().Dy(),
+		Thumbnails:  make(map[string]string, len(ThumbnailSizes)),
+	}
+
+	var keys []string
+	put := func(name string, b []byte, contentType string) (string, error) {
+		key := prefix + name + "." + extensions[contentType]
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(b), int64(len(b)), contentType); err != nil {
+			return "", err
+		}
+		keys = append(keys, key)
+		return s.blobs.URL(key), nil
+	}
+
+	var err error
+	if img.URL, err = put("original", data, contentType); err != nil {
+		return nil, keys, err
+	}
+	for _, t := range ThumbnailSizes {
+		b, thumbType, err := encode(Thumbnail(src, t.Size), contentType)
+		if err != nil {
+			return nil, keys, err
+		}
+		if img.Thumbnails[t.Name], err = put(t.Name, b, thumbType); err != nil {
+			return nil, keys, err
+		}
+	}
+	return img, keys, nil
+}
+
+// encode writes thumbnails of PNGs as PNG to keep transparency and everything
+// else as JPEG, as there is no WebP encoder in the standard library.
+func encode(img image.Image, sourceType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if sourceType == "image/png" {
+		err := png.Encode(&buf, img)
+		return buf.Bytes(), "image/png", err
+	}
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	return buf.Bytes(), "image/jpeg", err
+}
+
+// cleanUp removes blobs written for an upload that failed, so they don't
+// outlive it unreferenced.
+func (s *Service) cleanUp(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx, s.Logger).Warn("error removing image", logger.F("key", key), logger.Err(err))
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package images_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/blob"
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/images"
+	"github.com/stretchr/testify/assert"
+)
+
+type drinkImages map[int]*drinkee.Image
+
+func (d drinkImages) SetDrinkImage(ctx context.Context, id int, img *drinkee.Image) error {
+	if _, ok := d[id]; !ok {
+		return sql.ErrNoRows
+	}
+	d[id] = img
+	return nil
+}
+
+func pngOf(w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// files lists the blobs under dir, relative to it.
+func files(dir string) []string {
+	var names []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return names
+}
+
+func TestSetDrinkImage(t *testing.T) {
+	dir := t.TempDir()
+	drinks := drinkImages{7: nil}
+	s := images.NewService(blob.NewFS(dir, "/images"), drinks)
+
+	changed := false
+	s.OnChange = func(ctx context.Context) { changed = true }
+
+	img, err := s.SetDrinkImage(context.Background(), 7, bytes.NewReader(pngOf(2000, 1000)))
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, img, drinks[7])
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, 2000, img.Width)
+	assert.True(t, strings.HasPrefix(img.URL, "/images/drinks/7/"), img.URL)
+	assert.True(t, strings.HasSuffix(img.Thumbnails["small"], "/small.png"), img.Thumbnails["small"])
+	assert.Len(t, files(dir), 4)
+
+	f, err := os.Open(filepath.Join(dir, strings.TrimPrefix(img.Thumbnails["medium"], "/images/")))
+	assert.NoError(t, err)
+	defer f.Close()
+	thumb, err := png.DecodeConfig(f)
+	assert.NoError(t, err)
+	assert.Equal(t, 480, thumb.Width)
+	assert.Equal(t, 240, thumb.Height)
+}
+
+func TestSetDrinkImageRejectsUploads(t *testing.T) {
+	dir := t.TempDir()
+	s := images.NewService(blob.NewFS(dir, "/images"), drinkImages{7: nil})
+
+	_, err := s.SetDrinkImage(context.Background(), 7, strings.NewReader("GIF89a not allowed"))
+	assert.ErrorIs(t, err, drinkee.ErrImageType)
+
+	// a PNG signature with nothing decodable after it
+	_, err = s.SetDrinkImage(context.Background(), 7, strings.NewReader("\x89PNG\r\n\x1a\ngarbage"))
+	assert.ErrorIs(t, err, drinkee.ErrImageType)
+
+	_, err = s.SetDrinkImage(context.Background(), 7, bytes.NewReader(make([]byte, drinkee.MaxImageSize+1)))
+	assert.ErrorIs(t, err, drinkee.ErrImageTooLarge)
+
+	// blobs written for a drink that doesn't exist are removed again
+	_, err = s.SetDrinkImage(context.Background(), 8, bytes.NewReader(pngOf(10, 10)))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Empty(t, files(dir))
+}
+
+func TestThumbnail(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 300))
+	assert.Equal(t, image.Rect(0, 0, 53, 160), images.Thumbnail(src, 160).Bounds())
+	// never enlarged
+	assert.Equal(t, src.Bounds(), images.Thumbnail(src, 480).Bounds())
+}
@@ -0,0 +1,44 @@
+package images
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Thumbnail sizes, by the length of their longest edge in pixels.
+var ThumbnailSizes = []struct {
+	Name string
+	Size int
+}{
+	{"small", 160},
+	{"medium", 480},
+	{"large", 1024},
+}
+
+// Thumbnail scales src to fit within size x size, keeping its aspect ratio.
+// Images that already fit are returned unchanged rather than enlarged.
+func Thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	if w >= h {
+		w, h = size, max(1, h*size/w)
+	} else {
+		w, h = max(1, w*size/h), size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
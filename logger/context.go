@@ -0,0 +1,20 @@
+package logger
+
+import "context"
+
+type contextKey int
+
+const loggerContextKey = contextKey(0)
+
+// NewContext returns a copy of ctx carrying l, normally a request-scoped child logger.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// FromContext returns the logger stored in ctx, or fallback when there is none.
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if l, ok := ctx.Value(loggerContextKey).(Logger); ok {
+		return l
+	}
+	return fallback
+}
@@ -1,87 +1,193 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"log"
-	"net/url"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	LogsDirectoryPath = "logs"
+
+	DefaultRetentionDays = 14
 )
 
 type Logger interface {
-	// Debug(msg string, fields ...interface{})
-	Debug(lf *LogFields)
-	Info(msg string)
-	Warn(msg string)
-	Error(msg string)
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	// With returns a child logger that adds fields to every entry.
+	With(fields ...Field) Logger
 }
 
-type LogWrapper struct {
-	logger *log.Logger
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	}
+	return "unknown"
+}
+
+// ParseLevel maps a LOG_LEVEL value to a Level, defaulting to info.
+func ParseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLevel
+	case "warn", "warning":
+		return WarnLevel
+	case "error":
+		return ErrorLevel
+	}
+	return InfoLevel
+}
+
+type Field struct {
+	Key   string
+	Value interface{}
 }
 
-type LogFields struct {
-	Message string
-	Fields  interface{}
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
 }
 
-type HttpFields struct {
-	Route  string
-	Method string
-	URL    *url.URL
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
 }
 
+// LogWrapper writes one JSON object per line. Child loggers created with With
+// share the parent's writer and lock.
+type LogWrapper struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	level  Level
+	fields []Field
+	now    func() time.Time
+}
+
+// New builds the application logger from the environment. Without APP_ENV logs
+// go to stdout, otherwise to daily files in LogsDirectoryPath which are kept for
+// LOG_RETENTION_DAYS days.
 func New() *LogWrapper {
+	level := ParseLevel(os.Getenv("LOG_LEVEL"))
+
 	if os.Getenv("APP_ENV") == "" {
-		return &LogWrapper{
-			logger: log.New(os.Stdout, "", log.LstdFlags|log.Lshortfile),
-		}
+		return NewJSONLogger(os.Stdout, level)
 	}
 
-	os.Mkdir(LogsDirectoryPath, 0777)
-
-	year, month, day := time.Now().Date()
-	fileName := fmt.Sprintf("%v-%v-%v.log", year, month, day)
-	filePath, err := os.OpenFile(LogsDirectoryPath+"/"+fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		fmt.Println("error creating logger file: ", err)
-		return nil
+	retention, err := strconv.Atoi(os.Getenv("LOG_RETENTION_DAYS"))
+	if err != nil || retention <= 0 {
+		retention = DefaultRetentionDays
 	}
 
-	logger := log.New(filePath, "", log.LstdFlags)
+	return NewJSONLogger(NewDailyFile(LogsDirectoryPath, retention), level)
+}
 
-	newLogger := &LogWrapper{
-		logger: logger,
+func NewJSONLogger(w io.Writer, level Level) *LogWrapper {
+	return &LogWrapper{
+		mu:    &sync.Mutex{},
+		out:   w,
+		level: level,
+		now:   time.Now,
 	}
+}
+
+// NewNop returns a logger that discards everything.
+func NewNop() *LogWrapper {
+	return NewJSONLogger(io.Discard, ErrorLevel+1)
+}
+
+func (lw *LogWrapper) Debug(msg string, fields ...Field) {
+	lw.log(DebugLevel, msg, fields)
+}
+
+func (lw *LogWrapper) Info(msg string, fields ...Field) {
+	lw.log(InfoLevel, msg, fields)
+}
+
+func (lw *LogWrapper) Warn(msg string, fields ...Field) {
+	lw.log(WarnLevel, msg, fields)
+}
 
-	return newLogger
+func (lw *LogWrapper) Error(msg string, fields ...Field) {
+	lw.log(ErrorLevel, msg, fields)
 }
 
-//	func (lw *LogWrapper) Debug(msg string, fields ...interface{}) {
-//		fmt.Println("fields passed: ", fields)
-//		lw.logger.Printf("level=DEBUG, message=%s, fields=%s", msg, fields)
-//	}
-func (lw *LogWrapper) Debug(lf *LogFields) {
-	lw.logger.SetFlags(log.LstdFlags | log.Lshortfile)
-	fmt.Println("fields passed: ", lf)
-	lw.logger.Printf("level=DEBUG, message=%s, fields=%+v", lf.Message, lf.Fields)
+func (lw *LogWrapper) With(fields ...Field) Logger {
+	child := *lw
+	child.fields = make([]Field, 0, len(lw.fields)+len(fields))
+	child.fields = append(child.fields, lw.fields...)
+	child.fields = append(child.fields, fields...)
+	return &child
 }
 
-func (lw *LogWrapper) Info(msg string) {
-	lw.logger.SetFlags(log.LstdFlags)
-	lw.logger.Printf("level=INFO, message=%s", msg)
+func (lw *LogWrapper) log(level Level, msg string, fields []Field) {
+	if level < lw.level {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, lw.now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, msg)
+	for _, f := range lw.fields {
+		writeField(&buf, f)
+	}
+	for _, f := range fields {
+		writeField(&buf, f)
+	}
+	buf.WriteString("}\n")
+
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	lw.out.Write(buf.Bytes())
 }
 
-func (lw *LogWrapper) Warn(msg string) {
-	lw.logger.SetFlags(log.LstdFlags)
-	lw.logger.Printf("level=WARN, message=%s", msg)
+func writeField(buf *bytes.Buffer, f Field) {
+	buf.WriteByte(',')
+	writeJSON(buf, f.Key)
+	buf.WriteByte(':')
+
+	switch v := f.Value.(type) {
+	case error:
+		writeJSON(buf, v.Error())
+	case time.Duration:
+		writeJSON(buf, v.String())
+	case fmt.Stringer:
+		writeJSON(buf, v.String())
+	default:
+		writeJSON(buf, v)
+	}
 }
 
-func (lw *LogWrapper) Error(msg string) {
-	lw.logger.SetFlags(log.LstdFlags)
-	lw.logger.Printf("level=ERROR, message=%s", msg)
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+	buf.Write(b)
 }
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLoggerLevelsAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf, InfoLevel)
+	l.now = func() time.Time { return time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	l.Debug("hidden")
+	l.With(F("requestId", "abc")).Error("failed", F("status", 500), Err(errors.New("boom")))
+
+	var entry map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "failed", entry["msg"])
+	assert.Equal(t, "abc", entry["requestId"])
+	assert.Equal(t, 500.0, entry["status"])
+	assert.Equal(t, "boom", entry["error"])
+	assert.Equal(t, "2023-05-01T12:00:00Z", entry["time"])
+}
+
+func TestDailyFileRotatesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2023, 5, 1, 23, 0, 0, 0, time.UTC)
+
+	stale := filepath.Join(dir, "2023-04-01.log")
+	assert.NoError(t, os.WriteFile(stale, []byte("old\n"), 0644))
+
+	d := NewDailyFile(dir, 7)
+	d.now = func() time.Time { return day }
+	defer d.Close()
+
+	d.Write([]byte("first\n"))
+	day = day.Add(2 * time.Hour)
+	d.Write([]byte("second\n"))
+
+	first, _ := os.ReadFile(filepath.Join(dir, "2023-05-01.log"))
+	second, _ := os.ReadFile(filepath.Join(dir, "2023-05-02.log"))
+	assert.Equal(t, "first\n", string(first))
+	assert.Equal(t, "second\n", string(second))
+
+	_, err := os.Stat(stale)
+	assert.True(t, os.IsNotExist(err))
+}
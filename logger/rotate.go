@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dailyFileLayout = "2006-01-02"
+
+// DailyFile is an io.Writer that starts a new <dir>/<date>.log file each day
+// and deletes files older than the retention window when it rotates.
+type DailyFile struct {
+	mu            sync.Mutex
+	dir           string
+	retentionDays int
+	now           func() time.Time
+
+	current string
+	file    *os.File
+}
+
+func NewDailyFile(dir string, retentionDays int) *DailyFile {
+	return &DailyFile{
+		dir:           dir,
+		retentionDays: retentionDays,
+		now:           time.Now,
+	}
+}
+
+func (d *DailyFile) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	today := d.now().Format(dailyFileLayout)
+	if d.file == nil || today != d.current {
+		if err := d.rotate(today); err != nil {
+			// fall back to stderr rather than dropping entries
+			return os.Stderr.Write(p)
+		}
+	}
+
+	return d.file.Write(p)
+}
+
+func (d *DailyFile) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == nil {
+		return nil
+	}
+	return d.file.Close()
+}
+
+func (d *DailyFile) rotate(today string) error {
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(d.dir, today+".log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+
+	if d.file != nil {
+		d.file.Close()
+	}
+	d.file = f
+	d.current = today
+
+	d.prune()
+	return nil
+}
+
+// prune removes dated log files that have aged out of the retention window.
+func (d *DailyFile) prune() {
+	if d.retentionDays <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := d.now().AddDate(0, 0, -d.retentionDays).Format(dailyFileLayout)
+	for _, e := range entries {
+		date := strings.TrimSuffix(e.Name(), ".log")
+		if e.IsDir() || date == e.Name() {
+			continue
+		}
+		if _, err := time.Parse(dailyFileLayout, date); err != nil {
+			continue
+		}
+		if date < cutoff {
+			os.Remove(filepath.Join(d.dir, e.Name()))
+		}
+	}
+}
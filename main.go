@@ -1,41 +1,356 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/dylanconnolly/drinkee/blob"
+	"github.com/dylanconnolly/drinkee/cache"
+	"github.com/dylanconnolly/drinkee/db"
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/events"
+	"github.com/dylanconnolly/drinkee/grpc"
 	"github.com/dylanconnolly/drinkee/http"
+	"github.com/dylanconnolly/drinkee/images"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/match"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/dylanconnolly/drinkee/metrics"
 	"github.com/dylanconnolly/drinkee/postgres"
+	"github.com/dylanconnolly/drinkee/shopping"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/dylanconnolly/drinkee/webhook"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 )
 
 const DefaultConfigPath = "~/.env"
 
+// Set at build time, e.g.
+// go build -ldflags "-X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+// Config is read once from the environment and shared by both servers.
+type Config struct {
+	HTTPAddr        string
+	GRPCAddr        string
+	ShutdownTimeout time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		HTTPAddr:        ":8080",
+		GRPCAddr:        ":9090",
+		ShutdownTimeout: 15 * time.Second,
+	}
+
+	// PORT is what gin's Run honoured before HTTP_ADDR existed
+	if port := os.Getenv("PORT"); port != "" {
+		c.HTTPAddr = ":" + port
+	}
+	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
+		c.HTTPAddr = addr
+	}
+	if addr := os.Getenv("GRPC_ADDR"); addr != "" {
+		c.GRPCAddr = addr
+	}
+	if d, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT")); err == nil {
+		c.ShutdownTimeout = d
+	}
+
+	return c
+}
+
 type Main struct {
-	DB         *sqlx.DB
-	HTTPServer *http.Server
+	Config        Config
+	DB            *sqlx.DB
+	HTTPServer    *http.Server
+	GRPCServer    *grpc.Server
+	WebhookWorker *webhook.Worker
+	EventListener *postgres.EventListener
 }
 
-func CreateMain() *Main {
-	db, _ := postgres.CreatePostgresConnection()
+func CreateMain() (*Main, error) {
+	db, err := postgres.CreatePostgresConnection()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Main{
-		DB:         db,
-		HTTPServer: http.NewServer(),
+		Config:        LoadConfig(),
+		DB:            db,
+		HTTPServer:    http.NewServer(),
+		GRPCServer:    grpc.NewServer(),
+		WebhookWorker: webhook.NewWorker(nil),
+	}, nil
+}
+
+// Close shuts both servers down gracefully, lets in-flight webhook deliveries
+// finish, then flushes traces and closes the database.
+func (m *Main) Close(ctx context.Context) error {
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
+
+	record(m.HTTPServer.Close(ctx))
+	record(m.GRPCServer.Close(ctx))
+	record(m.WebhookWorker.Close(ctx))
+	if m.EventListener != nil {
+		record(m.EventListener.Close())
+	}
+	record(trace.Shutdown(ctx))
+	record(m.DB.Close())
+
+	return firstErr
 }
 
 func main() {
-	// fmt.Println("db username env: ", os.Getenv("POSTGRES_USERNAME"))
-	err := godotenv.Load()
+	// until .env is loaded only the defaults are known
+	l := logger.NewJSONLogger(os.Stdout, logger.InfoLevel)
+	fatal := func(msg string, err error) {
+		l.Error(msg, logger.Err(err))
+		os.Exit(1)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		fatal("error loading .env file", err)
+	}
+	l = logger.New()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	migrationVersion, err := db.LatestVersion()
 	if err != nil {
-		fmt.Println(err)
-		log.Fatal("Error loading .env file")
+		fatal("error reading migrations", err)
+	}
+
+	l.Debug("creating main")
+	m, err := CreateMain()
+	if err != nil {
+		fatal("error creating main", err)
+	}
+	if err := metrics.RegisterDBStats(m.DB.DB, os.Getenv("POSTGRES_DBNAME")); err != nil {
+		fatal("error registering db metrics", err)
+	}
+
+	switch os.Getenv("TRACE_EXPORTER") {
+	case "stdout":
+		trace.SetExporter(trace.NewStdoutExporter(os.Stdout))
+	case "otlp":
+		trace.SetExporter(trace.NewOTLPExporter(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "drinkee"))
 	}
 
-	fmt.Println("creating main")
-	m := CreateMain()
 	drinkService := postgres.NewDrinkService(m.DB)
-	m.HTTPServer.DrinkService = drinkService
-	m.HTTPServer.Serve()
+	drinkService.Logger = l
+	drinkService.Estimator, err = newEstimator()
+	if err != nil {
+		fatal("error configuring estimates", err)
+	}
+	if os.Getenv("GENERATE_ENGINE") != "sql" {
+		drinkService.Matcher = match.NewIndex()
+		if err := drinkService.LoadMatcher(ctx); err != nil {
+			fatal("error loading drinks for generation", err)
+		}
+	}
+
+	blobs, err := newBlobStore()
+	if err != nil {
+		fatal("error configuring image store", err)
+	}
+	imageService := images.NewService(blobs, drinkService)
+	imageService.Logger = l
+
+	var ds drinkee.DrinkService = drinkService
+	var cached *cache.DrinkService
+	store, err := newCacheStore()
+	if err != nil {
+		fatal("error configuring cache", err)
+	}
+	if store != nil {
+		cached = cache.NewDrinkService(drinkService, store, cache.DefaultTTL)
+		imageService.OnChange = cached.Invalidate
+		ds = cached
+	}
+
+	// a failed refresh leaves the previous estimates in place
+	if n, err := drinkService.RefreshEstimates(ctx); err != nil {
+		l.Error("error refreshing drink estimates", logger.Err(err))
+	} else if n > 0 && cached != nil {
+		// a shared cache may still hold the old estimates
+		cached.Invalidate(ctx)
+	}
+
+	webhookService := postgres.NewWebhookService(m.DB)
+	webhookService.Logger = l
+
+	aliasService := postgres.NewIngredientAliasService(m.DB)
+	aliasService.Logger = l
+	if cached != nil {
+		aliasService.OnChange = cached.Invalidate
+		drinkService.OnChange = cached.Invalidate
+	}
+
+	broker := events.NewBroker(envInt("EVENTS_BUFFER", events.DefaultBufferSize))
+	m.EventListener = postgres.NewEventListener(m.DB, postgres.ConnectionURL(), broker)
+	m.EventListener.Logger = l
+	m.EventListener.Backfill = envInt("EVENTS_BUFFER", events.DefaultBufferSize)
+	m.EventListener.Retention = time.Duration(envInt("EVENTS_RETENTION_DAYS", int(postgres.DefaultEventRetention.Hours()/24))) * 24 * time.Hour
+	if err := m.EventListener.Open(ctx); err != nil {
+		fatal("error listening for catalog events", err)
+	}
+	if drinkService.Matcher != nil {
+		go drinkService.FollowMatcher(ctx, broker)
+	}
+	if cached != nil {
+		go cached.Follow(logger.NewContext(ctx, l), broker)
+	}
+
+	m.HTTPServer.Logger = l
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		if err := m.HTTPServer.Router.SetTrustedProxies(strings.Split(proxies, ",")); err != nil {
+			fatal("error configuring trusted proxies", err)
+		}
+	}
+	m.HTTPServer.DrinkService = ds
+	m.HTTPServer.WebhookService = webhookService
+	m.HTTPServer.AllowHTTPWebhooks = os.Getenv("WEBHOOK_ALLOW_HTTP") == "true"
+	m.HTTPServer.EventService = broker
+	m.HTTPServer.ImageService = imageService
+	if fs, ok := blobs.(*blob.FS); ok {
+		m.HTTPServer.ImageFS = fs.FileSystem()
+	}
+	shoppingService := shopping.NewService(ds)
+	shoppingService.Resolver = aliasService
+	m.HTTPServer.ShoppingListService = shoppingService
+	m.HTTPServer.SimilarityService = drinkService
+	m.HTTPServer.IngredientAliasService = aliasService
+	m.HTTPServer.DrinkRevisionService = drinkService
+	m.HTTPServer.HealthService = postgres.NewHealthService(m.DB)
+	m.HTTPServer.MigrationVersion = migrationVersion
+	m.HTTPServer.BuildInfo = drinkee.BuildInfo{Commit: commit, BuildTime: buildTime}
+
+	if os.Getenv("OPENAPI_VALIDATE") == "true" {
+		if err := m.HTTPServer.EnableRequestValidation(); err != nil {
+			fatal("error loading openapi spec", err)
+		}
+	}
+
+	m.GRPCServer.Logger = l
+	m.GRPCServer.DrinkService = ds
+
+	m.WebhookWorker.Queue = webhookService
+	m.WebhookWorker.Logger = l
+	if os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true" {
+		m.WebhookWorker.HTTPClient = &nethttp.Client{}
+	}
+
+	if err := m.HTTPServer.Open(m.Config.HTTPAddr); err != nil {
+		fatal("error starting http server", err)
+	}
+	if err := m.GRPCServer.Open(m.Config.GRPCAddr); err != nil {
+		fatal("error starting grpc server", err)
+	}
+	m.WebhookWorker.Open()
+	l.Info("serving", logger.F("httpAddr", m.Config.HTTPAddr), logger.F("grpcAddr", m.Config.GRPCAddr))
+
+	<-ctx.Done()
+	l.Info("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.Config.ShutdownTimeout)
+	defer cancel()
+	if err := m.Close(shutdownCtx); err != nil {
+		l.Error("error shutting down", logger.Err(err))
+	}
+}
+
+// newCacheStore picks the catalog cache backend from CACHE_BACKEND: "memory"
+// (the default), "redis" using REDIS_URL, or "none".
+func newCacheStore() (cache.Store, error) {
+	switch os.Getenv("CACHE_BACKEND") {
+	case "none":
+		return nil, nil
+	case "redis":
+		store, err := cache.NewRedis(os.Getenv("REDIS_URL"), "drinkee:cache")
+		if err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return store, nil
+	default:
+		return cache.NewLRU(envInt("CACHE_SIZE", 1000)), nil
+	}
+}
+
+// newBlobStore picks where drink images are kept from IMAGE_STORE: "fs" (the
+// default) under IMAGE_DIR, served by the http server, or "s3" using S3_URL.
+// IMAGE_BASE_URL changes the URL images are linked with, e.g. to a CDN.
+func newBlobStore() (blob.Store, error) {
+	baseURL := os.Getenv("IMAGE_BASE_URL")
+
+	switch os.Getenv("IMAGE_STORE") {
+	case "s3":
+		store, err := blob.NewS3(os.Getenv("S3_URL"), baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("s3: %w", err)
+		}
+		return store, nil
+	default:
+		dir := os.Getenv("IMAGE_DIR")
+		if dir == "" {
+			dir = "data/images"
+		}
+		if baseURL == "" {
+			baseURL = "/images"
+		}
+		return blob.NewFS(dir, baseURL), nil
+	}
+}
+
+// newEstimator configures drink stats. DILUTION overrides the water added per
+// preparation method, e.g. "shaken=0.3,stirred=0.22", and
+// STANDARD_DRINK_GRAMS the grams of alcohol in a standard drink.
+func newEstimator() (*measure.Estimator, error) {
+	e := measure.NewEstimator()
+
+	e.Dilution = map[string]float64{}
+	for method, f := range measure.DefaultDilution {
+		e.Dilution[method] = f
+	}
+	if dilution := os.Getenv("DILUTION"); dilution != "" {
+		for _, pair := range strings.Split(dilution, ",") {
+			method, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil || f < 0 || !drinkee.Contains(drinkee.Methods, method) {
+				return nil, fmt.Errorf("invalid DILUTION %q", pair)
+			}
+			e.Dilution[method] = f
+		}
+	}
+
+	if grams, err := strconv.ParseFloat(os.Getenv("STANDARD_DRINK_GRAMS"), 64); err == nil && grams > 0 {
+		e.StandardDrink = grams
+	}
+	return e, nil
+}
+
+// envInt reads a positive integer from the environment, or returns def.
+func envInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
 }
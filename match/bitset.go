@@ -0,0 +1,65 @@
+package match
+
+import "math/bits"
+
+// bitset is a growable set of small non-negative integers.
+type bitset []uint64
+
+func (b *bitset) set(i int) {
+	w := i / 64
+	if w >= len(*b) {
+		*b = append(*b, make(bitset, w-len(*b)+1)...)
+	}
+	(*b)[w] |= 1 << (uint(i) % 64)
+}
+
+func (b bitset) clear(i int) {
+	if w := i / 64; w < len(b) {
+		b[w] &^= 1 << (uint(i) % 64)
+	}
+}
+
+func (b bitset) has(i int) bool {
+	w := i / 64
+	return w < len(b) && b[w]&(1<<(uint(i)%64)) != 0
+}
+
+// or adds every member of o to b.
+func (b *bitset) or(o bitset) {
+	if len(o) > len(*b) {
+		*b = append(*b, make(bitset, len(o)-len(*b))...)
+	}
+	for i, w := range o {
+		(*b)[i] |= w
+	}
+}
+
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// intersection counts the members of both b and o.
+func (b bitset) intersection(o bitset) int {
+	if len(o) < len(b) {
+		b, o = o, b
+	}
+	n := 0
+	for i, w := range b {
+		n += bits.OnesCount64(w & o[i])
+	}
+	return n
+}
+
+// each calls f with every member in ascending order.
+func (b bitset) each(f func(i int)) {
+	for wi, w := range b {
+		for w != 0 {
+			f(wi*64 + bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+}
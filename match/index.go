@@ -0,0 +1,352 @@
+// Package match answers drink generation from memory. An Index keeps each
+// drink's ingredients as a bitset, and for each ingredient a bitset of the
+// drinks using it, so a query only looks at drinks sharing an ingredient with
+// the pantry and compares them a word at a time.
+package match
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+// OptionalWeight is what an optional ingredient counts for in Coverage, next
+// to 1 for a required one.
+const OptionalWeight = 0.5
+
+type Drink struct {
+	ID     int
+	Name   string
+	Rating float64
+	// Ingredients are the drink's ingredient IDs. One listed both ways is
+	// required.
+	Ingredients []Ingredient
+}
+
+type Ingredient struct {
+	ID       int
+	Optional bool
+}
+
+// Match is a drink measured against a pantry.
+type Match struct {
+	ID     int
+	Name   string
+	Rating float64
+	// Present counts the drink's ingredients in the pantry, and Missing its
+	// required ones that aren't.
+	Present int
+	Missing int
+	// Coverage is the share of the drink's ingredients in the pantry, with
+	// optional ones counting OptionalWeight, rounded to 4 places.
+	Coverage float64
+}
+
+type entry struct {
+	id       int
+	name     string
+	rating   float64
+	required bitset
+	optional bitset
+	// requiredCount and weight are the totals Missing and Coverage are
+	// worked out against.
+	requiredCount int
+	weight        float64
+}
+
+// Index is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+	// bits numbers ingredient IDs densely, users holds the slots of the
+	// drinks using each
+	bits  map[int]int
+	users []bitset
+	// slots holds the drinks, nil where one was deleted and not yet reused
+	slots []*entry
+	free  []int
+	byID  map[int]int
+}
+
+func NewIndex() *Index {
+	return &Index{bits: map[int]int{}, byID: map[int]int{}}
+}
+
+// Load replaces every drink in the index.
+func (ix *Index) Load(drinks []Drink) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.bits, ix.users = map[int]int{}, nil
+	ix.slots, ix.free = nil, nil
+	ix.byID = make(map[int]int, len(drinks))
+	for _, d := range drinks {
+		ix.remove(d.ID)
+		ix.put(d)
+	}
+}
+
+// Put adds the drink, replacing any with the same ID.
+func (ix *Index) Put(d Drink) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(d.ID)
+	ix.put(d)
+}
+
+func (ix *Index) Delete(id int) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+// Len is the number of drinks in the index.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.byID)
+}
+
+func (ix *Index) put(d Drink) {
+	slot := len(ix.slots)
+	if n := len(ix.free); n > 0 {
+		slot, ix.free = ix.free[n-1], ix.free[:n-1]
+	} else {
+		ix.slots = append(ix.slots, nil)
+	}
+
+	e := &entry{id: d.ID, name: d.Name, rating: d.Rating}
+	for _, i := range d.Ingredients {
+		bit, ok := ix.bits[i.ID]
+		if !ok {
+			bit = len(ix.users)
+			ix.bits[i.ID] = bit
+			ix.users = append(ix.users, nil)
+		}
+		switch {
+		case !i.Optional:
+			e.optional.clear(bit)
+			e.required.set(bit)
+		case !e.required.has(bit):
+			e.optional.set(bit)
+		}
+		ix.users[bit].set(slot)
+	}
+	e.requiredCount = e.required.count()
+	e.weight = float64(e.requiredCount) + OptionalWeight*float64(e.optional.count())
+
+	ix.slots[slot] = e
+	ix.byID[d.ID] = slot
+}
+
+func (ix *Index) remove(id int) {
+	slot, ok := ix.byID[id]
+	if !ok {
+		return
+	}
+	e := ix.slots[slot]
+	e.required.each(func(bit int) { ix.users[bit].clear(slot) })
+	e.optional.each(func(bit int) { ix.users[bit].clear(slot) })
+
+	ix.slots[slot] = nil
+	ix.free = append(ix.free, slot)
+	delete(ix.byID, id)
+}
+
+// Strict returns the drinks whose every required ingredient is in the pantry,
+// given as ingredient IDs, ordered by name.
+func (ix *Index) Strict(pantry []int) []Match {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var matches []Match
+	ix.each(pantry, func(m Match, _ float64) {
+		if m.Missing == 0 {
+			matches = append(matches, m)
+		}
+	})
+
+	sort.Slice(matches, func(i, j int) bool { return byName(&matches[i], &matches[j]) < 0 })
+	return matches
+}
+
+// NonStrict returns the drinks using at least one ingredient in the pantry,
+// narrowed, ranked and paged by opts as postgres does. Only the page is
+// sorted.
+func (ix *Index) NonStrict(pantry []int, opts drinkee.GenerateOptions) ([]Match, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	rank := opts.Rank
+	if rank == "" {
+		rank = drinkee.RankMissing
+	}
+	less, ok := rankings[rank]
+	if !ok {
+		return nil, fmt.Errorf("%w: rank %q isn't supported", drinkee.ErrInvalidGenerateOptions, rank)
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// keep the best skip+limit in a heap with the worst on top
+	top := &ranked{less: less}
+	k := opts.Skip + opts.Limit
+	ix.each(pantry, func(m Match, coverage float64) {
+		if opts.MaxMissing != nil && m.Missing > *opts.MaxMissing {
+			return
+		}
+		if coverage < opts.MinCoverage {
+			return
+		}
+		switch {
+		case opts.Limit == 0 || top.Len() < k:
+			top.push(m)
+		default:
+			top.offer(m)
+		}
+	})
+
+	matches := top.matches
+	sort.Slice(matches, func(i, j int) bool { return less(&matches[i], &matches[j]) })
+	if opts.Skip >= len(matches) {
+		return nil, nil
+	}
+	return matches[opts.Skip:], nil
+}
+
+// each calls f with every drink sharing an ingredient with the pantry and its
+// unrounded coverage. The caller holds the read lock.
+func (ix *Index) each(pantry []int, f func(m Match, coverage float64)) {
+	var have, candidates bitset
+	for _, id := range pantry {
+		if bit, ok := ix.bits[id]; ok && !have.has(bit) {
+			have.set(bit)
+			candidates.or(ix.users[bit])
+		}
+	}
+
+	candidates.each(func(slot int) {
+		e := ix.slots[slot]
+		required := e.required.intersection(have)
+		optional := e.optional.intersection(have)
+		coverage := (float64(required) + OptionalWeight*float64(optional)) / e.weight
+		f(Match{
+			ID:       e.id,
+			Name:     e.name,
+			Rating:   e.rating,
+			Present:  required + optional,
+			Missing:  e.requiredCount - required,
+			Coverage: math.Round(coverage*10000) / 10000,
+		}, coverage)
+	})
+}
+
+// ranked is a heap of matches with the worst by less on top. It doesn't use
+// container/heap, which would allocate for every match pushed.
+type ranked struct {
+	matches []Match
+	next    Match
+	less    func(a, b *Match) bool
+}
+
+func (r *ranked) Len() int { return len(r.matches) }
+
+func (r *ranked) push(m Match) {
+	r.matches = append(r.matches, m)
+	for i := len(r.matches) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !r.less(&r.matches[parent], &r.matches[i]) {
+			break
+		}
+		r.matches[parent], r.matches[i] = r.matches[i], r.matches[parent]
+		i = parent
+	}
+}
+
+// offer swaps the worst match for m if m is better. m is copied to next
+// first, as taking its address for less would move every m to the heap.
+func (r *ranked) offer(m Match) {
+	r.next = m
+	if !r.less(&r.next, &r.matches[0]) {
+		return
+	}
+	r.matches[0] = r.next
+	for i, n := 0, len(r.matches); ; {
+		worst := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < n && r.less(&r.matches[worst], &r.matches[child]) {
+				worst = child
+			}
+		}
+		if worst == i {
+			return
+		}
+		r.matches[i], r.matches[worst] = r.matches[worst], r.matches[i]
+		i = worst
+	}
+}
+
+// rankings orders matches for each of drinkee.Rankings, ties going the same
+// way as in postgres. Add a ranking by adding its comparisons here.
+var rankings = map[string]func(a, b *Match) bool{
+	drinkee.RankMissing:  by(byMissing, byCoverage),
+	drinkee.RankCoverage: by(byCoverage, byMissing),
+	drinkee.RankRating:   by(byRating, byCoverage, byMissing),
+}
+
+// by orders matches by each comparison in turn, then by name.
+func by(comparisons ...func(a, b *Match) int) func(a, b *Match) bool {
+	comparisons = append(comparisons, byName)
+	return func(a, b *Match) bool {
+		for _, compare := range comparisons {
+			if c := compare(a, b); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	}
+}
+
+// The comparisons return a negative number when a goes first.
+
+func byMissing(a, b *Match) int { return a.Missing - b.Missing }
+
+func byCoverage(a, b *Match) int { return compareFloats(b.Coverage, a.Coverage) }
+
+// byRating puts unrated drinks last.
+func byRating(a, b *Match) int {
+	ra, rb := a.Rating, b.Rating
+	if ra == 0 {
+		ra = -1
+	}
+	if rb == 0 {
+		rb = -1
+	}
+	return compareFloats(rb, ra)
+}
+
+func byName(a, b *Match) int {
+	switch {
+	case a.Name < b.Name:
+		return -1
+	case a.Name > b.Name:
+		return 1
+	}
+	return a.ID - b.ID
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
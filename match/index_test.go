@@ -0,0 +1,170 @@
+package match_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/match"
+	"github.com/stretchr/testify/assert"
+)
+
+// ingredient IDs
+const (
+	gin = iota + 1
+	lime
+	syrup
+	soda
+	tonic
+	salt
+	tequila
+	triple
+)
+
+func required(ids ...int) []match.Ingredient {
+	var is []match.Ingredient
+	for _, id := range ids {
+		is = append(is, match.Ingredient{ID: id})
+	}
+	return is
+}
+
+func newIndex() *match.Index {
+	ix := match.NewIndex()
+	ix.Load([]match.Drink{
+		{ID: 1, Name: "gimlet", Rating: 4, Ingredients: required(gin, lime, syrup)},
+		{ID: 2, Name: "gin_rickey", Ingredients: required(gin, lime, soda)},
+		{ID: 3, Name: "gin_tonic", Rating: 3.5, Ingredients: required(gin, tonic)},
+		{ID: 4, Name: "margarita", Rating: 4.5, Ingredients: append(required(tequila, lime, triple), match.Ingredient{ID: salt, Optional: true})},
+	})
+	return ix
+}
+
+func names(ms []match.Match) []string {
+	var ns []string
+	for _, m := range ms {
+		ns = append(ns, m.Name)
+	}
+	return ns
+}
+
+func TestStrict(t *testing.T) {
+	ix := newIndex()
+
+	assert.Equal(t, []string{"gimlet", "gin_tonic"}, names(ix.Strict([]int{gin, lime, syrup, tonic})))
+	// the salt rim is optional
+	assert.Equal(t, []string{"margarita"}, names(ix.Strict([]int{tequila, lime, triple})))
+	assert.Empty(t, ix.Strict([]int{gin}))
+	assert.Empty(t, ix.Strict([]int{99}))
+	assert.Empty(t, ix.Strict(nil))
+}
+
+func TestNonStrict(t *testing.T) {
+	ix := newIndex()
+	pantry := []int{gin, lime, salt}
+
+	matches, err := ix.NonStrict(pantry, drinkee.GenerateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gimlet", "gin_rickey", "gin_tonic", "margarita"}, names(matches))
+	assert.Equal(t, match.Match{ID: 1, Name: "gimlet", Rating: 4, Present: 2, Missing: 1, Coverage: 0.6667}, matches[0])
+	assert.Equal(t, match.Match{ID: 4, Name: "margarita", Rating: 4.5, Present: 2, Missing: 2, Coverage: 0.4286}, matches[3])
+
+	matches, _ = ix.NonStrict(pantry, drinkee.GenerateOptions{Rank: drinkee.RankCoverage})
+	assert.Equal(t, []string{"gimlet", "gin_rickey", "gin_tonic", "margarita"}, names(matches))
+
+	matches, _ = ix.NonStrict(pantry, drinkee.GenerateOptions{Rank: drinkee.RankRating})
+	assert.Equal(t, []string{"margarita", "gimlet", "gin_tonic", "gin_rickey"}, names(matches))
+
+	one := 1
+	matches, _ = ix.NonStrict(pantry, drinkee.GenerateOptions{MaxMissing: &one, MinCoverage: 0.6})
+	assert.Equal(t, []string{"gimlet", "gin_rickey"}, names(matches))
+
+	matches, _ = ix.NonStrict(pantry, drinkee.GenerateOptions{Limit: 2, Skip: 1})
+	assert.Equal(t, []string{"gin_rickey", "gin_tonic"}, names(matches))
+
+	matches, _ = ix.NonStrict(pantry, drinkee.GenerateOptions{Limit: 2, Skip: 4})
+	assert.Empty(t, matches)
+
+	_, err = ix.NonStrict(pantry, drinkee.GenerateOptions{Rank: "popularity"})
+	assert.True(t, errors.Is(err, drinkee.ErrInvalidGenerateOptions))
+}
+
+func TestPutAndDelete(t *testing.T) {
+	ix := newIndex()
+
+	ix.Put(match.Drink{ID: 3, Name: "gin_tonic", Ingredients: required(gin, tonic, lime)})
+	ix.Put(match.Drink{ID: 5, Name: "gin_soda", Ingredients: required(gin, soda)})
+	ix.Delete(2)
+	ix.Delete(99)
+
+	assert.Equal(t, 4, ix.Len())
+	assert.Equal(t, []string{"gin_soda"}, names(ix.Strict([]int{gin, soda})))
+	assert.Equal(t, []string{"gimlet", "gin_tonic"}, names(ix.Strict([]int{gin, lime, syrup, tonic})))
+}
+
+// catalog is a synthetic catalog of n drinks of 3 to 7 ingredients each,
+// drawn from a skewed set of 400 so some are in most drinks.
+func catalog(n int) []match.Drink {
+	r := rand.New(rand.NewSource(1))
+	drinks := make([]match.Drink, n)
+	for i := range drinks {
+		d := match.Drink{ID: i + 1, Name: fmt.Sprintf("drink %d", i+1), Rating: float64(r.Intn(11)) / 2}
+		for j := 3 + r.Intn(5); j > 0; j-- {
+			d.Ingredients = append(d.Ingredients, match.Ingredient{ID: 1 + int(r.ExpFloat64()*60)%400, Optional: j == 1 && r.Intn(3) == 0})
+		}
+		drinks[i] = d
+	}
+	return drinks
+}
+
+// pantry is a home bar of 15 ingredients, most of them common.
+func pantry() []int {
+	var ids []int
+	for id := 1; id <= 30; id += 2 {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func BenchmarkStrict(b *testing.B) {
+	for _, n := range []int{1000, 20000} {
+		ix := match.NewIndex()
+		ix.Load(catalog(n))
+		p := pantry()
+
+		b.Run(fmt.Sprintf("drinks=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ix.Strict(p)
+			}
+		})
+	}
+}
+
+func BenchmarkNonStrict(b *testing.B) {
+	for _, n := range []int{1000, 20000} {
+		ix := match.NewIndex()
+		ix.Load(catalog(n))
+		p := pantry()
+
+		b.Run(fmt.Sprintf("drinks=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ix.NonStrict(p, drinkee.GenerateOptions{Limit: 100}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPut(b *testing.B) {
+	ix := match.NewIndex()
+	drinks := catalog(20000)
+	ix.Load(drinks)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ix.Put(drinks[i%len(drinks)])
+	}
+}
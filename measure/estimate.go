@@ -0,0 +1,109 @@
+package measure
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+const (
+	// ethanolDensity is grams of ethanol per milliliter.
+	ethanolDensity     = 0.789
+	kcalPerAlcoholGram = 7
+	kcalPerSugarGram   = 4
+
+	// DefaultStandardDrink is the US standard drink, in grams of alcohol.
+	DefaultStandardDrink = 14
+)
+
+// DefaultDilution is the fraction of water melting ice adds, by preparation
+// method. Drinks without a method are assumed undiluted.
+var DefaultDilution = map[string]float64{
+	drinkee.MethodShaken:  0.25,
+	drinkee.MethodStirred: 0.20,
+	drinkee.MethodBlended: 0.30,
+	drinkee.MethodThrown:  0.15,
+	drinkee.MethodBuilt:   0.10,
+}
+
+// Portion is one ingredient of a drink and how much of it is used.
+type Portion struct {
+	Ingredient  drinkee.Ingredient
+	Measurement string
+}
+
+// Estimator works out drink volume, strength and calories.
+type Estimator struct {
+	// Dilution is the fraction of water added while mixing, by method.
+	Dilution map[string]float64
+	// StandardDrink is grams of alcohol in one standard drink.
+	StandardDrink float64
+}
+
+func NewEstimator() *Estimator {
+	return &Estimator{Dilution: DefaultDilution, StandardDrink: DefaultStandardDrink}
+}
+
+// Quantity parses the portion's measurement. Counts, such as "2 wedges", get a
+// volume when the ingredient has a UnitML.
+func (p Portion) Quantity() (*drinkee.Quantity, bool) {
+	q, ok := Parse(p.Measurement)
+	if !ok {
+		return nil, false
+	}
+
+	dq := &drinkee.Quantity{Amount: round(q.Amount, 3), Unit: q.Unit}
+	if ml, ok := q.Milliliters(); ok {
+		ml = round(ml, 2)
+		dq.ML = &ml
+	} else if p.Ingredient.UnitML > 0 {
+		ml := round(q.Amount*p.Ingredient.UnitML, 2)
+		dq.ML = &ml
+	}
+	return dq, true
+}
+
+// Estimate totals the portions of a drink made with method. It returns nil
+// when no portion has a known volume. Portions without one are listed in
+// Unmeasured and left out of the figures.
+func (e *Estimator) Estimate(method string, portions []Portion) *drinkee.DrinkStats {
+	var volume, alcohol, calories float64
+	var unmeasured []string
+
+	for _, p := range portions {
+		q, ok := p.Quantity()
+		if !ok || q.ML == nil {
+			unmeasured = append(unmeasured, p.Ingredient.Name)
+			continue
+		}
+		ml := *q.ML
+		i := p.Ingredient
+
+		volume += ml
+		alcohol += ml * i.ABV / 100
+		if i.Calories != nil {
+			calories += ml * *i.Calories / 100
+		} else {
+			calories += ml*i.ABV/100*ethanolDensity*kcalPerAlcoholGram + ml*i.Sugar/100*kcalPerSugarGram
+		}
+	}
+	if volume == 0 {
+		return nil
+	}
+
+	final := volume * (1 + e.Dilution[method])
+	sort.Strings(unmeasured)
+	return &drinkee.DrinkStats{
+		Volume:         round(final, 0),
+		ABV:            round(alcohol/final*100, 1),
+		StandardDrinks: round(alcohol*ethanolDensity/e.StandardDrink, 1),
+		Calories:       round(calories, 0),
+		Unmeasured:     unmeasured,
+	}
+}
+
+func round(f float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(f*p) / p
+}
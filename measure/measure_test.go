@@ -0,0 +1,85 @@
+package measure_test
+
+import (
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want measure.Quantity
+	}{
+		{"2 oz", measure.Quantity{Amount: 2, Unit: "oz"}},
+		{"1 1/2 fl oz", measure.Quantity{Amount: 1.5, Unit: "oz"}},
+		{"1½ oz", measure.Quantity{Amount: 1.5, Unit: "oz"}},
+		{"¾ Ounces", measure.Quantity{Amount: 0.75, Unit: "oz"}},
+		{"1/2 tsp", measure.Quantity{Amount: 0.5, Unit: "tsp"}},
+		{"70ml/2fl oz", measure.Quantity{Amount: 70, Unit: "ml"}},
+		{"4,5 cl", measure.Quantity{Amount: 4.5, Unit: "cl"}},
+		{"2-3 dashes", measure.Quantity{Amount: 2.5, Unit: "dash"}},
+		{"2 parts", measure.Quantity{Amount: 2, Unit: "part"}},
+		{"1 bar spoon", measure.Quantity{Amount: 1, Unit: "barspoon"}},
+		{"3 wedges", measure.Quantity{Amount: 3, Unit: "wedge"}},
+		{"1 egg white", measure.Quantity{Amount: 1, Unit: ""}},
+		{"2", measure.Quantity{Amount: 2, Unit: ""}},
+	}
+	for _, tt := range tests {
+		got, ok := measure.Parse(tt.in)
+		assert.True(t, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "top up", "to taste", "juice of 1 lime", "1/0 oz"} {
+		_, ok := measure.Parse(in)
+		assert.False(t, ok, in)
+	}
+}
+
+func TestMilliliters(t *testing.T) {
+	ml, ok := measure.Quantity{Amount: 2, Unit: "oz"}.Milliliters()
+	assert.True(t, ok)
+	assert.InDelta(t, 59.15, ml, 0.01)
+
+	_, ok = measure.Quantity{Amount: 2, Unit: "wedge"}.Milliliters()
+	assert.False(t, ok)
+}
+
+func TestEstimate(t *testing.T) {
+	gin := drinkee.Ingredient{Name: "gin", ABV: 40}
+	vermouth := drinkee.Ingredient{Name: "dry vermouth", ABV: 18, Sugar: 3}
+	olive := drinkee.Ingredient{Name: "olive"}
+
+	stats := measure.NewEstimator().Estimate(drinkee.MethodStirred, []measure.Portion{
+		{Ingredient: gin, Measurement: "2 oz"},
+		{Ingredient: vermouth, Measurement: "1 oz"},
+		{Ingredient: olive, Measurement: "1"},
+	})
+
+	// 88.7ml of ingredients and 20% water
+	assert.Equal(t, 106.0, stats.Volume)
+	assert.Equal(t, 27.2, stats.ABV)
+	assert.Equal(t, 1.6, stats.StandardDrinks)
+	assert.Equal(t, 164.0, stats.Calories)
+	assert.Equal(t, []string{"olive"}, stats.Unmeasured)
+}
+
+func TestEstimateCountsAndCalories(t *testing.T) {
+	e := &measure.Estimator{Dilution: map[string]float64{}, StandardDrink: 10}
+	kcal := 40.0
+
+	stats := e.Estimate("", []measure.Portion{
+		{Ingredient: drinkee.Ingredient{Name: "egg white", UnitML: 30, Calories: &kcal}, Measurement: "1"},
+		{Ingredient: drinkee.Ingredient{Name: "soda water"}, Measurement: "top up"},
+	})
+
+	assert.Equal(t, 30.0, stats.Volume)
+	assert.Equal(t, 0.0, stats.ABV)
+	assert.Equal(t, 12.0, stats.Calories)
+	assert.Equal(t, []string{"soda water"}, stats.Unmeasured)
+
+	assert.Nil(t, e.Estimate("", []measure.Portion{{Ingredient: drinkee.Ingredient{Name: "mint"}, Measurement: "8 leaves"}}))
+}
@@ -0,0 +1,176 @@
+// Package measure turns free-text recipe measurements such as "1 1/2 oz" into
+// quantities and estimates a drink's volume, strength and calories from them.
+package measure
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Quantity is a parsed measurement. Unit is normalized, e.g. "oz" for "fl
+// oz" or "ounces", and empty for plain counts such as "2".
+type Quantity struct {
+	Amount float64
+	Unit   string
+}
+
+// volumes are milliliters per unit. A part is taken to be an ounce.
+var volumes = map[string]float64{
+	"ml":       1,
+	"cl":       10,
+	"dl":       100,
+	"l":        1000,
+	"oz":       29.5735,
+	"tsp":      4.92892,
+	"tbsp":     14.7868,
+	"cup":      236.588,
+	"pint":     473.176,
+	"shot":     44.3603,
+	"jigger":   44.3603,
+	"pony":     29.5735,
+	"part":     29.5735,
+	"barspoon": 5,
+	"dash":     0.92,
+	"splash":   5.9,
+	"drop":     0.05,
+}
+
+// counts are units of whole things, whose volume depends on the ingredient.
+var counts = map[string]bool{
+	"":       true,
+	"wedge":  true,
+	"slice":  true,
+	"wheel":  true,
+	"twist":  true,
+	"peel":   true,
+	"sprig":  true,
+	"leaf":   true,
+	"piece":  true,
+	"cube":   true,
+	"whole":  true,
+	"pinch":  true,
+	"scoop":  true,
+	"can":    true,
+	"bottle": true,
+}
+
+var aliases = map[string]string{
+	"milliliter": "ml", "millilitre": "ml", "mls": "ml",
+	"centiliter": "cl", "centilitre": "cl", "cls": "cl",
+	"liter": "l", "litre": "l",
+	"fl oz": "oz", "fl. oz": "oz", "fl.oz": "oz", "floz": "oz", "ounce": "oz", "oz.": "oz",
+	"teaspoon": "tsp", "tsp.": "tsp",
+	"tablespoon": "tbsp", "tblsp": "tbsp", "tbs": "tbsp", "tbsp.": "tbsp", "tbl": "tbsp",
+	"bar spoon": "barspoon", "bsp": "barspoon",
+	"dashes": "dash", "splashes": "splash", "leaves": "leaf", "pinches": "pinch",
+}
+
+var fractions = strings.NewReplacer(
+	"½", " 1/2", "⅓", " 1/3", "⅔", " 2/3", "¼", " 1/4", "¾", " 3/4", "⅛", " 1/8",
+)
+
+// amountRE matches a leading amount: "1", "1.5", "1/2", "1 1/2" or a range
+// such as "2-3".
+var amountRE = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)(?:\s+(\d+)/(\d+)|/(\d+))?(?:\s*(?:-|to)\s*(\d+(?:[.,]\d+)?))?\s*`)
+
+// Parse reads the leading quantity of a measurement. Only the first of several
+// alternatives, as in "70ml/2fl oz", is read, and ranges give their midpoint.
+// Measurements without an amount, like "top up", don't parse.
+func Parse(s string) (Quantity, bool) {
+	s = strings.ToLower(strings.TrimSpace(fractions.Replace(s)))
+	m := amountRE.FindStringSubmatchIndex(s)
+	if m == nil {
+		return Quantity{}, false
+	}
+
+	sub := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return s[m[2*i]:m[2*i+1]]
+	}
+	amount := number(sub(1))
+	switch {
+	case sub(2) != "":
+		amount += number(sub(2)) / number(sub(3))
+	case sub(4) != "":
+		amount /= number(sub(4))
+	}
+	if to := sub(5); to != "" {
+		amount = (amount + number(to)) / 2
+	}
+	if amount <= 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return Quantity{}, false
+	}
+
+	return Quantity{Amount: amount, Unit: parseUnit(s[m[1]:])}, true
+}
+
+// parseUnit normalizes the unit at the start of rest. Anything after a unit,
+// such as "/2fl oz" or "of sugar", is ignored.
+func parseUnit(rest string) string {
+	rest = strings.TrimSpace(strings.SplitN(rest, "/", 2)[0])
+	if rest == "" {
+		return ""
+	}
+
+	// try the longest prefix of words first so "fl oz" wins over "fl"
+	words := strings.Fields(rest)
+	for n := min(len(words), 2); n > 0; n-- {
+		if unit, ok := normalizeUnit(strings.Join(words[:n], " ")); ok {
+			return unit
+		}
+	}
+	// anything else, like "1 egg white", counts the ingredient itself
+	return ""
+}
+
+func normalizeUnit(u string) (string, bool) {
+	if a, ok := aliases[u]; ok {
+		u = a
+	}
+	if _, ok := volumes[u]; ok {
+		return u, true
+	}
+	if counts[u] {
+		return u, true
+	}
+	// plurals: "parts", "cups", "wedges", "slices"
+	if s := strings.TrimSuffix(u, "s"); s != u {
+		if a, ok := aliases[s]; ok {
+			s = a
+		}
+		if _, ok := volumes[s]; ok || counts[s] {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Milliliters converts a volumetric quantity, reporting false for counts.
+func (q Quantity) Milliliters() (float64, bool) {
+	ml, ok := volumes[q.Unit]
+	if !ok {
+		return 0, false
+	}
+	return q.Amount * ml, true
+}
+
+// IsCount reports whether q counts whole things, like "2 wedges".
+func (q Quantity) IsCount() bool {
+	return counts[q.Unit]
+}
+
+func number(s string) float64 {
+	n, _ := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return n
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,271 @@
+package measure
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+)
+
+// Unit systems a scaled recipe can be written in.
+const (
+	Metric = "metric"
+	US     = "us"
+)
+
+var metricUnits = map[string]bool{"ml": true, "cl": true, "dl": true, "l": true}
+
+// smallUnits are kept when scaled, up to maxSmall of them, rather than
+// turned into a fraction of a teaspoon.
+var smallUnits = map[string]bool{"dash": true, "drop": true, "splash": true}
+
+const maxSmall = 16
+
+var plurals = map[string]string{
+	"dash": "dashes", "splash": "splashes", "pinch": "pinches", "leaf": "leaves", "whole": "whole",
+}
+
+// Scaling is how ScaleDrink rewrites a recipe.
+type Scaling struct {
+	Servings float64
+	// Units is Metric or US, or empty to keep each ingredient in the system
+	// it was measured in.
+	Units string
+	// Dilute adds the water that mixing with ice would, for batches that are
+	// poured straight from the bottle.
+	Dilute bool
+}
+
+// ParseVolume reads a volume such as "1.5l" or "64 oz". A bare number is in
+// milliliters.
+func ParseVolume(s string) (float64, bool) {
+	q, ok := Parse(s)
+	if !ok {
+		return 0, false
+	}
+	if q.Unit == "" {
+		return q.Amount, true
+	}
+	return q.Milliliters()
+}
+
+// BatchServings returns how many servings of d make a batch of ml
+// milliliters, water included when dilute is set. It returns
+// drinkee.ErrUnknownVolume when d has no stats or no measured volume.
+func BatchServings(d *drinkee.Drink, ml float64, dilute bool) (float64, error) {
+	if d.Stats == nil {
+		return 0, drinkee.ErrUnknownVolume
+	}
+
+	serving := measuredVolume(d)
+	if dilute {
+		serving = d.Stats.Volume
+	}
+	if serving <= 0 {
+		return 0, drinkee.ErrUnknownVolume
+	}
+	return round(ml/serving, 2), nil
+}
+
+// ScaleDrink returns a copy of d with its measurements multiplied by
+// s.Servings and rounded to amounts that can be measured. Counts, like "1
+// wedge", are multiplied as counts, and measurements without an amount, like
+// "top up", are left as they are.
+func ScaleDrink(d *drinkee.Drink, s Scaling) (*drinkee.Drink, error) {
+	if s.Dilute && d.Stats == nil {
+		return nil, drinkee.ErrUnknownVolume
+	}
+
+	scaled := *d
+	scaled.DrinkIngredients = make(drinkee.DrinkIngredientSlice, 0, len(d.DrinkIngredients)+1)
+	for _, di := range d.DrinkIngredients {
+		scaled.DrinkIngredients = append(scaled.DrinkIngredients, scaleIngredient(di, s.Servings, s.Units))
+	}
+
+	scale := &drinkee.Scale{Servings: s.Servings, Units: s.Units}
+	if d.Stats != nil {
+		measured := measuredVolume(d) * s.Servings
+		scale.Volume = round(measured, 0)
+
+		if water := (d.Stats.Volume - measuredVolume(d)) * s.Servings; s.Dilute && water > 0 {
+			scaled.DrinkIngredients = append(scaled.DrinkIngredients, waterIngredient(water, systemOf(d, s.Units)))
+			scale.Water = round(water, 0)
+			scale.Volume = round(measured+water, 0)
+		}
+	}
+	scaled.Scale = scale
+	return &scaled, nil
+}
+
+func scaleIngredient(di drinkee.DrinkIngredient, servings float64, units string) drinkee.DrinkIngredient {
+	q := di.Quantity
+	if q == nil {
+		return di
+	}
+
+	var ml *float64
+	if q.ML != nil {
+		v := round(*q.ML*servings, 2)
+		ml = &v
+	}
+
+	switch amount := q.Amount * servings; {
+	case counts[q.Unit]:
+		amount = math.Max(0.5, roundTo(amount, 0.5))
+		di.Measurement = countText(di.Measurement, q.Unit, amount)
+		di.Quantity = &drinkee.Quantity{Amount: amount, Unit: q.Unit, ML: ml}
+	case smallUnits[q.Unit] && amount <= maxSmall:
+		amount = math.Max(1, math.Round(amount))
+		di.Measurement = text(amount, q.Unit)
+		di.Quantity = &drinkee.Quantity{Amount: amount, Unit: q.Unit, ML: ml}
+	default:
+		if units == "" {
+			units = US
+			if metricUnits[q.Unit] {
+				units = Metric
+			}
+		}
+		amount, unit := convert(*ml, units)
+		di.Measurement = text(amount, unit)
+		di.Quantity = &drinkee.Quantity{Amount: amount, Unit: unit, ML: ml}
+	}
+	return di
+}
+
+func waterIngredient(ml float64, units string) drinkee.DrinkIngredient {
+	ml = round(ml, 2)
+	amount, unit := convert(ml, units)
+	return drinkee.DrinkIngredient{
+		Name:        "water",
+		DisplayName: "Water (dilution)",
+		Measurement: text(amount, unit),
+		Quantity:    &drinkee.Quantity{Amount: amount, Unit: unit, ML: &ml},
+	}
+}
+
+// convert picks the unit ml is most easily measured in and rounds to it.
+func convert(ml float64, units string) (float64, string) {
+	if units == Metric {
+		switch {
+		case ml >= 1000:
+			return roundTo(ml/1000, 0.05), "l"
+		case ml >= 100:
+			return roundTo(ml, 5), "ml"
+		case ml >= 10:
+			return roundTo(ml, 1), "ml"
+		}
+		return math.Max(0.5, roundTo(ml, 0.5)), "ml"
+	}
+
+	oz := ml / volumes["oz"]
+	switch {
+	case oz >= 16:
+		return roundTo(oz/8, 0.25), "cup"
+	case oz >= 0.5:
+		return roundTo(oz, 0.25), "oz"
+	}
+	return math.Max(0.25, roundTo(ml/volumes["tsp"], 0.25)), "tsp"
+}
+
+// systemOf is units, or else the system of the first ingredient measured by
+// volume.
+func systemOf(d *drinkee.Drink, units string) string {
+	if units != "" {
+		return units
+	}
+	for _, di := range d.DrinkIngredients {
+		if q := di.Quantity; q != nil && q.ML != nil && !counts[q.Unit] {
+			if metricUnits[q.Unit] {
+				return Metric
+			}
+			return US
+		}
+	}
+	return Metric
+}
+
+// measuredVolume is the undiluted volume of one serving, which is what
+// DrinkStats are worked out from.
+func measuredVolume(d *drinkee.Drink) float64 {
+	var ml float64
+	for _, di := range d.DrinkIngredients {
+		if di.Quantity != nil && di.Quantity.ML != nil {
+			ml += *di.Quantity.ML
+		}
+	}
+	return ml
+}
+
+// countText writes a scaled count. Counts without a unit, like "1 egg white",
+// keep their text after the amount.
+func countText(measurement, unit string, amount float64) string {
+	if unit != "" {
+		return text(amount, unit)
+	}
+
+	s := strings.TrimSpace(fractions.Replace(measurement))
+	rest := ""
+	if m := amountRE.FindStringIndex(s); m != nil {
+		rest = strings.TrimSpace(s[m[1]:])
+	}
+	return strings.TrimSpace(formatAmount(amount) + " " + rest)
+}
+
+// Describe writes ml in units the way ScaleDrink would, such as "1.25 l" or
+// "2 1/4 cups".
+func Describe(ml float64, units string) string {
+	return text(convert(ml, units))
+}
+
+// DescribeCount writes a count of unit, such as "3 wedges".
+func DescribeCount(amount float64, unit string) string {
+	return text(amount, unit)
+}
+
+// text writes an amount of unit, in decimals for metric units and quarters
+// for the rest.
+func text(amount float64, unit string) string {
+	if metricUnits[unit] {
+		return strconv.FormatFloat(amount, 'f', -1, 64) + " " + unit
+	}
+	return strings.TrimSpace(formatAmount(amount) + " " + plural(unit, amount))
+}
+
+func plural(unit string, amount float64) string {
+	if amount <= 1 || unit == "" || unit == "oz" || unit == "tsp" {
+		return unit
+	}
+	if p, ok := plurals[unit]; ok {
+		return p
+	}
+	return unit + "s"
+}
+
+// formatAmount writes quarters as fractions, like "1 1/2", and anything else
+// as a decimal.
+func formatAmount(f float64) string {
+	whole, frac := math.Modf(f)
+	var quarter string
+	switch frac {
+	case 0:
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	case 0.25:
+		quarter = "1/4"
+	case 0.5:
+		quarter = "1/2"
+	case 0.75:
+		quarter = "3/4"
+	default:
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if whole == 0 {
+		return quarter
+	}
+	return fmt.Sprintf("%d %s", int(whole), quarter)
+}
+
+func roundTo(f, step float64) float64 {
+	return round(math.Round(f/step)*step, 2)
+}
@@ -0,0 +1,115 @@
+package measure_test
+
+import (
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/stretchr/testify/assert"
+)
+
+// daiquiri is measured the way postgres returns it, with quantities parsed
+// and stats worked out.
+func daiquiri() *drinkee.Drink {
+	ingredients := []drinkee.Ingredient{
+		{Name: "light rum", ABV: 40},
+		{Name: "lime juice", Sugar: 2.5},
+		{Name: "simple syrup", Sugar: 63},
+		{Name: "angostura bitters", ABV: 44.7},
+		{Name: "lime"},
+	}
+	measurements := []string{"2 oz", "1 oz", "3/4 oz", "2 dashes", "1 wheel"}
+
+	d := &drinkee.Drink{ID: 3, Name: "daiquiri", DrinkMetadata: drinkee.DrinkMetadata{Method: drinkee.MethodShaken}}
+	var portions []measure.Portion
+	for i, ingredient := range ingredients {
+		p := measure.Portion{Ingredient: ingredient, Measurement: measurements[i]}
+		q, _ := p.Quantity()
+		d.DrinkIngredients = append(d.DrinkIngredients, drinkee.DrinkIngredient{Name: ingredient.Name, Measurement: p.Measurement, Quantity: q})
+		portions = append(portions, p)
+	}
+	d.Stats = measure.NewEstimator().Estimate(d.Method, portions)
+	return d
+}
+
+func measurements(d *drinkee.Drink) []string {
+	var m []string
+	for _, di := range d.DrinkIngredients {
+		m = append(m, di.Measurement)
+	}
+	return m
+}
+
+func TestScaleDrink(t *testing.T) {
+	d := daiquiri()
+
+	scaled, err := measure.ScaleDrink(d, measure.Scaling{Servings: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4 oz", "2 oz", "1 1/2 oz", "4 dashes", "2 wheels"}, measurements(scaled))
+	assert.Equal(t, 2.0, scaled.Scale.Servings)
+	assert.Equal(t, 0.0, scaled.Scale.Water)
+
+	// the original is untouched
+	assert.Equal(t, "2 oz", d.DrinkIngredients[0].Measurement)
+	assert.Nil(t, d.Scale)
+
+	scaled, _ = measure.ScaleDrink(d, measure.Scaling{Servings: 12, Units: measure.Metric})
+	assert.Equal(t, []string{"710 ml", "355 ml", "265 ml", "22 ml", "12 wheels"}, measurements(scaled))
+	assert.Equal(t, "ml", scaled.DrinkIngredients[0].Quantity.Unit)
+	assert.Equal(t, 709.8, *scaled.DrinkIngredients[0].Quantity.ML)
+}
+
+func TestScaleDrinkDilute(t *testing.T) {
+	d := daiquiri()
+
+	scaled, err := measure.ScaleDrink(d, measure.Scaling{Servings: 10, Dilute: true})
+	assert.NoError(t, err)
+	water := scaled.DrinkIngredients[len(scaled.DrinkIngredients)-1]
+	assert.Equal(t, "water", water.Name)
+	assert.Equal(t, "9 1/2 oz", water.Measurement)
+	assert.Equal(t, 283.0, scaled.Scale.Water)
+	assert.Equal(t, 1410.0, scaled.Scale.Volume)
+
+	d.Stats = nil
+	_, err = measure.ScaleDrink(d, measure.Scaling{Servings: 10, Dilute: true})
+	assert.ErrorIs(t, err, drinkee.ErrUnknownVolume)
+}
+
+func TestScaleDrinkCounts(t *testing.T) {
+	d := &drinkee.Drink{DrinkIngredients: drinkee.DrinkIngredientSlice{
+		{Name: "egg white", Measurement: "1 egg white", Quantity: &drinkee.Quantity{Amount: 1}},
+		{Name: "lime", Measurement: "1 wedge", Quantity: &drinkee.Quantity{Amount: 1, Unit: "wedge"}},
+		{Name: "soda water", Measurement: "top up"},
+	}}
+
+	scaled, err := measure.ScaleDrink(d, measure.Scaling{Servings: 2.5})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2 1/2 egg white", "2 1/2 wedges", "top up"}, measurements(scaled))
+	assert.Equal(t, 0.0, scaled.Scale.Volume)
+}
+
+func TestBatchServings(t *testing.T) {
+	ml, ok := measure.ParseVolume("1.5l")
+	assert.True(t, ok)
+
+	servings, err := measure.BatchServings(daiquiri(), ml, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.64, servings)
+
+	ml, ok = measure.ParseVolume("750")
+	assert.True(t, ok)
+	assert.Equal(t, 750.0, ml)
+
+	_, ok = measure.ParseVolume("2 wedges")
+	assert.False(t, ok)
+
+	// counts alone have no volume to batch by
+	d := &drinkee.Drink{
+		DrinkIngredients: drinkee.DrinkIngredientSlice{{Name: "egg white", Measurement: "1", Quantity: &drinkee.Quantity{Amount: 1}}},
+		Stats:            &drinkee.DrinkStats{},
+	}
+	for _, dilute := range []bool{false, true} {
+		_, err = measure.BatchServings(d, 0, dilute)
+		assert.ErrorIs(t, err, drinkee.ErrUnknownVolume)
+	}
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type IngredientAliasService struct {
+	db     *sqlx.DB
+	Logger logger.Logger
+
+	// OnChange is called after an alias is created or deleted, to drop
+	// cached reads that looked ingredients up by name.
+	OnChange func(ctx context.Context)
+}
+
+func NewIngredientAliasService(db *sqlx.DB) *IngredientAliasService {
+	return &IngredientAliasService{db: db, Logger: logger.NewNop()}
+}
+
+func (s *IngredientAliasService) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, s.Logger)
+}
+
+const ingredientAliasColumns = "a.id, a.alias, i.name AS ingredient, a.ingredient_id, a.created_at"
+
+func (s *IngredientAliasService) FindIngredientAliases(ctx context.Context) ([]*drinkee.IngredientAlias, error) {
+	ctx, span := trace.Start(ctx, "IngredientAliasService.FindIngredientAliases")
+	defer span.End()
+
+	var aliases []*drinkee.IngredientAlias
+	ctx, end := startQuery(ctx, "findIngredientAliases")
+	err := s.db.SelectContext(ctx, &aliases, `
+		SELECT `+ingredientAliasColumns+`
+		FROM ingredient_aliases a
+		JOIN ingredients i ON i.id = a.ingredient_id
+		ORDER BY lower(a.alias), i.name
+	`)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+func (s *IngredientAliasService) CreateIngredientAlias(ctx context.Context, ca *drinkee.CreateIngredientAlias) (*drinkee.IngredientAlias, error) {
+	ctx, span := trace.Start(ctx, "IngredientAliasService.CreateIngredientAlias", trace.String("alias", ca.Alias))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	alias, err := createIngredientAlias(ctx, tx, ca)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error creating ingredient alias", logger.F("alias", ca.Alias), logger.F("ingredient", ca.Ingredient), logger.Err(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if s.OnChange != nil {
+		s.OnChange(ctx)
+	}
+
+	s.log(ctx).Info("created ingredient alias", logger.F("id", alias.ID), logger.F("alias", alias.Alias), logger.F("ingredient", alias.Ingredient))
+	return alias, nil
+}
+
+func createIngredientAlias(ctx context.Context, tx *sqlx.Tx, ca *drinkee.CreateIngredientAlias) (*drinkee.IngredientAlias, error) {
+	var shadowed bool
+	queryCtx, end := startQuery(ctx, "ingredientNameExists")
+	err := tx.GetContext(queryCtx, &shadowed, "SELECT EXISTS (SELECT 1 FROM ingredients WHERE lower(name) = lower($1))", ca.Alias)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	if shadowed {
+		return nil, fmt.Errorf("%w: %q is already an ingredient's name", drinkee.ErrInvalidAlias, ca.Alias)
+	}
+
+	var alias drinkee.IngredientAlias
+	queryCtx, end = startQuery(ctx, "createIngredientAlias")
+	err = tx.GetContext(queryCtx, &alias, `
+		WITH a AS (
+			INSERT INTO ingredient_aliases (alias, ingredient_id)
+			SELECT $1, id FROM ingredients WHERE name = $2
+			ON CONFLICT DO NOTHING
+			RETURNING id, alias, ingredient_id, created_at
+		)
+		SELECT `+ingredientAliasColumns+` FROM a JOIN ingredients i ON i.id = a.ingredient_id
+	`, ca.Alias, ca.Ingredient)
+	end(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		// either the ingredient is unknown or it already has the alias
+		var known bool
+		queryCtx, end = startQuery(ctx, "ingredientNameExists")
+		err = tx.GetContext(queryCtx, &known, "SELECT EXISTS (SELECT 1 FROM ingredients WHERE name = $1)", ca.Ingredient)
+		end(err)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown ingredient %q", drinkee.ErrInvalidAlias, ca.Ingredient)
+		}
+		return nil, fmt.Errorf("%w: %q is already an alias of %s", drinkee.ErrInvalidAlias, ca.Alias, ca.Ingredient)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &alias, nil
+}
+
+// DeleteIngredientAlias returns sql.ErrNoRows if there is no such alias.
+func (s *IngredientAliasService) DeleteIngredientAlias(ctx context.Context, id int) error {
+	ctx, span := trace.Start(ctx, "IngredientAliasService.DeleteIngredientAlias", trace.Int("alias.id", id))
+	defer span.End()
+
+	ctx, end := startQuery(ctx, "deleteIngredientAlias")
+	res, err := s.db.ExecContext(ctx, `DELETE FROM ingredient_aliases WHERE id = $1`, id)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	if s.OnChange != nil {
+		s.OnChange(ctx)
+	}
+
+	s.log(ctx).Info("deleted ingredient alias", logger.F("id", id))
+	return nil
+}
+
+// ResolveIngredients returns the ingredients names refer to, keyed by name,
+// the way ingredients given by name are looked up everywhere.
+func (s *IngredientAliasService) ResolveIngredients(ctx context.Context, names []string) (map[string]drinkee.Ingredient, error) {
+	ctx, span := trace.Start(ctx, "IngredientAliasService.ResolveIngredients", trace.Int("names.count", len(names)))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	byName, err := resolveIngredients(ctx, tx, names)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return byName, nil
+}
+
+// resolveIngredients returns the ingredients names refer to, keyed by name.
+// A name matches an ingredient's name ignoring case or, failing that, its
+// aliases. Names matching nothing are left out, and a name matching more than
+// one ingredient is an AmbiguousIngredientError.
+func resolveIngredients(ctx context.Context, tx *sqlx.Tx, names []string) (map[string]drinkee.Ingredient, error) {
+	var rows []struct {
+		Input string `db:"input"`
+		drinkee.Ingredient
+	}
+
+	ctx, end := startQuery(ctx, "resolveIngredients")
+	err := tx.SelectContext(ctx, &rows, `
+		WITH inputs AS (
+			SELECT DISTINCT unnest($1::text[]) AS input
+		),
+		named AS (
+			SELECT n.input, i.id FROM inputs n JOIN ingredients i ON lower(i.name) = lower(n.input)
+		),
+		aliased AS (
+			SELECT DISTINCT n.input, a.ingredient_id AS id
+			FROM inputs n JOIN ingredient_aliases a ON lower(a.alias) = lower(n.input)
+			WHERE n.input NOT IN (SELECT input FROM named)
+		)
+		SELECT r.input, `+ingredientColumns+`
+		FROM (SELECT * FROM named UNION ALL SELECT * FROM aliased) r
+		JOIN ingredients USING (id)
+		ORDER BY r.input, name
+	`, pq.Array(names))
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]drinkee.Ingredient, len(rows))
+	for i, r := range rows {
+		if i > 0 && rows[i-1].Input == r.Input {
+			continue
+		}
+		if i+1 < len(rows) && rows[i+1].Input == r.Input {
+			ambiguous := &drinkee.AmbiguousIngredientError{Name: r.Input}
+			for _, o := range rows[i:] {
+				if o.Input != r.Input {
+					break
+				}
+				ambiguous.Ingredients = append(ambiguous.Ingredients, o.Name)
+			}
+			return nil, ambiguous
+		}
+		byName[r.Input] = r.Ingredient
+	}
+	return byName, nil
+}
+
+// resolveIngredientIDs returns the IDs of the given ingredients, looking up
+// by name those given without one. Names matching nothing are an
+// UnknownIngredientError.
+func resolveIngredientIDs(ctx context.Context, tx *sqlx.Tx, ingredients []drinkee.Ingredient) ([]int, error) {
+	var ids []int
+	var names []string
+	for _, i := range ingredients {
+		if i.ID == 0 && i.Name != "" {
+			names = append(names, i.Name)
+			continue
+		}
+		ids = append(ids, i.ID)
+	}
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	byName, err := resolveIngredients(ctx, tx, names)
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	for _, name := range names {
+		i, ok := byName[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		ids = append(ids, i.ID)
+	}
+	if len(unknown) > 0 {
+		return nil, &drinkee.UnknownIngredientError{Names: unknown}
+	}
+	return ids, nil
+}
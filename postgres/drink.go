@@ -1,121 +1,361 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/dylanconnolly/drinkee/drinkee"
-	"github.com/gin-gonic/gin"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/match"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/dylanconnolly/drinkee/trace"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
 type DrinkService struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	Logger logger.Logger
+	// Estimator works out drink stats when drinks are created or refreshed.
+	Estimator *measure.Estimator
+	// Matcher answers generation from memory when set, leaving postgres to
+	// load only the matched drinks. See LoadMatcher and FollowMatcher.
+	Matcher *match.Index
+
+	// OnChange is called after a drink is reverted, to drop cached reads of
+	// it. Creates go through the cache, which drops them itself.
+	OnChange func(ctx context.Context)
 }
 
 func NewDrinkService(db *sqlx.DB) *DrinkService {
-	return &DrinkService{db: db}
+	return &DrinkService{db: db, Logger: logger.NewNop(), Estimator: measure.NewEstimator()}
 }
 
-func (s *DrinkService) FindDrinkByID(c *gin.Context, id int) (*drinkee.Drink, error) {
-	tx, err := s.db.BeginTxx(c, nil)
+// log prefers the request-scoped logger so entries carry the request ID.
+func (s *DrinkService) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, s.Logger)
+}
+
+func (s *DrinkService) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindDrinkByID", trace.Int("drink.id", id))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drink, err := findDrinkByID(ctx, tx, id)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error finding drink by id", logger.F("id", id), logger.Err(err))
+		return nil, err
+	}
+
+	return drink, nil
+}
+
+func (s *DrinkService) FindDrinkBySlug(ctx context.Context, slug string) (*drinkee.Drink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindDrinkBySlug", trace.String("drink.slug", slug))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	drink, err := findDrinkByID(c, tx, id)
+	drink, err := findDrinkBySlug(ctx, tx, slug)
 	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error finding drink by slug", logger.F("slug", slug), logger.Err(err))
 		return nil, err
 	}
 
 	return drink, nil
 }
 
-func (s *DrinkService) FindDrinks(ctx *gin.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
-	tx, err := s.db.BeginTxx(ctx, nil)
+func (s *DrinkService) FindDrinks(ctx context.Context, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindDrinks")
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 	defer tx.Rollback()
 
 	drinks, err := findDrinks(ctx, tx, f)
 	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error finding drinks", logger.Err(err))
 		return nil, err
 	}
 
 	return drinks, nil
 }
 
-func (s *DrinkService) CreateDrink(c *gin.Context, cd *drinkee.CreateDrink) error {
-	tx, err := s.db.BeginTxx(c, nil)
+func (s *DrinkService) CreateDrink(ctx context.Context, cd *drinkee.CreateDrink) (*drinkee.Drink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.CreateDrink", trace.String("drink.name", cd.Name))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
 	if err != nil {
-		return err
+		span.RecordError(err)
+		return nil, err
 	}
 	defer tx.Rollback()
 
-	if err := createDrink(c, tx, cd); err != nil {
-		return err
+	id, err := createDrink(ctx, tx, s.Estimator, cd)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error creating drink", logger.F("name", cd.Name), logger.Err(err))
+		return nil, err
+	}
+	if _, err := recordDrinkRevision(ctx, tx, id, drinkee.RevisionCreated, 0); err != nil {
+		span.RecordError(err)
+		return nil, err
 	}
 
-	return tx.Commit()
+	drink, err := findDrinkByID(ctx, tx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// the matcher gets the drink once it's committed, before its event
+	// arrives, so it can be generated straight away
+	var created *match.Drink
+	if s.Matcher != nil {
+		if created, err = findMatchDrink(ctx, tx, id); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if created != nil {
+		s.Matcher.Put(*created)
+	}
+
+	span.SetAttributes(trace.Int("drink.id", id))
+	s.log(ctx).Info("created drink", logger.F("id", id), logger.F("name", cd.Name), logger.F("ingredients", len(cd.DrinkIngredients)))
+	return drink, nil
 }
 
-func (s *DrinkService) GenerateDrinks(c *gin.Context, i []drinkee.Ingredient) ([]*drinkee.Drink, error) {
-	var ingredientIDs []int
+func (s *DrinkService) GenerateDrinks(ctx context.Context, i []drinkee.Ingredient) ([]*drinkee.Drink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.GenerateDrinks", trace.Int("ingredients.count", len(i)))
+	defer span.End()
 
-	tx, err := s.db.BeginTxx(c, nil)
+	tx, err := beginTx(ctx, s.db)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	for _, ingredient := range i {
-		ingredientIDs = append(ingredientIDs, ingredient.ID)
+	ingredientIDs, err := resolveIngredientIDs(ctx, tx, i)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
 	}
 
-	drinks, err := generateDrinks(c, tx, ingredientIDs)
+	var drinks []*drinkee.Drink
+	if s.Matcher != nil {
+		drinks, err = findDrinksByID(ctx, tx, matchIDs(s.Matcher.Strict(ingredientIDs)))
+	} else {
+		drinks, err = generateDrinks(ctx, tx, ingredientIDs)
+	}
 	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error generating drinks", logger.F("ingredientIds", ingredientIDs), logger.Err(err))
 		return nil, err
 	}
+	span.SetAttributes(trace.Int("drinks.count", len(drinks)))
+	s.log(ctx).Debug("generated drinks", logger.F("ingredientIds", ingredientIDs), logger.F("matches", len(drinks)))
 
 	return drinks, nil
 }
 
-func (s *DrinkService) GenerateNonStrictDrinks(c *gin.Context, i []drinkee.Ingredient) ([]*drinkee.NonStrictDrink, error) {
-	var ingredientIDs []int
+func (s *DrinkService) GenerateNonStrictDrinks(ctx context.Context, i []drinkee.Ingredient, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.GenerateNonStrictDrinks", trace.Int("ingredients.count", len(i)), trace.String("rank", opts.Rank))
+	defer span.End()
 
-	tx, err := s.db.BeginTxx(c, nil)
+	tx, err := beginTx(ctx, s.db)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	for _, ingredient := range i {
-		ingredientIDs = append(ingredientIDs, ingredient.ID)
+	ingredientIDs, err := resolveIngredientIDs(ctx, tx, i)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
 	}
 
-	drinks, err := generateNonStrictDrinks(c, tx, ingredientIDs)
+	var drinks []*drinkee.NonStrictDrink
+	if s.Matcher != nil {
+		var matches []match.Match
+		matches, err = s.Matcher.NonStrict(ingredientIDs, opts)
+		if err == nil {
+			drinks, err = findNonStrictMatches(ctx, tx, matches, ingredientIDs)
+		}
+	} else {
+		drinks, err = generateNonStrictDrinks(ctx, tx, ingredientIDs, opts)
+	}
 	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error generating non strict drinks", logger.F("ingredientIds", ingredientIDs), logger.Err(err))
 		return nil, err
 	}
+	span.SetAttributes(trace.Int("drinks.count", len(drinks)))
+	s.log(ctx).Debug("generated non strict drinks", logger.F("ingredientIds", ingredientIDs), logger.F("matches", len(drinks)))
 
 	return drinks, nil
 }
 
-func createDrink(c *gin.Context, tx *sqlx.Tx, cd *drinkee.CreateDrink) error {
+// ingredientData mirrors the ingredient_data type createDrink and updateDrink
+// insert from.
+type ingredientData struct {
+	Name        string   `json:"name"`
+	Measurement string   `json:"measurement"`
+	Amount      *float64 `json:"amount"`
+	Unit        *string  `json:"unit"`
+	ML          *float64 `json:"ml"`
+	Optional    bool     `json:"optional"`
+	Role        string   `json:"role"`
+}
+
+// drinkRow is a drink ready to be written, with its ingredients resolved and
+// stats estimated.
+type drinkRow struct {
+	// ingredientNames are the resolved names ingredientJSON refers to.
+	ingredientNames []string
+	ingredientJSON  string
+	statsJSON       string
+	glassID, iceID  *int
+}
+
+// prepareDrink checks cd and works out the row createDrink or updateDrink
+// writes, with the drink's stats estimated by est.
+func prepareDrink(ctx context.Context, tx *sqlx.Tx, est *measure.Estimator, cd *drinkee.CreateDrink) (*drinkRow, error) {
 	var ingredientNames []string
 	for _, di := range cd.DrinkIngredients {
 		ingredientNames = append(ingredientNames, di.Name)
 	}
 
-	diJSON, err := json.Marshal(cd.DrinkIngredients)
+	ingredients, err := resolveIngredients(ctx, tx, ingredientNames)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for _, name := range ingredientNames {
+		if _, ok := ingredients[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", drinkee.ErrInvalidDrink, &drinkee.UnknownIngredientError{Names: unknown})
+	}
+
+	var data []ingredientData
+	var portions []measure.Portion
+	var resolvedNames []string
+	for _, di := range cd.DrinkIngredients {
+		if di.Role != "" && !drinkee.Contains(drinkee.Roles, di.Role) {
+			return nil, fmt.Errorf("%w: unknown role %q for %s", drinkee.ErrInvalidDrink, di.Role, di.Name)
+		}
+		d := ingredientData{
+			Name:        di.Name,
+			Measurement: di.Measurement,
+			Optional:    di.Optional || di.Role == drinkee.RoleGarnish,
+			Role:        di.Role,
+		}
+		// stored under the ingredient's own name, di.Name may be an alias
+		i := ingredients[di.Name]
+		d.Name = i.Name
+		p := measure.Portion{Ingredient: i, Measurement: di.Measurement}
+		if q, ok := p.Quantity(); ok {
+			d.Amount, d.Unit, d.ML = &q.Amount, &q.Unit, q.ML
+		}
+		portions = append(portions, p)
+		data = append(data, d)
+		resolvedNames = append(resolvedNames, d.Name)
+	}
+
+	diJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	statsJSON, err := json.Marshal(est.Estimate(cd.Method, portions))
+	if err != nil {
+		return nil, err
+	}
+
+	if cd.Method != "" && !drinkee.Contains(drinkee.Methods, cd.Method) {
+		return nil, fmt.Errorf("%w: unknown method %q", drinkee.ErrInvalidDrink, cd.Method)
+	}
+	if cd.Year < 0 || cd.Year > 9999 {
+		return nil, fmt.Errorf("%w: year %d out of range", drinkee.ErrInvalidDrink, cd.Year)
+	}
+	if cd.Rating < 0 || cd.Rating > 5 {
+		return nil, fmt.Errorf("%w: rating %g out of range", drinkee.ErrInvalidDrink, cd.Rating)
+	}
+	glassID, err := lookupID(ctx, tx, "glasses", "glass", cd.Glass)
+	if err != nil {
+		return nil, err
+	}
+	iceID, err := lookupID(ctx, tx, "ice_styles", "ice", cd.Ice)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err = tx.Exec(`
+	return &drinkRow{
+		ingredientNames: resolvedNames,
+		ingredientJSON:  string(diJSON),
+		statsJSON:       string(statsJSON),
+		glassID:         glassID,
+		iceID:           iceID,
+	}, nil
+}
+
+// createDrink inserts the drink and its ingredients, with their quantities and
+// the drink's stats worked out by est, and returns the new ID. A drink with
+// the same name or slug as another is a ConflictError.
+func createDrink(ctx context.Context, tx *sqlx.Tx, est *measure.Estimator, cd *drinkee.CreateDrink) (int, error) {
+	slug := drinkee.Slugify(cd.DisplayName)
+	if slug == "" {
+		return 0, fmt.Errorf("%w: display name %q has no letters or digits", drinkee.ErrInvalidDrink, cd.DisplayName)
+	}
+	if err := checkDrinkConflict(ctx, tx, 0, cd.Name, slug); err != nil {
+		return 0, err
+	}
+
+	row, err := prepareDrink(ctx, tx, est, cd)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	ctx, end := startQuery(ctx, "createDrink")
+	err = tx.GetContext(ctx, &id, `
 		WITH drink AS (
-			INSERT INTO drinks (name, display_name, description, instructions)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO drinks (name, display_name, description, instructions, glass_id, method, ice_id, garnish, origin, creator, year, stats, rating, slug)
+			VALUES ($1, $2, $3, $4, $7, NULLIF($8, ''), $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, 0), NULLIF($14, 'null')::jsonb, NULLIF($15, 0), $16)
 			RETURNING id
 		),
 		ingredient_ids AS (
@@ -123,21 +363,167 @@ func createDrink(c *gin.Context, tx *sqlx.Tx, cd *drinkee.CreateDrink) error {
 		),
 		ingredient_data AS (
 			SELECT * FROM json_populate_recordset(null::ingredient_data, $6)
+		),
+		drink_ingredients AS (
+			INSERT INTO drink_ingredients (drink_id, ingredient_id, measurement, amount, unit, ml, optional, role)
+			SELECT drink.id, ingredient_ids.id, ingredient_data.measurement, ingredient_data.amount, ingredient_data.unit, ingredient_data.ml,
+				ingredient_data.optional, NULLIF(ingredient_data.role, '')
+			FROM drink, ingredient_ids, ingredient_data
+			WHERE ingredient_ids.name = ingredient_data.name
 		)
-		INSERT INTO drink_ingredients (drink_id, ingredient_id, measurement)
-		SELECT drink.id, ingredient_ids.id, ingredient_data.measurement
-		FROM drink, ingredient_ids, ingredient_data
-		WHERE ingredient_ids.name = ingredient_data.name
-	`, cd.Name, cd.DisplayName, cd.Description, cd.Instructions, pq.Array(ingredientNames), string(diJSON))
+		SELECT id FROM drink
+	`, cd.Name, cd.DisplayName, cd.Description, cd.Instructions, pq.Array(row.ingredientNames), row.ingredientJSON,
+		row.glassID, cd.Method, row.iceID, cd.Garnish, cd.Origin, cd.Creator, cd.Year, row.statsJSON, cd.Rating, slug)
+	end(err)
+
+	// a drink created concurrently gets past checkDrinkConflict but not the
+	// unique indexes, and the failed insert leaves tx unable to look it up
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("drink %q %w", cd.Name, drinkee.ErrConflict)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// updateDrink replaces everything about the drink but its slug and stats with
+// cd and img, and its ingredients with cd's. It works out new stats with est.
+// A name another drink has is a ConflictError, and sql.ErrNoRows is returned
+// if there is no such drink.
+func updateDrink(ctx context.Context, tx *sqlx.Tx, est *measure.Estimator, id int, cd *drinkee.CreateDrink, img *drinkee.Image) error {
+	// an empty slug matches no drink, the slug isn't changing
+	if err := checkDrinkConflict(ctx, tx, id, cd.Name, ""); err != nil {
+		return err
+	}
 
+	row, err := prepareDrink(ctx, tx, est, cd)
+	if err != nil {
+		return err
+	}
+	imgJSON, err := json.Marshal(img)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// the delete and insert see the ingredients as they were before the
+	// statement, so only the old ones are removed
+	var updated int
+	queryCtx, end := startQuery(ctx, "updateDrink")
+	err = tx.GetContext(queryCtx, &updated, `
+		WITH drink AS (
+			UPDATE drinks SET name = $2, display_name = $3, description = $4, instructions = $5, glass_id = $8,
+				method = NULLIF($9, ''), ice_id = $10, garnish = NULLIF($11, ''), origin = NULLIF($12, ''), creator = NULLIF($13, ''),
+				year = NULLIF($14, 0), stats = NULLIF($15, 'null')::jsonb, rating = NULLIF($16, 0), image = NULLIF($17, 'null')::jsonb
+			WHERE id = $1
+			RETURNING id
+		),
+		removed AS (
+			DELETE FROM drink_ingredients WHERE drink_id IN (SELECT id FROM drink)
+		),
+		ingredient_ids AS (
+			SELECT id, name FROM ingredients WHERE name = ANY($6)
+		),
+		ingredient_data AS (
+			SELECT * FROM json_populate_recordset(null::ingredient_data, $7)
+		),
+		drink_ingredients AS (
+			INSERT INTO drink_ingredients (drink_id, ingredient_id, measurement, amount, unit, ml, optional, role)
+			SELECT drink.id, ingredient_ids.id, ingredient_data.measurement, ingredient_data.amount, ingredient_data.unit, ingredient_data.ml,
+				ingredient_data.optional, NULLIF(ingredient_data.role, '')
+			FROM drink, ingredient_ids, ingredient_data
+			WHERE ingredient_ids.name = ingredient_data.name
+		)
+		SELECT id FROM drink
+	`, id, cd.Name, cd.DisplayName, cd.Description, cd.Instructions, pq.Array(row.ingredientNames), row.ingredientJSON,
+		row.glassID, cd.Method, row.iceID, cd.Garnish, cd.Origin, cd.Creator, cd.Year, row.statsJSON, cd.Rating, string(imgJSON))
+	end(err)
+
+	if isUniqueViolation(err) {
+		return fmt.Errorf("drink %q %w", cd.Name, drinkee.ErrConflict)
+	}
+	return err
+}
+
+// uniqueViolation is the postgres error code for a duplicate key.
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
 }
 
-func findDrinks(ctx *gin.Context, tx *sqlx.Tx, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
+// checkDrinkConflict returns a ConflictError naming a drink other than id with
+// the given name, ignoring case, or slug, if there is one.
+func checkDrinkConflict(ctx context.Context, tx *sqlx.Tx, id int, name, slug string) error {
+	var existing struct {
+		ID   int
+		Name string
+		Slug string
+	}
+
+	ctx, end := startQuery(ctx, "checkDrinkConflict")
+	err := tx.GetContext(ctx, &existing, "SELECT id, name, slug FROM drinks WHERE (lower(name) = lower($1) OR slug = $2) AND id <> $3 LIMIT 1", name, slug, id)
+	end(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &drinkee.ConflictError{Resource: "drink", ID: existing.ID, Name: existing.Name, Slug: existing.Slug}
+}
+
+// lookupID returns the id of the row in a lookup table such as glasses with
+// the given name, or nil for an empty name. field names the value in the
+// error for an unknown name.
+func lookupID(ctx context.Context, tx *sqlx.Tx, table, field, name string) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var id int
+	ctx, end := startQuery(ctx, "lookupID")
+	err := tx.GetContext(ctx, &id, "SELECT id FROM "+table+" WHERE name = $1", name)
+	end(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: unknown %s %q", drinkee.ErrInvalidDrink, field, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// drinkMetadataColumns selects drinkee.DrinkMetadata from the drinks row
+// aliased as t.
+func drinkMetadataColumns(t string) string {
+	return fmt.Sprintf(`
+		COALESCE((SELECT name FROM glasses WHERE id = %[1]s.glass_id), '') AS glass,
+		COALESCE(%[1]s.method, '') AS method,
+		COALESCE((SELECT name FROM ice_styles WHERE id = %[1]s.ice_id), '') AS ice,
+		COALESCE(%[1]s.garnish, '') AS garnish,
+		COALESCE(%[1]s.origin, '') AS origin,
+		COALESCE(%[1]s.creator, '') AS creator,
+		COALESCE(%[1]s.year, 0) AS year,
+		COALESCE(%[1]s.rating, 0) AS rating`, t)
+}
+
+// drinkIngredientJSON builds a drinkee.DrinkIngredient from the
+// drink_ingredients row di and ingredients row i.
+const drinkIngredientJSON = `json_build_object(
+	'name', i.name, 'displayName', i.display_name, 'measurement', di.measurement,
+	'optional', di.optional, 'role', di.role,
+	'quantity', CASE WHEN di.amount IS NOT NULL THEN json_strip_nulls(json_build_object('amount', di.amount, 'unit', NULLIF(di.unit, ''), 'ml', di.ml)) END)`
+
+// containsPattern matches s anywhere with ILIKE, treating wildcards in s literally.
+func containsPattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+func findDrinks(ctx context.Context, tx *sqlx.Tx, f drinkee.DrinkFilter) ([]*drinkee.Drink, error) {
 	var drinks []*drinkee.Drink
 	var filters []interface{}
 	where := []string{"1 = 1"}
@@ -155,14 +541,63 @@ func findDrinks(ctx *gin.Context, tx *sqlx.Tx, f drinkee.DrinkFilter) ([]*drinke
 		}
 	}
 
+	// unlike a drink's ingredients a filter is a search, so a name matching
+	// nothing matches no drinks, as with the name filter
+	if len(f.Ingredients) > 0 {
+		ingredients, err := resolveIngredients(ctx, tx, f.Ingredients)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int, 0, len(ingredients))
+		for _, i := range ingredients {
+			ids = append(ids, i.ID)
+		}
+		where = append(where, "d.id IN (SELECT drink_id FROM drink_ingredients WHERE ingredient_id = ANY(?))")
+		filters = append(filters, pq.Array(ids))
+	}
+
+	if glass := f.Glass; glass != nil {
+		where, filters = append(where, "d.glass_id = (SELECT id FROM glasses WHERE name = ?)"), append(filters, *glass)
+	}
+	if method := f.Method; method != nil {
+		where, filters = append(where, "d.method = ?"), append(filters, *method)
+	}
+	if ice := f.Ice; ice != nil {
+		where, filters = append(where, "d.ice_id = (SELECT id FROM ice_styles WHERE name = ?)"), append(filters, *ice)
+	}
+	if garnish := f.Garnish; garnish != nil {
+		where, filters = append(where, "d.garnish ILIKE ?"), append(filters, containsPattern(*garnish))
+	}
+	if origin := f.Origin; origin != nil {
+		where, filters = append(where, "d.origin ILIKE ?"), append(filters, containsPattern(*origin))
+	}
+	if creator := f.Creator; creator != nil {
+		where, filters = append(where, "d.creator ILIKE ?"), append(filters, containsPattern(*creator))
+	}
+	if year := f.Year; year != nil {
+		where, filters = append(where, "d.year = ?"), append(filters, *year)
+	}
+	if abv := f.MinABV; abv != nil {
+		where, filters = append(where, "(d.stats->>'abv')::numeric >= ?"), append(filters, *abv)
+	}
+	if abv := f.MaxABV; abv != nil {
+		where, filters = append(where, "(d.stats->>'abv')::numeric <= ?"), append(filters, *abv)
+	}
+	if calories := f.MaxCalories; calories != nil {
+		where, filters = append(where, "(d.stats->>'calories')::numeric <= ?"), append(filters, *calories)
+	}
+
 	queryStr := `
 	SELECT 
 		d.id, 
 		d.name,
 		d.display_name,
+		d.slug,
 		d.description,
 		d.instructions,
-		json_agg(json_build_object('name', i.name, 'displayName', i.display_name, 'measurement', di.measurement)) as drink_ingredients 
+		d.image,
+		d.stats,` + drinkMetadataColumns("d") + `,
+		json_agg(` + drinkIngredientJSON + `) as drink_ingredients 
 	FROM drinks d 
 	JOIN drink_ingredients di ON di.drink_id=d.id
 	JOIN ingredients i ON di.ingredient_id=i.id
@@ -172,7 +607,9 @@ func findDrinks(ctx *gin.Context, tx *sqlx.Tx, f drinkee.DrinkFilter) ([]*drinke
 
 	// Rebind query to assign postgres bindvars to generic ? used in filters above
 	q := tx.Rebind(queryStr)
-	err := tx.Select(&drinks, q, filters...)
+	ctx, end := startQuery(ctx, "findDrinks")
+	err := tx.SelectContext(ctx, &drinks, q, filters...)
+	end(err)
 
 	if err != nil {
 		return nil, err
@@ -181,23 +618,26 @@ func findDrinks(ctx *gin.Context, tx *sqlx.Tx, f drinkee.DrinkFilter) ([]*drinke
 	return drinks, nil
 }
 
-func generateDrinks(ctx *gin.Context, tx *sqlx.Tx, ingredientIDs []int) ([]*drinkee.Drink, error) {
+func generateDrinks(ctx context.Context, tx *sqlx.Tx, ingredientIDs []int) ([]*drinkee.Drink, error) {
 	var drinks []*drinkee.Drink
 
-	queryStr := `SELECT md.id,md.name,md.display_name,md.description,md.instructions, ij.drink_ingredients
+	// optional ingredients don't count towards the ones needed
+	queryStr := `SELECT md.id,md.name,md.display_name,md.slug,md.description,md.instructions,md.image,md.stats,` + drinkMetadataColumns("md") + `, ij.drink_ingredients
 		FROM 
-			(SELECT d.*, COUNT(*) AS ingredients_present,
-			(SELECT COUNT(*) FROM drink_ingredients WHERE drink_ingredients.drink_id=d.id) AS total_ingredients 
+			(SELECT d.*, COUNT(*) FILTER (WHERE NOT di.optional) AS required_present,
+			(SELECT COUNT(*) FROM drink_ingredients WHERE drink_ingredients.drink_id=d.id AND NOT drink_ingredients.optional) AS required_total 
 			FROM drinks d JOIN drink_ingredients di ON di.drink_id=d.id WHERE di.ingredient_id = ANY($1) GROUP BY d.id) AS md 
-      JOIN (SELECT d.id, json_agg(json_build_object('name', i.name, 'displayName', i.display_name, 'measurement', di.measurement)) as drink_ingredients 
+      JOIN (SELECT d.id, json_agg(` + drinkIngredientJSON + `) as drink_ingredients 
             FROM drinks d 
             JOIN drink_ingredients di ON di.drink_id=d.id
             JOIN ingredients i ON di.ingredient_id=i.id 
             GROUP BY d.id, d.name ) AS ij ON ij.id=md.id
-		WHERE ingredients_present=total_ingredients
+		WHERE required_present=required_total
 		ORDER BY md.name;`
 
-	err := tx.Select(&drinks, queryStr, pq.Array(ingredientIDs))
+	ctx, end := startQuery(ctx, "generateDrinks")
+	err := tx.SelectContext(ctx, &drinks, queryStr, pq.Array(ingredientIDs))
+	end(err)
 	if err != nil {
 		return nil, err
 	}
@@ -205,23 +645,72 @@ func generateDrinks(ctx *gin.Context, tx *sqlx.Tx, ingredientIDs []int) ([]*drin
 	return drinks, nil
 }
 
-func generateNonStrictDrinks(ctx *gin.Context, tx *sqlx.Tx, ingredientIDs []int) ([]*drinkee.NonStrictDrink, error) {
+// ingredientWeight is what the drink_ingredients row t counts for in
+// non-strict coverage, match.OptionalWeight for optional ingredients.
+func ingredientWeight(t string) string {
+	return "CASE WHEN " + t + ".optional THEN " + strconv.FormatFloat(match.OptionalWeight, 'f', -1, 64) + " ELSE 1 END"
+}
+
+// rankings orders non-strict matches for each of drinkee.Rankings. Add a
+// ranking by adding its ORDER BY here.
+var rankings = map[string]string{
+	drinkee.RankMissing:  "missing_ingredients, coverage DESC",
+	drinkee.RankCoverage: "coverage DESC, missing_ingredients",
+	drinkee.RankRating:   "md.rating DESC NULLS LAST, coverage DESC, missing_ingredients",
+}
+
+func generateNonStrictDrinks(ctx context.Context, tx *sqlx.Tx, ingredientIDs []int, opts drinkee.GenerateOptions) ([]*drinkee.NonStrictDrink, error) {
 	var drinks []*drinkee.NonStrictDrink
 
-	queryStr := `SELECT md.id,md.name,md.display_name,md.description,md.instructions, ij.drink_ingredients, ingredients_present, total_ingredients - ingredients_present AS missing_ingredients
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	rank := opts.Rank
+	if rank == "" {
+		rank = drinkee.RankMissing
+	}
+	orderBy, ok := rankings[rank]
+	if !ok {
+		return nil, fmt.Errorf("%w: rank %q isn't supported", drinkee.ErrInvalidGenerateOptions, rank)
+	}
+
+	// $1 is used several times, so later parameters are numbered by hand
+	// rather than rebound from ?
+	args := []interface{}{pq.Array(ingredientIDs)}
+	where := []string{"ingredients_present >= 1"}
+	if maxMissing := opts.MaxMissing; maxMissing != nil {
+		args = append(args, *maxMissing)
+		where = append(where, fmt.Sprintf("required_total - required_present <= $%d", len(args)))
+	}
+	if opts.MinCoverage > 0 {
+		args = append(args, opts.MinCoverage)
+		where = append(where, fmt.Sprintf("weight_present / weight_total >= $%d", len(args)))
+	}
+
+	queryStr := `SELECT md.id,md.name,md.display_name,md.slug,md.description,md.instructions,md.image,md.stats,` + drinkMetadataColumns("md") + `,
+			ij.drink_ingredients, ij.matched_ingredients, ij.missing_ingredient_list,
+			ingredients_present, required_total - required_present AS missing_ingredients,
+			round(weight_present / weight_total, 4) AS coverage
 		FROM 
 			(SELECT d.*, COUNT(*) AS ingredients_present,
-			(SELECT COUNT(*) FROM drink_ingredients WHERE drink_ingredients.drink_id=d.id) AS total_ingredients 
+			COUNT(*) FILTER (WHERE NOT di.optional) AS required_present,
+			SUM(` + ingredientWeight("di") + `) AS weight_present,
+			(SELECT COUNT(*) FROM drink_ingredients adi WHERE adi.drink_id=d.id AND NOT adi.optional) AS required_total,
+			(SELECT SUM(` + ingredientWeight("adi") + `) FROM drink_ingredients adi WHERE adi.drink_id=d.id) AS weight_total
 			FROM drinks d JOIN drink_ingredients di ON di.drink_id=d.id WHERE di.ingredient_id = ANY($1) GROUP BY d.id) AS md 
-      JOIN (SELECT d.id, json_agg(json_build_object('name', i.name, 'displayName', i.display_name, 'measurement', di.measurement)) as drink_ingredients 
+      JOIN (SELECT d.id, json_agg(` + drinkIngredientJSON + `) as drink_ingredients,
+              COALESCE(json_agg(` + drinkIngredientJSON + `) FILTER (WHERE di.ingredient_id = ANY($1)), '[]') AS matched_ingredients,
+              COALESCE(json_agg(` + drinkIngredientJSON + `) FILTER (WHERE NOT di.ingredient_id = ANY($1)), '[]') AS missing_ingredient_list
             FROM drinks d 
             JOIN drink_ingredients di ON di.drink_id=d.id
             JOIN ingredients i ON di.ingredient_id=i.id 
             GROUP BY d.id, d.name ) AS ij ON ij.id=md.id
-		WHERE ingredients_present>=1
-		ORDER BY missing_ingredients, md.name;`
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY ` + orderBy + `, md.name ` + SetLimitOffset(opts.Limit, opts.Skip)
 
-	err := tx.Select(&drinks, queryStr, pq.Array(ingredientIDs))
+	ctx, end := startQuery(ctx, "generateNonStrictDrinks")
+	err := tx.SelectContext(ctx, &drinks, queryStr, args...)
+	end(err)
 	if err != nil {
 		return nil, err
 	}
@@ -229,17 +718,31 @@ func generateNonStrictDrinks(ctx *gin.Context, tx *sqlx.Tx, ingredientIDs []int)
 	return drinks, nil
 }
 
-func findDrinkByID(c *gin.Context, tx *sqlx.Tx, id int) (*drinkee.Drink, error) {
+func findDrinkByID(ctx context.Context, tx *sqlx.Tx, id int) (*drinkee.Drink, error) {
+	return findDrink(ctx, tx, "findDrinkByID", "d.id = $1", id)
+}
+
+func findDrinkBySlug(ctx context.Context, tx *sqlx.Tx, slug string) (*drinkee.Drink, error) {
+	return findDrink(ctx, tx, "findDrinkBySlug", "d.slug = $1", slug)
+}
+
+// findDrink returns the drink matching where, which takes arg as $1. op
+// names the query in metrics and traces.
+func findDrink(ctx context.Context, tx *sqlx.Tx, op, where string, arg interface{}) (*drinkee.Drink, error) {
 	var drink drinkee.Drink
 
-	err := tx.Get(&drink, `
-	SELECT d.id, d.name, d.display_name, d.description, d.instructions, json_agg(json_build_object('name', i.name, 'displayName', i.display_name, 'measurement', di.measurement)) as drink_ingredients 
+	queryStr := `
+	SELECT d.id, d.name, d.display_name, d.slug, d.description, d.instructions, d.image, d.stats,` + drinkMetadataColumns("d") + `, json_agg(` + drinkIngredientJSON + `) as drink_ingredients 
 	FROM drinks d 
 	JOIN drink_ingredients di ON di.drink_id=d.id
 	JOIN ingredients i ON di.ingredient_id=i.id 
-	WHERE d.id = $1
+	WHERE ` + where + `
 	GROUP BY d.id, d.name ORDER BY d.name
-	`, id)
+	`
+
+	ctx, end := startQuery(ctx, op)
+	err := tx.GetContext(ctx, &drink, queryStr, arg)
+	end(err)
 
 	if err != nil {
 		return nil, err
@@ -248,28 +751,89 @@ func findDrinkByID(c *gin.Context, tx *sqlx.Tx, id int) (*drinkee.Drink, error)
 	return &drink, nil
 }
 
-func (s *DrinkService) FindIngredients(c *gin.Context) ([]*drinkee.Ingredient, error) {
-	tx, err := s.db.BeginTxx(c, nil)
+// SetDrinkImage replaces the drink's image, returning sql.ErrNoRows if there
+// is no such drink.
+func (s *DrinkService) SetDrinkImage(ctx context.Context, id int, img *drinkee.Image) error {
+	ctx, span := trace.Start(ctx, "DrinkService.SetDrinkImage", trace.Int("drink.id", id))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setDrinkImage(ctx, tx, id, img); err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error setting drink image", logger.F("id", id), logger.Err(err))
+		return err
+	}
+	if _, err := recordDrinkRevision(ctx, tx, id, drinkee.RevisionUpdated, 0); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	s.log(ctx).Info("set drink image", logger.F("id", id), logger.F("url", img.URL))
+	return nil
+}
+
+func setDrinkImage(ctx context.Context, tx *sqlx.Tx, id int, img *drinkee.Image) error {
+	imgJSON, err := json.Marshal(img)
 	if err != nil {
+		return err
+	}
+
+	ctx, end := startQuery(ctx, "setDrinkImage")
+	res, err := tx.ExecContext(ctx, "UPDATE drinks SET image = $1 WHERE id = $2", string(imgJSON), id)
+	end(err)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *DrinkService) FindIngredients(ctx context.Context) ([]*drinkee.Ingredient, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindIngredients")
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	ingredients, err := findIngredients(c, tx)
+	ingredients, err := findIngredients(ctx, tx)
 	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error finding ingredients", logger.Err(err))
 		return nil, err
 	}
 
 	return ingredients, nil
 }
 
-func findIngredients(c *gin.Context, tx *sqlx.Tx) ([]*drinkee.Ingredient, error) {
+func findIngredients(ctx context.Context, tx *sqlx.Tx) ([]*drinkee.Ingredient, error) {
 	var ingredients []*drinkee.Ingredient
 
-	err := tx.Select(&ingredients, "SELECT id, name, display_name FROM ingredients ORDER BY name")
+	ctx, end := startQuery(ctx, "findIngredients")
+	err := tx.SelectContext(ctx, &ingredients, "SELECT "+ingredientColumns+" FROM ingredients ORDER BY name")
+	end(err)
 	if err != nil {
 		return nil, err
 	}
 
 	return ingredients, nil
 }
+
+const ingredientColumns = "id, name, display_name, slug, category, abv, sugar_per_100ml, calories_per_100ml, unit_ml"
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// refreshLock is the advisory lock key RefreshEstimates holds, so replicas
+// starting together don't all rewrite the same rows.
+const refreshLock = 0x64726e6b
+
+// RefreshEstimates parses every measurement and works out every drink's stats
+// again, returning how many drinks changed. Only changed rows are written, so
+// catalog events go out for those drinks alone. Run it at startup to pick up
+// drinks inserted outside the app, new ingredient data or a reconfigured
+// Estimator. Only one replica refreshes at a time; the others return 0 without
+// waiting.
+func (s *DrinkService) RefreshEstimates(ctx context.Context) (int, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.RefreshEstimates")
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	locked, err := tryLock(ctx, tx, refreshLock)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	if !locked {
+		s.log(ctx).Info("drink estimates are being refreshed elsewhere")
+		return 0, nil
+	}
+
+	n, err := refreshEstimates(ctx, tx, s.Estimator)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error refreshing drink estimates", logger.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	span.SetAttributes(trace.Int("drinks.count", n))
+	s.log(ctx).Info("refreshed drink estimates", logger.F("changed", n))
+	return n, nil
+}
+
+// tryLock takes a transaction-scoped advisory lock if no one else holds it,
+// reporting whether it did.
+func tryLock(ctx context.Context, tx *sqlx.Tx, key int64) (bool, error) {
+	var locked bool
+
+	ctx, end := startQuery(ctx, "tryLock")
+	err := tx.GetContext(ctx, &locked, `SELECT pg_try_advisory_xact_lock($1)`, key)
+	end(err)
+	return locked, err
+}
+
+type portionRow struct {
+	DrinkIngredientID int    `db:"drink_ingredient_id"`
+	DrinkID           int    `db:"drink_id"`
+	Method            string `db:"method"`
+	Measurement       string `db:"measurement"`
+	drinkee.Ingredient
+}
+
+// quantities holds parsed measurements column by column, to be written in one
+// statement.
+type quantities struct {
+	ids     []int
+	amounts []sql.NullFloat64
+	units   []sql.NullString
+	mls     []sql.NullFloat64
+}
+
+func (q *quantities) add(id int, p measure.Portion) {
+	var amount, ml sql.NullFloat64
+	var unit sql.NullString
+	if qty, ok := p.Quantity(); ok {
+		amount = sql.NullFloat64{Float64: qty.Amount, Valid: true}
+		unit = sql.NullString{String: qty.Unit, Valid: true}
+		if qty.ML != nil {
+			ml = sql.NullFloat64{Float64: *qty.ML, Valid: true}
+		}
+	}
+	q.ids = append(q.ids, id)
+	q.amounts = append(q.amounts, amount)
+	q.units = append(q.units, unit)
+	q.mls = append(q.mls, ml)
+}
+
+func refreshEstimates(ctx context.Context, tx *sqlx.Tx, est *measure.Estimator) (int, error) {
+	var rows []portionRow
+
+	queryCtx, end := startQuery(ctx, "findPortions")
+	err := tx.SelectContext(queryCtx, &rows, `
+		SELECT di.id AS drink_ingredient_id, di.drink_id, COALESCE(d.method, '') AS method, di.measurement,
+			i.id, i.name, i.display_name, i.abv, i.sugar_per_100ml, i.calories_per_100ml, i.unit_ml
+		FROM drink_ingredients di
+		JOIN drinks d ON d.id = di.drink_id
+		JOIN ingredients i ON i.id = di.ingredient_id
+		ORDER BY di.drink_id, di.id`)
+	end(err)
+	if err != nil {
+		return 0, err
+	}
+
+	var qs quantities
+	var drinkIDs []int
+	var stats []string
+	var portions []measure.Portion
+	for i, row := range rows {
+		p := measure.Portion{Ingredient: row.Ingredient, Measurement: row.Measurement}
+		portions = append(portions, p)
+		qs.add(row.DrinkIngredientID, p)
+
+		// rows are ordered by drink, so a drink ends where the next begins
+		if i+1 < len(rows) && rows[i+1].DrinkID == row.DrinkID {
+			continue
+		}
+		statsJSON, err := json.Marshal(est.Estimate(row.Method, portions))
+		if err != nil {
+			return 0, err
+		}
+		drinkIDs = append(drinkIDs, row.DrinkID)
+		stats = append(stats, string(statsJSON))
+		portions = nil
+	}
+
+	changed := map[int]bool{}
+	ids, err := setQuantities(ctx, tx, qs)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		changed[id] = true
+	}
+	ids, err = setStats(ctx, tx, drinkIDs, stats)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		changed[id] = true
+	}
+
+	return len(changed), nil
+}
+
+// setQuantities stores parsed measurements of drink_ingredients rows,
+// returning the IDs of drinks with a changed row.
+func setQuantities(ctx context.Context, tx *sqlx.Tx, qs quantities) ([]int, error) {
+	var ids []int
+	if len(qs.ids) == 0 {
+		return ids, nil
+	}
+
+	ctx, end := startQuery(ctx, "setQuantities")
+	err := tx.SelectContext(ctx, &ids, `
+		UPDATE drink_ingredients di SET amount = q.amount, unit = q.unit, ml = q.ml
+		FROM unnest($1::int[], $2::numeric[], $3::varchar[], $4::numeric[]) AS q(id, amount, unit, ml)
+		WHERE di.id = q.id AND (di.amount, di.unit, di.ml) IS DISTINCT FROM (q.amount, q.unit, q.ml)
+		RETURNING di.drink_id`,
+		pq.Array(qs.ids), pq.Array(qs.amounts), pq.Array(qs.units), pq.Array(qs.mls))
+	end(err)
+	return ids, err
+}
+
+// setStats stores drinks' stats, given as JSON, returning the IDs of the
+// drinks whose stats changed.
+func setStats(ctx context.Context, tx *sqlx.Tx, drinkIDs []int, stats []string) ([]int, error) {
+	var ids []int
+	if len(drinkIDs) == 0 {
+		return ids, nil
+	}
+
+	ctx, end := startQuery(ctx, "setStats")
+	err := tx.SelectContext(ctx, &ids, `
+		UPDATE drinks d SET stats = NULLIF(s.stats, 'null')::jsonb
+		FROM unnest($1::int[], $2::text[]) AS s(id, stats)
+		WHERE d.id = s.id AND d.stats IS DISTINCT FROM NULLIF(s.stats, 'null')::jsonb
+		RETURNING d.id`,
+		pq.Array(drinkIDs), pq.Array(stats))
+	end(err)
+	return ids, err
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type HealthService struct {
+	db *sqlx.DB
+}
+
+func NewHealthService(db *sqlx.DB) *HealthService {
+	return &HealthService{db: db}
+}
+
+func (s *HealthService) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// MigrationVersion reads the version golang-migrate recorded in schema_migrations.
+func (s *HealthService) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	var row struct {
+		Version uint
+		Dirty   bool
+	}
+
+	err := s.db.GetContext(ctx, &row, "SELECT version, dirty FROM schema_migrations LIMIT 1")
+	if err != nil {
+		return 0, false, err
+	}
+
+	return row.Version, row.Dirty, nil
+}
@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// eventChannel is the channel emit_catalog_event notifies.
+const eventChannel = "catalog_events"
+
+const (
+	// catchUpWindow is how long before a lost connection a catalog write may
+	// have started and still be found by the catch-up after reconnecting.
+	// Event IDs are taken when a transaction inserts the event, not when it
+	// commits, so reading on from the last ID seen would skip one committed
+	// late.
+	catchUpWindow = 5 * time.Minute
+
+	// DefaultEventRetention is how long catalog events are kept.
+	DefaultEventRetention = 30 * 24 * time.Hour
+	eventPruneInterval    = time.Hour
+)
+
+// EventPublisher receives events from an EventListener, implemented by
+// events.Broker.
+type EventPublisher interface {
+	Publish(e drinkee.Event)
+	LastEventID() int64
+}
+
+// EventListener LISTENs for catalog events and publishes them. It holds its
+// own connection outside the pool, as LISTEN requires.
+type EventListener struct {
+	db        *sqlx.DB
+	dsn       string
+	publisher EventPublisher
+	listener  *pq.Listener
+	done      chan struct{}
+	Logger    logger.Logger
+
+	// Backfill is how many recent events are loaded on Open, so clients can
+	// resume across a restart.
+	Backfill int
+	// Retention is how old events get before they're deleted, unless a
+	// webhook delivery of them is still pending.
+	Retention time.Duration
+
+	mu sync.Mutex
+	// delivered holds when each event published within the catch-up window
+	// arrived, so catching up doesn't publish it again.
+	delivered map[int64]time.Time
+	// lostAt is when the connection was lost, zero while connected.
+	lostAt time.Time
+}
+
+func NewEventListener(db *sqlx.DB, dsn string, p EventPublisher) *EventListener {
+	return &EventListener{
+		db:        db,
+		dsn:       dsn,
+		publisher: p,
+		Logger:    logger.NewNop(),
+		Backfill:  1000,
+		Retention: DefaultEventRetention,
+		delivered: make(map[int64]time.Time),
+	}
+}
+
+// Open loads recent events and starts listening for new ones.
+func (l *EventListener) Open(ctx context.Context) error {
+	l.listener = pq.NewListener(l.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if ev == pq.ListenerEventDisconnected {
+			l.mu.Lock()
+			l.lostAt = time.Now()
+			l.mu.Unlock()
+		}
+		if err != nil {
+			l.Logger.Warn("event listener connection problem", logger.F("event", int(ev)), logger.Err(err))
+		}
+	})
+	if err := l.listener.Listen(eventChannel); err != nil {
+		l.listener.Close()
+		return err
+	}
+
+	// listen before loading so nothing committed in between is missed, the
+	// publisher drops the overlap
+	recent, err := recentEvents(ctx, l.db, 0, 0, l.Backfill)
+	if err != nil {
+		l.listener.Close()
+		return err
+	}
+	for _, e := range recent {
+		l.publish(e)
+	}
+
+	l.done = make(chan struct{})
+	go l.run()
+	return nil
+}
+
+func (l *EventListener) Close() error {
+	if l.listener == nil {
+		return nil
+	}
+	err := l.listener.Close()
+	<-l.done
+	return err
+}
+
+func (l *EventListener) run() {
+	defer close(l.done)
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+	prune := time.NewTicker(eventPruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case n, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+			if n == nil {
+				// the connection was re-established and notifications sent
+				// while it was down are lost, so read them from the table
+				l.catchUp()
+				continue
+			}
+
+			var e drinkee.Event
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+				l.Logger.Error("error decoding catalog event", logger.F("payload", n.Extra), logger.Err(err))
+				continue
+			}
+			l.publish(e)
+		case <-ping.C:
+			go l.listener.Ping()
+			l.forgetDelivered()
+		case <-prune.C:
+			go l.pruneEvents()
+		}
+	}
+}
+
+// publish sends e on unless it was already delivered, reporting whether it
+// did.
+func (l *EventListener) publish(e drinkee.Event) bool {
+	l.mu.Lock()
+	if _, ok := l.delivered[e.ID]; ok {
+		l.mu.Unlock()
+		return false
+	}
+	l.delivered[e.ID] = time.Now()
+	l.mu.Unlock()
+
+	l.publisher.Publish(e)
+	return true
+}
+
+// catchUp publishes the events committed while the connection was down. It
+// reads every event since catchUpWindow before the connection was lost,
+// skipping those already delivered.
+func (l *EventListener) catchUp() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	l.mu.Lock()
+	lostAt := l.lostAt
+	l.mu.Unlock()
+	if lostAt.IsZero() {
+		lostAt = time.Now()
+	}
+
+	missed, err := recentEvents(ctx, l.db, l.publisher.LastEventID(), time.Since(lostAt)+catchUpWindow, l.Backfill)
+	if err != nil {
+		l.Logger.Error("error catching up on catalog events", logger.Err(err))
+		return
+	}
+
+	l.mu.Lock()
+	l.lostAt = time.Time{}
+	l.mu.Unlock()
+
+	n := 0
+	for _, e := range missed {
+		if l.publish(e) {
+			n++
+		}
+	}
+	l.Logger.Info("event listener reconnected", logger.F("missedEvents", n))
+}
+
+// forgetDelivered drops delivered events too old for a catch-up to read. It
+// keeps them all while disconnected, as the next catch-up reads from before
+// the connection was lost.
+func (l *EventListener) forgetDelivered() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.lostAt.IsZero() {
+		return
+	}
+	// twice the window leaves room for the database clock differing from ours
+	cutoff := time.Now().Add(-2 * catchUpWindow)
+	for id, at := range l.delivered {
+		if at.Before(cutoff) {
+			delete(l.delivered, id)
+		}
+	}
+}
+
+// pruneEvents deletes events older than Retention. Every replica does it,
+// the deletes are the same.
+func (l *EventListener) pruneEvents() {
+	if l.Retention <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	n, err := pruneEvents(ctx, l.db, l.Retention)
+	if err != nil {
+		l.Logger.Error("error pruning catalog events", logger.Err(err))
+		return
+	}
+	if n > 0 {
+		l.Logger.Info("pruned catalog events", logger.F("count", n))
+	}
+}
+
+// pruneEvents deletes events older than retention with no pending webhook
+// delivery, and their finished deliveries with them.
+func pruneEvents(ctx context.Context, db *sqlx.DB, retention time.Duration) (int64, error) {
+	ctx, end := startQuery(ctx, "pruneEvents")
+	res, err := db.ExecContext(ctx, `
+		DELETE FROM catalog_events e
+		WHERE e.created_at < localtimestamp - $1 * interval '1 second'
+		AND NOT EXISTS (
+			SELECT 1 FROM webhook_deliveries d
+			WHERE d.event_id = e.id AND d.status = 'pending'
+		)
+	`, retention.Seconds())
+	end(err)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// recentEvents returns up to limit of the newest events after afterID or
+// created within the last since, oldest first.
+func recentEvents(ctx context.Context, db *sqlx.DB, afterID int64, since time.Duration, limit int) ([]drinkee.Event, error) {
+	var rows []struct {
+		ID        int64     `db:"id"`
+		Type      string    `db:"type"`
+		Data      []byte    `db:"data"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	ctx, end := startQuery(ctx, "recentEvents")
+	err := db.SelectContext(ctx, &rows, `
+		SELECT id, type, data, created_at FROM (
+			SELECT id, type, data, created_at FROM catalog_events
+			WHERE id > $1 OR created_at > localtimestamp - $2 * interval '1 second'
+			ORDER BY id DESC
+			LIMIT $3
+		) recent
+		ORDER BY id
+	`, afterID, since.Seconds(), limit)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]drinkee.Event, 0, len(rows))
+	for _, r := range rows {
+		events = append(events, drinkee.Event{ID: r.ID, Type: r.Type, Data: r.Data, CreatedAt: r.CreatedAt})
+	}
+	return events, nil
+}
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/match"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// matchDrink is a drink as the matcher needs it.
+type matchDrink struct {
+	ID          int
+	Name        string
+	Rating      float64
+	Ingredients matchIngredients
+}
+
+type matchIngredients []match.Ingredient
+
+func (mi *matchIngredients) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, mi)
+}
+
+func (d matchDrink) drink() match.Drink {
+	return match.Drink{ID: d.ID, Name: d.Name, Rating: d.Rating, Ingredients: d.Ingredients}
+}
+
+const matchDrinksQuery = `
+	SELECT d.id, d.name, COALESCE(d.rating, 0) AS rating,
+		json_agg(json_build_object('id', di.ingredient_id, 'optional', di.optional)) AS ingredients
+	FROM drinks d
+	JOIN drink_ingredients di ON di.drink_id=d.id`
+
+// LoadMatcher replaces the drinks in Matcher with every drink in the catalog.
+func (s *DrinkService) LoadMatcher(ctx context.Context) error {
+	ctx, span := trace.Start(ctx, "DrinkService.LoadMatcher")
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var rows []matchDrink
+	queryCtx, end := startQuery(ctx, "loadMatcher")
+	err = tx.SelectContext(queryCtx, &rows, matchDrinksQuery+` GROUP BY d.id`)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error loading matcher", logger.Err(err))
+		return err
+	}
+
+	drinks := make([]match.Drink, 0, len(rows))
+	for _, r := range rows {
+		drinks = append(drinks, r.drink())
+	}
+	s.Matcher.Load(drinks)
+	span.SetAttributes(trace.Int("drinks.count", len(drinks)))
+
+	return nil
+}
+
+// FollowMatcher keeps Matcher in step with the catalog until ctx is done,
+// reloading it whenever the subscription drops and events may have been
+// missed.
+func (s *DrinkService) FollowMatcher(ctx context.Context, es drinkee.EventService) {
+	for {
+		// subscribe before loading so nothing committed in between is
+		// missed, refreshing a drink twice is harmless
+		ch, err := es.SubscribeEvents(ctx, 0)
+		if err == nil {
+			err = s.LoadMatcher(ctx)
+		}
+		if err != nil {
+			s.log(ctx).Error("error following catalog for matcher", logger.Err(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
+		for e := range ch {
+			s.applyMatcherEvent(ctx, e)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		s.log(ctx).Warn("matcher fell behind catalog events, reloading")
+	}
+}
+
+func (s *DrinkService) applyMatcherEvent(ctx context.Context, e drinkee.Event) {
+	var data struct {
+		ID int `json:"id"`
+	}
+	switch e.Type {
+	case drinkee.EventDrinkCreated, drinkee.EventDrinkUpdated, drinkee.EventDrinkDeleted:
+	default:
+		return
+	}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		s.log(ctx).Error("error decoding catalog event", logger.F("eventId", e.ID), logger.Err(err))
+		return
+	}
+	if e.Type == drinkee.EventDrinkDeleted {
+		s.Matcher.Delete(data.ID)
+		return
+	}
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		s.log(ctx).Error("error refreshing matcher", logger.F("id", data.ID), logger.Err(err))
+		return
+	}
+	defer tx.Rollback()
+
+	d, err := findMatchDrink(ctx, tx, data.ID)
+	if err != nil {
+		s.log(ctx).Error("error refreshing matcher", logger.F("id", data.ID), logger.Err(err))
+		return
+	}
+	if d == nil {
+		s.Matcher.Delete(data.ID)
+		return
+	}
+	s.Matcher.Put(*d)
+}
+
+// findMatchDrink returns the drink as tx sees it, nil if it's gone or has no
+// ingredients left.
+func findMatchDrink(ctx context.Context, tx *sqlx.Tx, id int) (*match.Drink, error) {
+	var row matchDrink
+	ctx, end := startQuery(ctx, "findMatchDrink")
+	err := tx.GetContext(ctx, &row, matchDrinksQuery+` WHERE d.id = $1 GROUP BY d.id`, id)
+	end(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d := row.drink()
+	return &d, nil
+}
+
+func matchIDs(matches []match.Match) []int {
+	ids := make([]int, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+// findDrinksByID returns the drinks with the given IDs, ordered by name.
+func findDrinksByID(ctx context.Context, tx *sqlx.Tx, ids []int) ([]*drinkee.Drink, error) {
+	var drinks []*drinkee.Drink
+
+	queryStr := `
+	SELECT d.id, d.name, d.display_name, d.slug, d.description, d.instructions, d.image, d.stats,` + drinkMetadataColumns("d") + `, json_agg(` + drinkIngredientJSON + `) as drink_ingredients
+	FROM drinks d
+	JOIN drink_ingredients di ON di.drink_id=d.id
+	JOIN ingredients i ON di.ingredient_id=i.id
+	WHERE d.id = ANY($1)
+	GROUP BY d.id ORDER BY d.name, d.id
+	`
+
+	ctx, end := startQuery(ctx, "findDrinksByID")
+	err := tx.SelectContext(ctx, &drinks, queryStr, pq.Array(ids))
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return drinks, nil
+}
+
+// findNonStrictMatches loads the matched drinks in the matcher's order,
+// listing which of their ingredients are among ingredientIDs. Drinks deleted
+// since matching are left out.
+func findNonStrictMatches(ctx context.Context, tx *sqlx.Tx, matches []match.Match, ingredientIDs []int) ([]*drinkee.NonStrictDrink, error) {
+	var rows []*drinkee.NonStrictDrink
+
+	queryStr := `
+	SELECT d.id, d.name, d.display_name, d.slug, d.description, d.instructions, d.image, d.stats,` + drinkMetadataColumns("d") + `,
+		json_agg(` + drinkIngredientJSON + `) AS drink_ingredients,
+		COALESCE(json_agg(` + drinkIngredientJSON + `) FILTER (WHERE di.ingredient_id = ANY($2)), '[]') AS matched_ingredients,
+		COALESCE(json_agg(` + drinkIngredientJSON + `) FILTER (WHERE NOT di.ingredient_id = ANY($2)), '[]') AS missing_ingredient_list
+	FROM drinks d
+	JOIN drink_ingredients di ON di.drink_id=d.id
+	JOIN ingredients i ON di.ingredient_id=i.id
+	WHERE d.id = ANY($1)
+	GROUP BY d.id
+	`
+
+	ctx, end := startQuery(ctx, "findNonStrictMatches")
+	err := tx.SelectContext(ctx, &rows, queryStr, pq.Array(matchIDs(matches)), pq.Array(ingredientIDs))
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*drinkee.NonStrictDrink, len(rows))
+	for _, d := range rows {
+		byID[d.ID] = d
+	}
+	drinks := make([]*drinkee.NonStrictDrink, 0, len(matches))
+	for _, m := range matches {
+		d, ok := byID[m.ID]
+		if !ok {
+			continue
+		}
+		d.HaveIngredientCount = m.Present
+		d.MissingIngredientCount = m.Missing
+		d.Coverage = m.Coverage
+		drinks = append(drinks, d)
+	}
+
+	return drinks, nil
+}
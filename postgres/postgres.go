@@ -1,10 +1,13 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"time"
 
+	"github.com/dylanconnolly/drinkee/metrics"
+	"github.com/dylanconnolly/drinkee/trace"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 )
@@ -15,15 +18,26 @@ const (
 	sslmode = "disable" // or verify-full
 )
 
+// ConnectionURL is the DSN built from the POSTGRES_* environment variables.
+func ConnectionURL() string {
+	return fmt.Sprintf("postgres://localhost:5432/%s?sslmode=%s", os.Getenv("POSTGRES_DBNAME"), os.Getenv("POSTGRES_SSLMODE"))
+}
+
 func CreatePostgresConnection() (*sqlx.DB, error) {
-	connUrl := fmt.Sprintf("postgres://localhost:5432/%s?sslmode=%s", os.Getenv("POSTGRES_DBNAME"), os.Getenv("POSTGRES_SSLMODE"))
 	// connStr := fmt.Sprintf("user=%s dbname=%s sslmode=%s", user, dbname, sslmode)
-	db, err := sqlx.Open("postgres", connUrl)
+	db, err := sqlx.Open("postgres", ConnectionURL())
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
+	// sqlx.Open only validates the DSN, so ping to surface an unreachable database at startup
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error connecting to postgres: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -37,3 +51,30 @@ func SetLimitOffset(limit int, offset int) string {
 	}
 	return ""
 }
+
+// beginTx opens a transaction inside its own span so time spent waiting for a
+// pooled connection shows up separately from the queries that follow.
+func beginTx(ctx context.Context, db *sqlx.DB) (*sqlx.Tx, error) {
+	ctx, span := trace.Start(ctx, "postgres.BeginTx")
+	defer span.End()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	span.RecordError(err)
+	return tx, err
+}
+
+// startQuery times and traces one SQL statement. Call the returned func with
+// the statement's error once it completes.
+func startQuery(ctx context.Context, operation string) (context.Context, func(error)) {
+	timer := metrics.QueryTimer(operation)
+	ctx, span := trace.Start(ctx, "sql "+operation,
+		trace.String("db.system", "postgresql"),
+		trace.String("db.operation", operation),
+	)
+
+	return ctx, func(err error) {
+		timer.ObserveDuration()
+		span.RecordError(err)
+		span.End()
+	}
+}
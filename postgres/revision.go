@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"net"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/match"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/jmoiron/sqlx"
+)
+
+const drinkRevisionColumns = `drink_id, number, action, COALESCE(reverted_from, 0) AS reverted_from,
+	COALESCE(actor, '') AS actor, COALESCE(host(client_ip), '') AS client_ip, COALESCE(request_id, '') AS request_id,
+	created_at, snapshot`
+
+func (s *DrinkService) FindDrinkRevisions(ctx context.Context, drinkID int) ([]*drinkee.DrinkRevision, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindDrinkRevisions", trace.Int("drink.id", drinkID))
+	defer span.End()
+
+	var revisions []*drinkee.DrinkRevision
+	ctx, end := startQuery(ctx, "findDrinkRevisions")
+	err := s.db.SelectContext(ctx, &revisions, `
+		SELECT `+drinkRevisionColumns+`
+		FROM drink_revisions
+		WHERE drink_id = $1
+		ORDER BY number DESC
+	`, drinkID)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error finding drink revisions", logger.F("id", drinkID), logger.Err(err))
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return revisions, nil
+}
+
+func (s *DrinkService) FindDrinkRevision(ctx context.Context, drinkID, number int) (*drinkee.DrinkRevision, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindDrinkRevision", trace.Int("drink.id", drinkID), trace.Int("revision", number))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	revision, err := findDrinkRevision(ctx, tx, drinkID, number)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+func findDrinkRevision(ctx context.Context, tx *sqlx.Tx, drinkID, number int) (*drinkee.DrinkRevision, error) {
+	var revision drinkee.DrinkRevision
+
+	ctx, end := startQuery(ctx, "findDrinkRevision")
+	err := tx.GetContext(ctx, &revision, `
+		SELECT `+drinkRevisionColumns+`
+		FROM drink_revisions
+		WHERE drink_id = $1 AND number = $2
+	`, drinkID, number)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+func (s *DrinkService) RevertDrink(ctx context.Context, drinkID, number int) (*drinkee.Drink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.RevertDrink", trace.Int("drink.id", drinkID), trace.Int("revision", number))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	revision, err := revertDrink(ctx, tx, s.Estimator, drinkID, number)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error reverting drink", logger.F("id", drinkID), logger.F("revision", number), logger.Err(err))
+		return nil, err
+	}
+
+	drink, err := findDrinkByID(ctx, tx, drinkID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// the ingredients may have changed, so the matcher gets the drink as it
+	// does a created one
+	var reverted *match.Drink
+	if s.Matcher != nil {
+		if reverted, err = findMatchDrink(ctx, tx, drinkID); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if reverted != nil {
+		s.Matcher.Put(*reverted)
+	}
+	if s.OnChange != nil {
+		s.OnChange(ctx)
+	}
+
+	s.log(ctx).Info("reverted drink", logger.F("id", drinkID), logger.F("revertedFrom", number), logger.F("revision", revision))
+	return drink, nil
+}
+
+// revertDrink restores the drink to the given revision, recorded as a new
+// revision whose number it returns.
+func revertDrink(ctx context.Context, tx *sqlx.Tx, est *measure.Estimator, drinkID, number int) (int, error) {
+	revision, err := findDrinkRevision(ctx, tx, drinkID, number)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshot := revision.Drink
+	cd := &drinkee.CreateDrink{
+		Name:             snapshot.Name,
+		DisplayName:      snapshot.DisplayName,
+		Description:      snapshot.Description,
+		Instructions:     snapshot.Instructions,
+		DrinkIngredients: snapshot.DrinkIngredients,
+		DrinkMetadata:    snapshot.DrinkMetadata,
+	}
+	if err := updateDrink(ctx, tx, est, drinkID, cd, snapshot.Image); err != nil {
+		return 0, err
+	}
+
+	return recordDrinkRevision(ctx, tx, drinkID, drinkee.RevisionReverted, number)
+}
+
+// recordDrinkRevision snapshots the drink, as tx sees it, as its next
+// revision, with the actor, client IP and request ID from ctx. revertedFrom is the
+// revision a revert restored, 0 for other actions. It returns the new
+// revision's number, or sql.ErrNoRows if there is no such drink.
+func recordDrinkRevision(ctx context.Context, tx *sqlx.Tx, drinkID int, action string, revertedFrom int) (int, error) {
+	// numbering from the latest revision is only safe while the drink is
+	// locked, and the insert has to be a later statement to see revisions
+	// committed while waiting for the lock
+	var locked int
+	queryCtx, end := startQuery(ctx, "lockDrink")
+	err := tx.GetContext(queryCtx, &locked, "SELECT id FROM drinks WHERE id = $1 FOR UPDATE", drinkID)
+	end(err)
+	if err != nil {
+		return 0, err
+	}
+
+	var number int
+	queryCtx, end = startQuery(ctx, "recordDrinkRevision")
+	err = tx.GetContext(queryCtx, &number, `
+		INSERT INTO drink_revisions (drink_id, number, action, reverted_from, actor, client_ip, request_id, snapshot)
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2, NULLIF($3, 0), NULLIF($4, ''), NULLIF($5, '')::inet, NULLIF($6, ''), drink_snapshot($1)
+		FROM drink_revisions
+		WHERE drink_id = $1
+		RETURNING number
+	`, drinkID, action, revertedFrom, drinkee.ActorFromContext(ctx), clientIP(ctx), drinkee.RequestIDFromContext(ctx))
+	end(err)
+	if err != nil {
+		return 0, err
+	}
+
+	return number, nil
+}
+
+// clientIP is the client IP from ctx if it parses, so a bad one can't fail
+// the write it is recorded with.
+func clientIP(ctx context.Context) string {
+	ip := net.ParseIP(drinkee.ClientIPFromContext(ctx))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
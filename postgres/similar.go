@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/jmoiron/sqlx"
+)
+
+// FindSimilarDrinks ranks drinks by the weighted Jaccard index of their
+// ingredients and the drink's, weighting each ingredient by its inverse
+// document frequency, ln(1 + drinks / drinks using it).
+func (s *DrinkService) FindSimilarDrinks(ctx context.Context, id int, limit int) ([]*drinkee.SimilarDrink, error) {
+	ctx, span := trace.Start(ctx, "DrinkService.FindSimilarDrinks", trace.Int("drink.id", id))
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drinks, err := findSimilarDrinks(ctx, tx, id, limit)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error finding similar drinks", logger.F("id", id), logger.Err(err))
+		return nil, err
+	}
+	span.SetAttributes(trace.Int("drinks.count", len(drinks)))
+
+	return drinks, nil
+}
+
+func findSimilarDrinks(ctx context.Context, tx *sqlx.Tx, id int, limit int) ([]*drinkee.SimilarDrink, error) {
+	var exists bool
+	queryCtx, end := startQuery(ctx, "drinkExists")
+	err := tx.GetContext(queryCtx, &exists, "SELECT EXISTS (SELECT 1 FROM drinks WHERE id = $1)", id)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	var drinks []*drinkee.SimilarDrink
+
+	// compared has a row for each ingredient of either drink in every pair of
+	// the drink and a drink sharing an ingredient with it, marking which
+	// side of the pair it's on.
+	queryStr := `
+	WITH weights AS (
+		SELECT ingredient_id, ln(1 + (SELECT COUNT(*) FROM drinks)::float8 / COUNT(DISTINCT drink_id)) AS weight
+		FROM drink_ingredients
+		GROUP BY ingredient_id
+	),
+	target AS (
+		SELECT DISTINCT ingredient_id FROM drink_ingredients WHERE drink_id = $1
+	),
+	candidates AS (
+		SELECT DISTINCT drink_id FROM drink_ingredients
+		WHERE ingredient_id IN (SELECT ingredient_id FROM target) AND drink_id <> $1
+	),
+	compared AS (
+		SELECT DISTINCT di.drink_id, di.ingredient_id,
+			CASE WHEN di.ingredient_id IN (SELECT ingredient_id FROM target) THEN 'shared' ELSE 'extra' END AS side
+		FROM drink_ingredients di
+		WHERE di.drink_id IN (SELECT drink_id FROM candidates)
+		UNION ALL
+		SELECT c.drink_id, t.ingredient_id, 'missing'
+		FROM candidates c CROSS JOIN target t
+		WHERE NOT EXISTS (
+			SELECT 1 FROM drink_ingredients di
+			WHERE di.drink_id = c.drink_id AND di.ingredient_id = t.ingredient_id)
+	),
+	scores AS (
+		SELECT cmp.drink_id,
+			COALESCE(SUM(w.weight) FILTER (WHERE cmp.side = 'shared'), 0) / SUM(w.weight) AS score,
+			` + weightedIngredientsJSON("shared") + ` AS shared,
+			` + weightedIngredientsJSON("missing") + ` AS missing,
+			` + weightedIngredientsJSON("extra") + ` AS extra
+		FROM compared cmp
+		JOIN weights w ON w.ingredient_id = cmp.ingredient_id
+		JOIN ingredients i ON i.id = cmp.ingredient_id
+		GROUP BY cmp.drink_id
+	)
+	SELECT d.id, d.name, d.display_name, d.slug, d.description, d.instructions, d.image, d.stats,` + drinkMetadataColumns("d") + `, ij.drink_ingredients,
+		json_build_object('score', round(s.score::numeric, 4), 'shared', s.shared, 'missing', s.missing, 'extra', s.extra) AS similarity
+	FROM scores s
+	JOIN drinks d ON d.id = s.drink_id
+	JOIN (SELECT di.drink_id, json_agg(` + drinkIngredientJSON + `) AS drink_ingredients
+		FROM drink_ingredients di
+		JOIN ingredients i ON di.ingredient_id = i.id
+		GROUP BY di.drink_id) AS ij ON ij.drink_id = d.id
+	ORDER BY s.score DESC, d.name
+	LIMIT $2`
+
+	queryCtx, end = startQuery(ctx, "findSimilarDrinks")
+	err = tx.SelectContext(queryCtx, &drinks, queryStr, id, limit)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return drinks, nil
+}
+
+// weightedIngredientsJSON aggregates the compared ingredients on one side into
+// a JSON array of drinkee.WeightedIngredient, heaviest first.
+func weightedIngredientsJSON(side string) string {
+	return `COALESCE(json_agg(json_build_object('name', i.name, 'displayName', i.display_name, 'weight', round(w.weight::numeric, 3))
+				ORDER BY w.weight DESC, i.name) FILTER (WHERE cmp.side = '` + side + `'), '[]')`
+}
@@ -0,0 +1,372 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/trace"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// WebhookService stores subscriptions and deliveries. Deliveries are queued by
+// the record_catalog_event trigger, so catalog writes need no extra code here.
+type WebhookService struct {
+	db     *sqlx.DB
+	Logger logger.Logger
+}
+
+func NewWebhookService(db *sqlx.DB) *WebhookService {
+	return &WebhookService{db: db, Logger: logger.NewNop()}
+}
+
+func (s *WebhookService) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, s.Logger)
+}
+
+type webhookSubscriptionRow struct {
+	ID        int            `db:"id"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Events    pq.StringArray `db:"events"`
+	Active    bool           `db:"active"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func (r *webhookSubscriptionRow) subscription() *drinkee.WebhookSubscription {
+	events := []string(r.Events)
+	if events == nil {
+		events = []string{}
+	}
+	return &drinkee.WebhookSubscription{
+		ID:        r.ID,
+		URL:       r.URL,
+		Events:    events,
+		Active:    r.Active,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type webhookDeliveryRow struct {
+	ID             int64      `db:"id"`
+	SubscriptionID int        `db:"subscription_id"`
+	URL            string     `db:"url"`
+	Secret         string     `db:"secret"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	EventID        int64      `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	EventData      []byte     `db:"event_data"`
+	EventCreatedAt time.Time  `db:"event_created_at"`
+}
+
+func (r *webhookDeliveryRow) delivery() *drinkee.WebhookDelivery {
+	return &drinkee.WebhookDelivery{
+		ID:             r.ID,
+		SubscriptionID: r.SubscriptionID,
+		URL:            r.URL,
+		Secret:         r.Secret,
+		Status:         r.Status,
+		Attempts:       r.Attempts,
+		NextAttemptAt:  r.NextAttemptAt,
+		CreatedAt:      r.CreatedAt,
+		Event: drinkee.Event{
+			ID:        r.EventID,
+			Type:      r.EventType,
+			Data:      r.EventData,
+			CreatedAt: r.EventCreatedAt,
+		},
+	}
+}
+
+// webhookDeliveryColumns selects a webhookDeliveryRow from webhook_deliveries d
+// joined to webhook_subscriptions s and catalog_events e. The secret is left
+// out, only claimDeliveries needs it.
+const webhookDeliveryColumns = `
+	d.id, d.subscription_id, s.url, '' AS secret, d.status, d.attempts,
+	CASE WHEN d.status = 'pending' THEN d.next_attempt_at END AS next_attempt_at,
+	d.created_at, e.id AS event_id, e.type AS event_type, e.data AS event_data, e.created_at AS event_created_at`
+
+func (s *WebhookService) CreateWebhookSubscription(ctx context.Context, cs *drinkee.CreateWebhookSubscription) (*drinkee.WebhookSubscription, error) {
+	ctx, span := trace.Start(ctx, "WebhookService.CreateWebhookSubscription")
+	defer span.End()
+
+	var row webhookSubscriptionRow
+	ctx, end := startQuery(ctx, "createWebhookSubscription")
+	err := s.db.GetContext(ctx, &row, `
+		INSERT INTO webhook_subscriptions (url, secret, events)
+		VALUES ($1, $2, $3)
+		RETURNING id, url, secret, events, active, created_at
+	`, cs.URL, cs.Secret, pq.StringArray(cs.Events))
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		s.log(ctx).Error("error creating webhook subscription", logger.F("url", cs.URL), logger.Err(err))
+		return nil, err
+	}
+
+	sub := row.subscription()
+	sub.Secret = row.Secret
+
+	s.log(ctx).Info("created webhook subscription", logger.F("id", sub.ID), logger.F("url", sub.URL), logger.F("events", sub.Events))
+	return sub, nil
+}
+
+func (s *WebhookService) FindWebhookSubscriptions(ctx context.Context) ([]*drinkee.WebhookSubscription, error) {
+	ctx, span := trace.Start(ctx, "WebhookService.FindWebhookSubscriptions")
+	defer span.End()
+
+	var rows []webhookSubscriptionRow
+	ctx, end := startQuery(ctx, "findWebhookSubscriptions")
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT id, url, secret, events, active, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	subs := make([]*drinkee.WebhookSubscription, 0, len(rows))
+	for i := range rows {
+		subs = append(subs, rows[i].subscription())
+	}
+	return subs, nil
+}
+
+// DeleteWebhookSubscription removes the subscription along with its deliveries.
+func (s *WebhookService) DeleteWebhookSubscription(ctx context.Context, id int) error {
+	ctx, span := trace.Start(ctx, "WebhookService.DeleteWebhookSubscription", trace.Int("subscription.id", id))
+	defer span.End()
+
+	ctx, end := startQuery(ctx, "deleteWebhookSubscription")
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.log(ctx).Info("deleted webhook subscription", logger.F("id", id))
+	return nil
+}
+
+func (s *WebhookService) FindWebhookDeliveries(ctx context.Context, f drinkee.WebhookDeliveryFilter) ([]*drinkee.WebhookDelivery, error) {
+	ctx, span := trace.Start(ctx, "WebhookService.FindWebhookDeliveries")
+	defer span.End()
+
+	var filters []interface{}
+	where := []string{"1 = 1"}
+
+	if id := f.SubscriptionID; id != nil {
+		where, filters = append(where, "d.subscription_id = ?"), append(filters, *id)
+	}
+	if status := f.Status; status != nil {
+		where, filters = append(where, "d.status = ?"), append(filters, *status)
+	}
+
+	q := s.db.Rebind(`
+	SELECT ` + webhookDeliveryColumns + `
+	FROM webhook_deliveries d
+	JOIN webhook_subscriptions s ON s.id = d.subscription_id
+	JOIN catalog_events e ON e.id = d.event_id
+	WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY d.id DESC ` + SetLimitOffset(f.Limit, f.Skip))
+
+	var rows []webhookDeliveryRow
+	ctx, end := startQuery(ctx, "findWebhookDeliveries")
+	err := s.db.SelectContext(ctx, &rows, q, filters...)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	deliveries := make([]*drinkee.WebhookDelivery, 0, len(rows))
+	for i := range rows {
+		deliveries = append(deliveries, rows[i].delivery())
+	}
+	return deliveries, nil
+}
+
+// FindWebhookDeliveryByID returns the delivery with every attempt made so far.
+func (s *WebhookService) FindWebhookDeliveryByID(ctx context.Context, id int64) (*drinkee.WebhookDelivery, error) {
+	ctx, span := trace.Start(ctx, "WebhookService.FindWebhookDeliveryByID")
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	d, err := findWebhookDeliveryByID(ctx, tx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (s *WebhookService) RedeliverWebhook(ctx context.Context, id int64) (*drinkee.WebhookDelivery, error) {
+	ctx, span := trace.Start(ctx, "WebhookService.RedeliverWebhook")
+	defer span.End()
+
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var newID int64
+	ctx, end := startQuery(ctx, "redeliverWebhook")
+	err = tx.GetContext(ctx, &newID, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id)
+		SELECT subscription_id, event_id FROM webhook_deliveries WHERE id = $1
+		RETURNING id
+	`, id)
+	end(err)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	d, err := findWebhookDeliveryByID(ctx, tx, newID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	s.log(ctx).Info("queued webhook redelivery", logger.F("deliveryId", id), logger.F("newDeliveryId", newID))
+	return d, nil
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries that are due,
+// with their subscription's secret. Claimed deliveries are pushed back by
+// lease so other workers skip them while they're being sent; a worker that
+// dies mid-send leaves them to be retried once the lease runs out.
+func (s *WebhookService) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*drinkee.WebhookDelivery, error) {
+	var rows []webhookDeliveryRow
+	ctx, end := startQuery(ctx, "claimWebhookDeliveries")
+	err := s.db.SelectContext(ctx, &rows, `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = current_timestamp + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= current_timestamp
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT d.id, d.subscription_id, s.url, s.secret, d.status, d.attempts, d.next_attempt_at, d.created_at,
+			e.id AS event_id, e.type AS event_type, e.data AS event_data, e.created_at AS event_created_at
+		FROM claimed d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		JOIN catalog_events e ON e.id = d.event_id
+		ORDER BY d.next_attempt_at, d.id
+	`, limit, lease.Seconds())
+	end(err)
+	if err != nil {
+		s.log(ctx).Error("error claiming webhook deliveries", logger.Err(err))
+		return nil, err
+	}
+
+	deliveries := make([]*drinkee.WebhookDelivery, 0, len(rows))
+	for i := range rows {
+		deliveries = append(deliveries, rows[i].delivery())
+	}
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt stores an attempt and moves its delivery to status.
+// Pending deliveries are retried after retryIn.
+func (s *WebhookService) RecordWebhookAttempt(ctx context.Context, a *drinkee.WebhookAttempt, status string, retryIn time.Duration) error {
+	tx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var statusCode *int
+	if a.StatusCode != 0 {
+		statusCode = &a.StatusCode
+	}
+
+	ctx, end := startQuery(ctx, "recordWebhookAttempt")
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+	`, a.DeliveryID, statusCode, a.Error, a.DurationMs)
+	end(err)
+	if err != nil {
+		return err
+	}
+
+	ctx, end = startQuery(ctx, "updateWebhookDelivery")
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, next_attempt_at = current_timestamp + make_interval(secs => $3)
+		WHERE id = $1
+	`, a.DeliveryID, status, retryIn.Seconds())
+	end(err)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func findWebhookDeliveryByID(ctx context.Context, tx *sqlx.Tx, id int64) (*drinkee.WebhookDelivery, error) {
+	var row webhookDeliveryRow
+	ctx, end := startQuery(ctx, "findWebhookDeliveryByID")
+	err := tx.GetContext(ctx, &row, `
+	SELECT `+webhookDeliveryColumns+`
+	FROM webhook_deliveries d
+	JOIN webhook_subscriptions s ON s.id = d.subscription_id
+	JOIN catalog_events e ON e.id = d.event_id
+	WHERE d.id = $1
+	`, id)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	d := row.delivery()
+
+	ctx, end = startQuery(ctx, "findWebhookAttempts")
+	err = tx.SelectContext(ctx, &d.AttemptLog, `
+	SELECT id, delivery_id, COALESCE(status_code, 0) AS status_code, COALESCE(error, '') AS error, duration_ms, created_at
+	FROM webhook_delivery_attempts
+	WHERE delivery_id = $1
+	ORDER BY id
+	`, id)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,389 @@
+// Package shopping totals what a set of drinks needs into a shopping list,
+// less what's in the pantry, rounded up to bottles where they come in them.
+package shopping
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/measure"
+	"github.com/dylanconnolly/drinkee/trace"
+)
+
+// DefaultBottleSizes are in milliliters.
+var DefaultBottleSizes = []float64{700, 750, 1000}
+
+// bottled categories are bought by the bottle.
+var bottled = map[string]bool{
+	drinkee.CategorySpirit:  true,
+	drinkee.CategoryLiqueur: true,
+	drinkee.CategoryWine:    true,
+}
+
+var _ drinkee.ShoppingListService = (*Service)(nil)
+
+// IngredientResolver looks ingredients up by name or alias, implemented by
+// postgres.IngredientAliasService. Names matching nothing are left out.
+type IngredientResolver interface {
+	ResolveIngredients(ctx context.Context, names []string) (map[string]drinkee.Ingredient, error)
+}
+
+type Service struct {
+	drinks drinkee.DrinkService
+	// BottleSizes are the bottles spirits, liqueurs and wines are bought in,
+	// in milliliters.
+	BottleSizes []float64
+	// Resolver matches pantry items to ingredients by name or alias. Without
+	// one they only match ingredients of the same name.
+	Resolver IngredientResolver
+}
+
+func NewService(drinks drinkee.DrinkService) *Service {
+	return &Service{drinks: drinks, BottleSizes: DefaultBottleSizes}
+}
+
+// need totals one ingredient across drinks.
+type need struct {
+	ingredient drinkee.Ingredient
+	ml         float64
+	// counts are amounts of whole things by unit, like "wedge".
+	counts   map[string]float64
+	asNeeded bool
+	drinks   map[string]bool
+}
+
+func (s *Service) BuildShoppingList(ctx context.Context, r *drinkee.ShoppingListRequest) (*drinkee.ShoppingList, error) {
+	ctx, span := trace.Start(ctx, "ShoppingListService.BuildShoppingList", trace.Int("drinks.count", len(r.Drinks)))
+	defer span.End()
+
+	ingredients, err := s.drinks.FindIngredients(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	byName := make(map[string]*drinkee.Ingredient, len(ingredients))
+	for _, i := range ingredients {
+		byName[i.Name] = i
+	}
+
+	needs := map[string]*need{}
+	for _, rd := range r.Drinks {
+		drink, err := s.drinks.FindDrinkByID(ctx, rd.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no drink with id %d", drinkee.ErrInvalidShoppingList, rd.ID)
+		}
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		for _, di := range drink.DrinkIngredients {
+			n, ok := needs[di.Name]
+			if !ok {
+				n = &need{counts: map[string]float64{}, drinks: map[string]bool{}}
+				if i, ok := byName[di.Name]; ok {
+					n.ingredient = *i
+				} else {
+					n.ingredient = drinkee.Ingredient{Name: di.Name, DisplayName: di.DisplayName}
+				}
+				needs[di.Name] = n
+			}
+			n.add(di.Quantity, rd.Servings)
+			n.drinks[drink.DisplayName] = true
+		}
+	}
+
+	units := r.Units
+	if units == "" {
+		units = measure.Metric
+	}
+	list := &drinkee.ShoppingList{}
+	items := map[string][]drinkee.ShoppingItem{}
+	pantry, err := readPantry(r.Pantry)
+	if err != nil {
+		return nil, err
+	}
+	if pantry, err = s.resolvePantry(ctx, pantry); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	for name, n := range needs {
+		item, covered := s.item(n, pantry[name], units)
+		if covered {
+			list.Covered = append(list.Covered, name)
+			continue
+		}
+		category := n.ingredient.Category
+		if category == "" {
+			category = drinkee.CategoryOther
+		}
+		items[category] = append(items[category], item)
+	}
+
+	sort.Strings(list.Covered)
+	for _, category := range drinkee.IngredientCategories {
+		if len(items[category]) == 0 {
+			continue
+		}
+		sort.Slice(items[category], func(i, j int) bool {
+			return items[category][i].DisplayName < items[category][j].DisplayName
+		})
+		list.Categories = append(list.Categories, drinkee.ShoppingCategory{Category: category, Items: items[category]})
+	}
+	return list, nil
+}
+
+// add counts a measurement for servings. Counts stay counts even when they
+// have a volume, eggs are bought by the egg.
+func (n *need) add(q *drinkee.Quantity, servings float64) {
+	switch {
+	case q == nil:
+		n.asNeeded = true
+	case measure.Quantity{Unit: q.Unit}.IsCount():
+		n.counts[q.Unit] += q.Amount * servings
+	case q.ML != nil:
+		n.ml += *q.ML * servings
+	default:
+		n.asNeeded = true
+	}
+}
+
+// stock is what the pantry has of one ingredient. all is set when it has
+// enough, whatever the amount.
+type stock struct {
+	all    bool
+	ml     float64
+	counts map[string]float64
+}
+
+func readPantry(items []drinkee.PantryItem) (map[string]*stock, error) {
+	pantry := map[string]*stock{}
+	for _, p := range items {
+		name := strings.ToLower(strings.TrimSpace(p.Name))
+		st, ok := pantry[name]
+		if !ok {
+			st = &stock{counts: map[string]float64{}}
+			pantry[name] = st
+		}
+		if p.Amount == "" {
+			st.all = true
+			continue
+		}
+
+		q, ok := measure.Parse(p.Amount)
+		if !ok {
+			return nil, fmt.Errorf("%w: can't read pantry amount %q for %s", drinkee.ErrInvalidShoppingList, p.Amount, p.Name)
+		}
+		if ml, ok := q.Milliliters(); ok {
+			st.ml += ml
+		} else {
+			st.counts[q.Unit] += q.Amount
+		}
+	}
+	return pantry, nil
+}
+
+// resolvePantry rekeys the pantry by the name of the ingredient each item is,
+// adding up items that turn out to be the same ingredient. Items matching no
+// ingredient keep their own name.
+func (s *Service) resolvePantry(ctx context.Context, pantry map[string]*stock) (map[string]*stock, error) {
+	if s.Resolver == nil || len(pantry) == 0 {
+		return pantry, nil
+	}
+
+	names := make([]string, 0, len(pantry))
+	for name := range pantry {
+		names = append(names, name)
+	}
+	byName, err := s.Resolver.ResolveIngredients(ctx, names)
+	if errors.Is(err, drinkee.ErrAmbiguousIngredient) {
+		return nil, fmt.Errorf("%w: %s", drinkee.ErrInvalidShoppingList, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]*stock, len(pantry))
+	for name, st := range pantry {
+		if i, ok := byName[name]; ok {
+			name = i.Name
+		}
+		if have, ok := resolved[name]; ok {
+			have.merge(st)
+			continue
+		}
+		resolved[name] = st
+	}
+	return resolved, nil
+}
+
+func (st *stock) merge(o *stock) {
+	st.all = st.all || o.all
+	st.ml += o.ml
+	for unit, n := range o.counts {
+		st.counts[unit] += n
+	}
+}
+
+// item writes what to buy of n after the pantry, reporting whether the pantry
+// already covers it.
+func (s *Service) item(n *need, st *stock, units string) (drinkee.ShoppingItem, bool) {
+	item := drinkee.ShoppingItem{
+		Name:        n.ingredient.Name,
+		DisplayName: n.ingredient.DisplayName,
+		Needed:      describe(n.ml, n.counts, n.asNeeded, units),
+	}
+	for d := range n.drinks {
+		item.Drinks = append(item.Drinks, d)
+	}
+	sort.Strings(item.Drinks)
+
+	ml, counts := n.ml, map[string]float64{}
+	for unit, amount := range n.counts {
+		counts[unit] = amount
+	}
+	asNeeded := n.asNeeded
+	if st != nil {
+		if st.all {
+			return item, true
+		}
+		ml = math.Max(0, ml-st.ml)
+		for unit, amount := range st.counts {
+			if _, ok := counts[unit]; ok {
+				counts[unit] = math.Max(0, counts[unit]-amount)
+			}
+		}
+		// having some of an ingredient covers "top up" and "to taste"
+		asNeeded = false
+	}
+
+	var left float64
+	for _, amount := range counts {
+		left += amount
+	}
+	if ml < 0.5 && left == 0 && !asNeeded {
+		return item, true
+	}
+
+	item.Buy = describe(ml, counts, asNeeded, units)
+	if ml >= 0.5 {
+		buy := math.Round(ml)
+		item.BuyML = &buy
+		if bottled[n.ingredient.Category] {
+			item.Bottles = Bottles(ml, s.BottleSizes)
+			item.Buy = describeBottles(item.Bottles)
+		}
+	}
+	return item, false
+}
+
+// maxSmallerBottles caps how many of each size but the largest Bottles tries,
+// for sizes with no useful common divisor with the largest.
+const maxSmallerBottles = 64
+
+// Bottles picks the bottles holding at least ml with the least left over,
+// then the fewest bottles.
+func Bottles(ml float64, sizes []float64) []drinkee.Bottle {
+	if len(sizes) == 0 || ml <= 0 {
+		return nil
+	}
+	sizes = append([]float64(nil), sizes...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sizes)))
+
+	// largest/g bottles of a size s hold as much as s/g of the largest, where
+	// g is their greatest common divisor, in more bottles. So the best mix has
+	// fewer than largest/g of each smaller size, whatever ml is, and the
+	// largest bottles make up the rest.
+	largest := sizes[0]
+	limits := make([]int, len(sizes))
+	for i := 1; i < len(sizes); i++ {
+		g := gcd(math.Round(largest), math.Round(sizes[i]))
+		limits[i] = maxSmallerBottles
+		if g > 0 && largest/g-1 < float64(limits[i]) {
+			limits[i] = int(largest/g) - 1
+		}
+		if n := int(math.Ceil(ml / sizes[i])); n < limits[i] {
+			limits[i] = n
+		}
+	}
+
+	var best []int
+	var bestTotal float64
+	bestCount := 0
+	counts := make([]int, len(sizes))
+
+	var try func(i int, left float64)
+	try = func(i int, left float64) {
+		if i == len(sizes) {
+			counts[0] = int(math.Ceil(math.Max(0, left) / largest))
+			total, count := 0.0, 0
+			for j, c := range counts {
+				total += float64(c) * sizes[j]
+				count += c
+			}
+			if best == nil || total < bestTotal || (total == bestTotal && count < bestCount) {
+				best = append(best[:0], counts...)
+				bestTotal, bestCount = total, count
+			}
+			return
+		}
+		for c := 0; c <= limits[i]; c++ {
+			counts[i] = c
+			try(i+1, left-float64(c)*sizes[i])
+		}
+	}
+	try(1, ml)
+
+	var bottles []drinkee.Bottle
+	for i, c := range best {
+		if c > 0 {
+			bottles = append(bottles, drinkee.Bottle{Size: sizes[i], Count: c})
+		}
+	}
+	return bottles
+}
+
+func gcd(a, b float64) float64 {
+	for b > 0 {
+		a, b = b, math.Mod(a, b)
+	}
+	return a
+}
+
+func describe(ml float64, counts map[string]float64, asNeeded bool, units string) string {
+	var parts []string
+	if ml >= 0.5 {
+		parts = append(parts, measure.Describe(ml, units))
+	}
+
+	var countUnits []string
+	for unit, amount := range counts {
+		if amount > 0 {
+			countUnits = append(countUnits, unit)
+		}
+	}
+	sort.Strings(countUnits)
+	for _, unit := range countUnits {
+		// a part of a lime wheel is still a lime
+		parts = append(parts, measure.DescribeCount(math.Ceil(counts[unit]), unit))
+	}
+
+	if asNeeded {
+		parts = append(parts, "as needed")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeBottles(bottles []drinkee.Bottle) string {
+	var parts []string
+	for _, b := range bottles {
+		parts = append(parts, fmt.Sprintf("%d × %g ml", b.Count, b.Size))
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,144 @@
+package shopping_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/shopping"
+	"github.com/stretchr/testify/assert"
+)
+
+type bar struct {
+	drinkee.DrinkService
+}
+
+func ml(v float64) *float64 { return &v }
+
+func (bar) FindIngredients(ctx context.Context) ([]*drinkee.Ingredient, error) {
+	return []*drinkee.Ingredient{
+		{Name: "gin", DisplayName: "Gin", Category: drinkee.CategorySpirit},
+		{Name: "lime juice", DisplayName: "Lime Juice", Category: drinkee.CategoryJuice},
+		{Name: "simple syrup", DisplayName: "Simple Syrup", Category: drinkee.CategorySyrup},
+		{Name: "lime", DisplayName: "Lime", Category: drinkee.CategoryProduce},
+		{Name: "soda water", DisplayName: "Soda Water", Category: drinkee.CategoryMixer},
+	}, nil
+}
+
+func (bar) FindDrinkByID(ctx context.Context, id int) (*drinkee.Drink, error) {
+	switch id {
+	case 1:
+		return &drinkee.Drink{ID: 1, DisplayName: "Gimlet", DrinkIngredients: drinkee.DrinkIngredientSlice{
+			{Name: "gin", Measurement: "60ml", Quantity: &drinkee.Quantity{Amount: 60, Unit: "ml", ML: ml(60)}},
+			{Name: "lime juice", Measurement: "20ml", Quantity: &drinkee.Quantity{Amount: 20, Unit: "ml", ML: ml(20)}},
+			{Name: "simple syrup", Measurement: "15ml", Quantity: &drinkee.Quantity{Amount: 15, Unit: "ml", ML: ml(15)}},
+			{Name: "lime", Measurement: "1 wheel", Quantity: &drinkee.Quantity{Amount: 1, Unit: "wheel"}},
+		}}, nil
+	case 2:
+		return &drinkee.Drink{ID: 2, DisplayName: "Gin Rickey", DrinkIngredients: drinkee.DrinkIngredientSlice{
+			{Name: "gin", Measurement: "45ml", Quantity: &drinkee.Quantity{Amount: 45, Unit: "ml", ML: ml(45)}},
+			{Name: "lime juice", Measurement: "15ml", Quantity: &drinkee.Quantity{Amount: 15, Unit: "ml", ML: ml(15)}},
+			{Name: "soda water", Measurement: "top up"},
+			{Name: "lime", Measurement: "1 wheel", Quantity: &drinkee.Quantity{Amount: 1, Unit: "wheel"}},
+		}}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func TestBuildShoppingList(t *testing.T) {
+	s := shopping.NewService(bar{})
+
+	list, err := s.BuildShoppingList(context.Background(), &drinkee.ShoppingListRequest{
+		Drinks: []drinkee.ShoppingListDrink{{ID: 1, Servings: 20}, {ID: 2, Servings: 10}},
+		Pantry: []drinkee.PantryItem{{Name: "Simple Syrup"}, {Name: "lime juice", Amount: "200ml"}},
+	})
+	assert.NoError(t, err)
+
+	var categories []string
+	for _, c := range list.Categories {
+		categories = append(categories, c.Category)
+	}
+	assert.Equal(t, []string{"spirit", "juice", "mixer", "produce"}, categories)
+	assert.Equal(t, []string{"simple syrup"}, list.Covered)
+
+	gin := list.Categories[0].Items[0]
+	assert.Equal(t, "1.65 l", gin.Needed)
+	assert.Equal(t, []drinkee.Bottle{{Size: 1000, Count: 1}, {Size: 700, Count: 1}}, gin.Bottles)
+	assert.Equal(t, "1 × 1000 ml + 1 × 700 ml", gin.Buy)
+	assert.Equal(t, []string{"Gimlet", "Gin Rickey"}, gin.Drinks)
+
+	juice := list.Categories[1].Items[0]
+	assert.Equal(t, "550 ml", juice.Needed)
+	assert.Equal(t, "350 ml", juice.Buy)
+	assert.Equal(t, 350.0, *juice.BuyML)
+
+	assert.Equal(t, "as needed", list.Categories[2].Items[0].Buy)
+	assert.Equal(t, "30 wheels", list.Categories[3].Items[0].Buy)
+}
+
+// aliases knows Tanqueray is gin and rose's could be either lime.
+type aliases map[string][]string
+
+func (a aliases) ResolveIngredients(ctx context.Context, names []string) (map[string]drinkee.Ingredient, error) {
+	byName := map[string]drinkee.Ingredient{}
+	for _, name := range names {
+		switch ingredients := a[name]; len(ingredients) {
+		case 0:
+		case 1:
+			byName[name] = drinkee.Ingredient{Name: ingredients[0]}
+		default:
+			return nil, &drinkee.AmbiguousIngredientError{Name: name, Ingredients: ingredients}
+		}
+	}
+	return byName, nil
+}
+
+func TestBuildShoppingListResolvesPantryAliases(t *testing.T) {
+	s := shopping.NewService(bar{})
+	s.Resolver = aliases{
+		"gin":       {"gin"},
+		"tanqueray": {"gin"},
+		"rose's":    {"lime", "lime juice"},
+	}
+
+	list, err := s.BuildShoppingList(context.Background(), &drinkee.ShoppingListRequest{
+		Drinks: []drinkee.ShoppingListDrink{{ID: 1, Servings: 20}, {ID: 2, Servings: 10}},
+		Pantry: []drinkee.PantryItem{{Name: "Tanqueray", Amount: "700ml"}, {Name: "gin", Amount: "1l"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gin"}, list.Covered)
+
+	_, err = s.BuildShoppingList(context.Background(), &drinkee.ShoppingListRequest{
+		Drinks: []drinkee.ShoppingListDrink{{ID: 1, Servings: 1}},
+		Pantry: []drinkee.PantryItem{{Name: "Rose's"}},
+	})
+	assert.ErrorIs(t, err, drinkee.ErrInvalidShoppingList)
+}
+
+func TestBuildShoppingListErrors(t *testing.T) {
+	s := shopping.NewService(bar{})
+
+	_, err := s.BuildShoppingList(context.Background(), &drinkee.ShoppingListRequest{
+		Drinks: []drinkee.ShoppingListDrink{{ID: 9, Servings: 1}},
+	})
+	assert.ErrorIs(t, err, drinkee.ErrInvalidShoppingList)
+
+	_, err = s.BuildShoppingList(context.Background(), &drinkee.ShoppingListRequest{
+		Drinks: []drinkee.ShoppingListDrink{{ID: 1, Servings: 1}},
+		Pantry: []drinkee.PantryItem{{Name: "gin", Amount: "plenty"}},
+	})
+	assert.ErrorIs(t, err, drinkee.ErrInvalidShoppingList)
+}
+
+func TestBottles(t *testing.T) {
+	sizes := shopping.DefaultBottleSizes
+	assert.Equal(t, []drinkee.Bottle{{Size: 700, Count: 1}}, shopping.Bottles(300, sizes))
+	assert.Equal(t, []drinkee.Bottle{{Size: 750, Count: 1}}, shopping.Bottles(720, sizes))
+	assert.Equal(t, []drinkee.Bottle{{Size: 750, Count: 2}}, shopping.Bottles(1500, sizes))
+	assert.Equal(t, []drinkee.Bottle{{Size: 1000, Count: 2}}, shopping.Bottles(2000, sizes))
+	assert.Nil(t, shopping.Bottles(0, sizes))
+
+	// a whole party's worth doesn't search every mix of the smaller bottles
+	assert.Equal(t, []drinkee.Bottle{{Size: 1000, Count: 996}, {Size: 750, Count: 2}, {Size: 700, Count: 4}}, shopping.Bottles(1000300, sizes))
+}
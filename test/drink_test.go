@@ -2,6 +2,7 @@ package integration_tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -231,3 +232,289 @@ func TestGetIngredients(t *testing.T) {
 // 	req, err := http.NewRequest("POST", "/drinks")
 
 // }
+
+// serve sends a request with a JSON body, if any, to a server of its own so
+// parallel tests don't swap each other's services.
+func serve(s *drinkeehttp.Server, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateDrinkRejectsUnknownIngredients(t *testing.T) {
+	t.Parallel()
+	db, p, resource := test_utils.SetupIntegrationTest(t, 2)
+	defer test_utils.TeardownIntegrationTest(p, resource)
+
+	s := drinkeehttp.NewServer()
+	s.DrinkService = postgres.NewDrinkService(db)
+
+	w := serve(s, "POST", "/api/v2/drinks", `{
+		"name": "typo", "displayName": "Typo", "description": "d", "instructions": "i",
+		"drinkIngredients": [
+			{"name": "test ingredient 1", "measurement": "1 oz"},
+			{"name": "tset ingredient 2", "measurement": "1 oz"}
+		]
+	}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown ingredient \"tset ingredient 2\"`)
+
+	var count int
+	assert.NoError(t, db.Get(&count, `SELECT count(*) FROM drinks WHERE name = 'typo'`))
+	assert.Equal(t, 0, count)
+}
+
+// v2Response is a v2 envelope with the data left to decode.
+type v2Response struct {
+	Data   json.RawMessage        `json:"data"`
+	Errors []drinkeehttp.APIError `json:"errors"`
+}
+
+func decodeV2(t *testing.T, w *httptest.ResponseRecorder, data interface{}) []drinkeehttp.APIError {
+	t.Helper()
+	var resp v2Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error unmarshalling response %s: %s", w.Body, err)
+	}
+	if data != nil && len(resp.Errors) == 0 {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			t.Fatalf("Error unmarshalling response data %s: %s", resp.Data, err)
+		}
+	}
+	return resp.Errors
+}
+
+func TestCreateDrinkConflicts(t *testing.T) {
+	t.Parallel()
+	db, p, resource := test_utils.SetupIntegrationTest(t, 2)
+	defer test_utils.TeardownIntegrationTest(p, resource)
+
+	s := drinkeehttp.NewServer()
+	s.DrinkService = postgres.NewDrinkService(db)
+
+	create := func(name, displayName string) *httptest.ResponseRecorder {
+		return serve(s, "POST", "/api/v2/drinks", fmt.Sprintf(`{
+			"name": %q, "displayName": %q, "description": "d", "instructions": "i",
+			"drinkIngredients": [{"name": "test ingredient 1", "measurement": "1 oz"}]
+		}`, name, displayName))
+	}
+
+	w := create("new drink", "New Drink")
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var drink drinkee.Drink
+	decodeV2(t, w, &drink)
+	assert.Equal(t, "new-drink", drink.Slug)
+
+	for _, tt := range []struct{ name, displayName string }{
+		{"TEST DRINK 1", "Something Else"},
+		{"something else", "Test Drink 2"},
+		{"new-drink", "NEW drink!"},
+	} {
+		w := create(tt.name, tt.displayName)
+		assert.Equal(t, http.StatusConflict, w.Code, tt.name)
+		errs := decodeV2(t, w, nil)
+		if assert.Len(t, errs, 1, tt.name) {
+			assert.Equal(t, "conflict", errs[0].Code, tt.name)
+		}
+	}
+
+	var count int
+	assert.NoError(t, db.Get(&count, `SELECT count(*) FROM drinks`))
+	assert.Equal(t, 3, count)
+}
+
+func TestIngredientAliasesResolve(t *testing.T) {
+	t.Parallel()
+	db, p, resource := test_utils.SetupIntegrationTest(t, 3)
+	defer test_utils.TeardownIntegrationTest(p, resource)
+
+	s := drinkeehttp.NewServer()
+	s.DrinkService = postgres.NewDrinkService(db)
+	s.IngredientAliasService = postgres.NewIngredientAliasService(db)
+
+	for _, alias := range []string{
+		`{"alias": "TI1", "ingredient": "test ingredient 1"}`,
+		`{"alias": "either", "ingredient": "test ingredient 2"}`,
+		`{"alias": "either", "ingredient": "test ingredient 3"}`,
+	} {
+		w := serve(s, "POST", "/api/v2/ingredients/aliases", alias)
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// the filter matches names ignoring case, then aliases
+	var drinks []drinkee.Drink
+	w := serve(s, "GET", "/api/v2/drinks?ingredients=ti1,TEST%20INGREDIENT%202", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decodeV2(t, w, &drinks)
+	var names []string
+	for _, d := range drinks {
+		names = append(names, d.Name)
+	}
+	assert.ElementsMatch(t, []string{"test drink 1", "test drink 2"}, names)
+
+	w = serve(s, "GET", "/api/v2/drinks?ingredients=either", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if errs := decodeV2(t, w, nil); assert.Len(t, errs, 1) {
+		assert.Equal(t, "ambiguous_ingredient", errs[0].Code)
+		assert.Contains(t, errs[0].Message, "test ingredient 2")
+		assert.Contains(t, errs[0].Message, "test ingredient 3")
+	}
+
+	// a filter name matching nothing matches no drinks
+	w = serve(s, "GET", "/api/v2/drinks?ingredients=nothing", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	drinks = nil
+	decodeV2(t, w, &drinks)
+	assert.Empty(t, drinks)
+
+	// drinks store the ingredient an alias stands for
+	w = serve(s, "POST", "/api/v2/drinks", `{
+		"name": "aliased", "displayName": "Aliased", "description": "d", "instructions": "i",
+		"drinkIngredients": [{"name": "ti1", "measurement": "1 oz"}]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var drink drinkee.Drink
+	decodeV2(t, w, &drink)
+	if assert.Len(t, drink.DrinkIngredients, 1) {
+		assert.Equal(t, "test ingredient 1", drink.DrinkIngredients[0].Name)
+	}
+
+	w = serve(s, "POST", "/api/v2/drinks/generate?strict=true", `{"ingredients": [{"name": "TI1"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	drinks = nil
+	decodeV2(t, w, &drinks)
+	names = nil
+	for _, d := range drinks {
+		names = append(names, d.Name)
+	}
+	assert.ElementsMatch(t, []string{"test drink 1", "aliased"}, names)
+}
+
+func TestRevertDrink(t *testing.T) {
+	t.Parallel()
+	db, p, resource := test_utils.SetupIntegrationTest(t, 2)
+	defer test_utils.TeardownIntegrationTest(p, resource)
+
+	drinkService := postgres.NewDrinkService(db)
+	s := drinkeehttp.NewServer()
+	s.DrinkService = drinkService
+	s.DrinkRevisionService = drinkService
+
+	w := serve(s, "POST", "/api/v2/drinks", `{
+		"name": "revert me", "displayName": "Revert Me", "description": "d", "instructions": "Stir.",
+		"drinkIngredients": [{"name": "test ingredient 1", "measurement": "2 oz"}]
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created drinkee.Drink
+	decodeV2(t, w, &created)
+
+	img := &drinkee.Image{URL: "/images/drinks/x/original.jpg", ContentType: "image/jpeg", Width: 10, Height: 10, Thumbnails: map[string]string{}}
+	assert.NoError(t, drinkService.SetDrinkImage(context.Background(), created.ID, img))
+
+	// changed behind the service's back, so not a revision
+	_, err := db.Exec(`UPDATE drinks SET instructions = 'Shake.' WHERE id = $1`, created.ID)
+	assert.NoError(t, err)
+
+	path := fmt.Sprintf("/api/v2/drinks/%d/revisions", created.ID)
+	req, _ := http.NewRequest("POST", path+"/1/revert", nil)
+	req.Header.Set(drinkeehttp.ActorHeader, "ben")
+	req.RemoteAddr = "203.0.113.7:51234"
+	w = httptest.NewRecorder()
+	s.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var reverted drinkee.Drink
+	decodeV2(t, w, &reverted)
+	assert.Equal(t, "Stir.", reverted.Instructions)
+	assert.Nil(t, reverted.Image)
+	assert.Equal(t, created.Slug, reverted.Slug)
+
+	var revisions []drinkee.DrinkRevision
+	w = serve(s, "GET", path, "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decodeV2(t, w, &revisions)
+	if assert.Len(t, revisions, 3) {
+		assert.Equal(t, []string{drinkee.RevisionReverted, drinkee.RevisionUpdated, drinkee.RevisionCreated},
+			[]string{revisions[0].Action, revisions[1].Action, revisions[2].Action})
+		assert.Equal(t, 1, revisions[0].RevertedFrom)
+		assert.Equal(t, "ben", revisions[0].Actor)
+		assert.Equal(t, "203.0.113.7", revisions[0].ClientIP)
+		assert.NotEmpty(t, revisions[0].RequestID)
+		assert.Equal(t, img, revisions[1].Drink.Image)
+	}
+
+	var diff drinkee.RevisionDiff
+	w = serve(s, "GET", path+"/diff?from=1&to=2", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decodeV2(t, w, &diff)
+	if assert.Len(t, diff.Fields, 1) {
+		assert.Equal(t, "image", diff.Fields[0].Field)
+	}
+	assert.Empty(t, diff.Ingredients)
+
+	// revisions can't be rewritten
+	_, err = db.Exec(`UPDATE drink_revisions SET actor = 'mallory'`)
+	assert.ErrorContains(t, err, "immutable")
+
+	w = serve(s, "POST", path+"/9/revert", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRefreshEstimatesOnlyWritesStaleRows(t *testing.T) {
+	t.Parallel()
+	db, p, resource := test_utils.SetupIntegrationTest(t, 3)
+	defer test_utils.TeardownIntegrationTest(p, resource)
+
+	ctx := context.Background()
+	drinkService := postgres.NewDrinkService(db)
+
+	_, err := db.Exec(`UPDATE ingredients SET abv = 40`)
+	assert.NoError(t, err)
+	_, err = db.Exec(`UPDATE drink_ingredients SET measurement = '2 oz'`)
+	assert.NoError(t, err)
+
+	n, err := drinkService.RefreshEstimates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = drinkService.RefreshEstimates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n, "nothing is stale")
+
+	events := func(drinkID int) int {
+		var count int
+		assert.NoError(t, db.Get(&count, `SELECT count(*) FROM catalog_events WHERE type = 'drink.updated' AND (data->>'id')::int = $1`, drinkID))
+		return count
+	}
+	before := []int{events(1), events(2), events(3)}
+
+	_, err = db.Exec(`UPDATE drink_ingredients SET measurement = '1 oz' WHERE drink_id = 2`)
+	assert.NoError(t, err)
+	before[1] = events(2)
+
+	n, err = drinkService.RefreshEstimates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	var amount float64
+	var unit string
+	assert.NoError(t, db.QueryRow(`SELECT amount, unit FROM drink_ingredients WHERE drink_id = 2`).Scan(&amount, &unit))
+	assert.Equal(t, 1.0, amount)
+	assert.Equal(t, "oz", unit)
+
+	drinks := map[int]*drinkee.Drink{}
+	for id := 1; id <= 3; id++ {
+		drinks[id], err = drinkService.FindDrinkByID(ctx, id)
+		assert.NoError(t, err)
+	}
+	if assert.NotNil(t, drinks[1].Stats) && assert.NotNil(t, drinks[2].Stats) {
+		assert.Less(t, drinks[2].Stats.Volume, drinks[1].Stats.Volume)
+	}
+	assert.Equal(t, drinks[1].Stats, drinks[3].Stats)
+
+	// only the drink that changed was written again
+	assert.Equal(t, []int{before[0], before[1] + 2, before[2]}, []int{events(1), events(2), events(3)})
+}
@@ -0,0 +1,48 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/match"
+	"github.com/dylanconnolly/drinkee/postgres"
+	test_utils "github.com/dylanconnolly/drinkee/test/utils"
+)
+
+// BenchmarkGenerateDrinks compares generating against postgres alone with
+// generating through the in-memory matcher, which only loads matched drinks.
+func BenchmarkGenerateDrinks(b *testing.B) {
+	db, p, resource := test_utils.SetupIntegrationTest(b, 2000)
+	defer test_utils.TeardownIntegrationTest(p, resource)
+
+	ctx := context.Background()
+	var pantry []drinkee.Ingredient
+	for id := 1; id <= 2000; id += 100 {
+		pantry = append(pantry, drinkee.Ingredient{ID: id})
+	}
+
+	sqlService := postgres.NewDrinkService(db)
+	memoryService := postgres.NewDrinkService(db)
+	memoryService.Matcher = match.NewIndex()
+	if err := memoryService.LoadMatcher(ctx); err != nil {
+		b.Fatal(err)
+	}
+
+	for name, s := range map[string]*postgres.DrinkService{"sql": sqlService, "memory": memoryService} {
+		b.Run("strict/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.GenerateDrinks(ctx, pantry); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("nonstrict/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.GenerateNonStrictDrinks(ctx, pantry, drinkee.GenerateOptions{Limit: 100}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -17,7 +17,7 @@ import (
 	"github.com/ory/dockertest/docker"
 )
 
-func SetupIntegrationTest(t *testing.T, mockDataCount int) (*sqlx.DB, *dockertest.Pool, *dockertest.Resource) {
+func SetupIntegrationTest(t testing.TB, mockDataCount int) (*sqlx.DB, *dockertest.Pool, *dockertest.Resource) {
 	db, pool, resource := setupDatabase(t)
 
 	if err := seedDatabase(db, mockDataCount); err != nil {
@@ -31,7 +31,7 @@ func TeardownIntegrationTest(p *dockertest.Pool, r *dockertest.Resource) {
 	p.Purge(r)
 }
 
-func setupDatabase(t *testing.T) (*sqlx.DB, *dockertest.Pool, *dockertest.Resource) {
+func setupDatabase(t testing.TB) (*sqlx.DB, *dockertest.Pool, *dockertest.Resource) {
 	var db *sqlx.DB
 	t.Helper()
 
@@ -0,0 +1,232 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/metrics"
+)
+
+const (
+	DefaultOTLPEndpoint = "http://localhost:4318"
+
+	otlpBatchSize     = 256
+	otlpQueueSize     = 2048
+	otlpFlushInterval = 5 * time.Second
+)
+
+// OTLPExporter batches spans and posts them to an OTLP/HTTP collector using the
+// JSON encoding (POST <endpoint>/v1/traces). Jaeger, Tempo and the
+// OpenTelemetry collector all accept it.
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+
+	// queue is never closed, spans can still end after Shutdown; closing stop
+	// tells run to flush and return instead
+	queue chan *Span
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func NewOTLPExporter(endpoint, serviceName string) *OTLPExporter {
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+
+	e := &OTLPExporter{
+		url:         strings.TrimRight(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan *Span, otlpQueueSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go e.run()
+
+	return e
+}
+
+// ExportSpan queues s, dropping it when the queue is full so a slow collector
+// never blocks request handling. Spans ended after Shutdown are dropped.
+// Outcomes are counted in metrics.TraceSpans.
+func (e *OTLPExporter) ExportSpan(s *Span) {
+	select {
+	case <-e.stop:
+		metrics.TraceSpans.WithLabelValues("dropped").Inc()
+		return
+	default:
+	}
+
+	select {
+	case e.queue <- s:
+	default:
+		metrics.TraceSpans.WithLabelValues("dropped").Inc()
+	}
+}
+
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.once.Do(func() { close(e.stop) })
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, otlpBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		outcome := "exported"
+		if err := e.send(batch); err != nil {
+			outcome = "failed"
+		}
+		metrics.TraceSpans.WithLabelValues(outcome).Add(float64(len(batch)))
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			// send whatever was queued before shutdown
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+					if len(batch) >= otlpBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) send(spans []*Span) error {
+	body, err := json.Marshal(e.payload(spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+func (e *OTLPExporter) payload(spans []*Span) map[string]interface{} {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		sp := otlpSpan{
+			TraceID:           s.SpanContext.TraceID.String(),
+			SpanID:            s.SpanContext.SpanID.String(),
+			Name:              s.Name,
+			Kind:              int(s.Kind),
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+		}
+		if s.ParentID.IsValid() {
+			sp.ParentSpanID = s.ParentID.String()
+		}
+		for _, a := range s.Attributes {
+			sp.Attributes = append(sp.Attributes, otlpAttribute(a))
+		}
+		// status codes: 1 ok, 2 error
+		sp.Status.Code = 1
+		if s.Err != "" {
+			sp.Status.Code = 2
+			sp.Status.Message = s.Err
+		}
+		out = append(out, sp)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpKeyValue{otlpAttribute(String("service.name", e.serviceName))},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "github.com/dylanconnolly/drinkee/trace"},
+						"spans": out,
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttribute(a Attribute) otlpKeyValue {
+	kv := otlpKeyValue{Key: a.Key}
+	switch v := a.Value.(type) {
+	case string:
+		kv.Value = map[string]interface{}{"stringValue": v}
+	case bool:
+		kv.Value = map[string]interface{}{"boolValue": v}
+	case int:
+		kv.Value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+	case int64:
+		kv.Value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		kv.Value = map[string]interface{}{"doubleValue": v}
+	default:
+		kv.Value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+	return kv
+}
@@ -0,0 +1,63 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// StdoutExporter writes each finished span as a JSON line.
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+type stdoutSpan struct {
+	TraceID    string                 `json:"traceId"`
+	SpanID     string                 `json:"spanId"`
+	ParentID   string                 `json:"parentSpanId,omitempty"`
+	Name       string                 `json:"name"`
+	Start      time.Time              `json:"start"`
+	DurationMs float64                `json:"durationMs"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+func (e *StdoutExporter) ExportSpan(s *Span) {
+	out := stdoutSpan{
+		TraceID:    s.SpanContext.TraceID.String(),
+		SpanID:     s.SpanContext.SpanID.String(),
+		Name:       s.Name,
+		Start:      s.StartTime,
+		DurationMs: float64(s.EndTime.Sub(s.StartTime).Microseconds()) / 1000,
+		Error:      s.Err,
+	}
+	if s.ParentID.IsValid() {
+		out.ParentID = s.ParentID.String()
+	}
+	if len(s.Attributes) > 0 {
+		out.Attributes = make(map[string]interface{}, len(s.Attributes))
+		for _, a := range s.Attributes {
+			out.Attributes[a.Key] = a.Value
+		}
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(append(b, '\n'))
+}
+
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package trace is a small span recorder modelled on OpenTelemetry. It
+// propagates W3C traceparent headers and hands finished spans to an Exporter
+// (stdout or an OTLP/HTTP collector).
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type TraceID [16]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+type SpanID [8]byte
+
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota + 1
+	SpanKindServer
+	SpanKindClient
+)
+
+// SpanContext is the part of a span that crosses process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats sc as a W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses a W3C traceparent header value.
+func ParseTraceparent(h string) (SpanContext, bool) {
+	var sc SpanContext
+
+	parts := strings.Split(strings.TrimSpace(h), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return sc, false
+	}
+	// version 00 has exactly four fields, later versions may append more
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&0x01 == 1
+
+	return sc, sc.IsValid()
+}
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: value} }
+
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+type Span struct {
+	mu sync.Mutex
+
+	Name        string
+	Kind        SpanKind
+	SpanContext SpanContext
+	ParentID    SpanID
+	StartTime   time.Time
+	EndTime     time.Time
+	Attributes  []Attribute
+	// Err is the message of the last recorded error; a non-empty value marks the span failed.
+	Err string
+
+	ended bool
+}
+
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes = append(s.Attributes, attrs...)
+}
+
+// RecordError marks the span failed. A nil error is ignored so callers can
+// pass their return value unconditionally.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err.Error()
+}
+
+// End finishes the span and queues it for export. Calling End twice is a no-op.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+
+	if s.SpanContext.Sampled {
+		if e := loadExporter(); e != nil {
+			e.ExportSpan(s)
+		}
+	}
+}
+
+type Exporter interface {
+	ExportSpan(s *Span)
+	Shutdown(ctx context.Context) error
+}
+
+type exporterHolder struct{ Exporter }
+
+var exporter atomic.Value
+
+// SetExporter installs e for all spans ended from now on. With no exporter
+// spans are still created so IDs propagate, but are dropped when ended.
+func SetExporter(e Exporter) {
+	exporter.Store(exporterHolder{e})
+}
+
+func loadExporter() Exporter {
+	h, _ := exporter.Load().(exporterHolder)
+	return h.Exporter
+}
+
+// Shutdown flushes and stops the installed exporter.
+func Shutdown(ctx context.Context) error {
+	if e := loadExporter(); e != nil {
+		return e.Shutdown(ctx)
+	}
+	return nil
+}
+
+type contextKey int
+
+const spanContextKey = contextKey(0)
+
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey, s)
+}
+
+// SpanFromContext returns the active span, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanContextKey).(*Span)
+	return s
+}
+
+// Start begins an internal span as a child of the span in ctx, or a new trace
+// when there is none.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	var parent SpanContext
+	if p := SpanFromContext(ctx); p != nil {
+		parent = p.SpanContext
+	}
+	return start(ctx, name, SpanKindInternal, parent, attrs)
+}
+
+// StartServer begins the root span of an incoming request. parent is the
+// remote caller's span context and may be invalid.
+func StartServer(ctx context.Context, name string, parent SpanContext, attrs ...Attribute) (context.Context, *Span) {
+	return start(ctx, name, SpanKindServer, parent, attrs)
+}
+
+func start(ctx context.Context, name string, kind SpanKind, parent SpanContext, attrs []Attribute) (context.Context, *Span) {
+	s := &Span{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: attrs,
+	}
+
+	if parent.IsValid() {
+		s.SpanContext.TraceID = parent.TraceID
+		s.SpanContext.Sampled = parent.Sampled
+		s.ParentID = parent.SpanID
+	} else {
+		rand.Read(s.SpanContext.TraceID[:])
+		s.SpanContext.Sampled = true
+	}
+	rand.Read(s.SpanContext.SpanID[:])
+
+	return ContextWithSpan(ctx, s), s
+}
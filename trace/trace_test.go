@@ -0,0 +1,154 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dylanconnolly/drinkee/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	for _, h := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-xyz92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		_, ok := ParseTraceparent(h)
+		assert.False(t, ok, h)
+	}
+}
+
+func TestChildSpansShareTraceAndExport(t *testing.T) {
+	var buf bytes.Buffer
+	SetExporter(NewStdoutExporter(&buf))
+	defer SetExporter(nil)
+
+	parent, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, root := StartServer(context.Background(), "GET /drinks", parent)
+	_, child := Start(ctx, "sql findDrinks", String("db.operation", "findDrinks"))
+	child.End()
+	root.End()
+
+	assert.Equal(t, parent.TraceID, child.SpanContext.TraceID)
+	assert.Equal(t, root.SpanContext.SpanID, child.ParentID)
+	assert.Equal(t, parent.SpanID, root.ParentID)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var exported stdoutSpan
+	assert.NoError(t, json.Unmarshal(lines[0], &exported))
+	assert.Equal(t, "sql findDrinks", exported.Name)
+	assert.Equal(t, "findDrinks", exported.Attributes["db.operation"])
+}
+
+func TestOTLPExportAfterShutdown(t *testing.T) {
+	var got int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&got, 1)
+	}))
+	defer collector.Close()
+
+	e := NewOTLPExporter(collector.URL, "drinkee")
+	_, span := StartServer(context.Background(), "GET /drinks", SpanContext{})
+	span.End()
+	e.ExportSpan(span)
+
+	assert.NoError(t, e.Shutdown(context.Background()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&got), "queued spans are flushed")
+
+	assert.NotPanics(t, func() { e.ExportSpan(span) })
+	assert.NoError(t, e.Shutdown(context.Background()))
+}
+
+func TestOTLPWireFormat(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan map[string]interface{}, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests <- r
+		bodies <- body
+	}))
+	defer collector.Close()
+
+	e := NewOTLPExporter(collector.URL+"/", "drinkee")
+	parent, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, root := StartServer(context.Background(), "GET /drinks", parent)
+	_, span := Start(ctx, "sql findDrinks", String("db.operation", "findDrinks"), Int("rows", 3), Bool("cached", false))
+	span.RecordError(errors.New("boom"))
+	span.End()
+	e.ExportSpan(span)
+	assert.NoError(t, e.Shutdown(context.Background()))
+
+	r := <-requests
+	assert.Equal(t, "POST", r.Method)
+	assert.Equal(t, "/v1/traces", r.URL.Path)
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+	resourceSpans := (<-bodies)["resourceSpans"].([]interface{})
+	assert.Len(t, resourceSpans, 1)
+	rs := resourceSpans[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"key":   "service.name",
+		"value": map[string]interface{}{"stringValue": "drinkee"},
+	}}, rs["resource"].(map[string]interface{})["attributes"])
+
+	scope := rs["scopeSpans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "github.com/dylanconnolly/drinkee/trace", scope["scope"].(map[string]interface{})["name"])
+	spans := scope["spans"].([]interface{})
+	assert.Len(t, spans, 1)
+
+	got := spans[0].(map[string]interface{})
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got["traceId"])
+	assert.Equal(t, span.SpanContext.SpanID.String(), got["spanId"])
+	assert.Equal(t, root.SpanContext.SpanID.String(), got["parentSpanId"])
+	assert.Equal(t, "sql findDrinks", got["name"])
+	assert.Equal(t, 1.0, got["kind"], "internal")
+	// 64-bit nanosecond timestamps are strings in OTLP/JSON
+	assert.Equal(t, strconv.FormatInt(span.StartTime.UnixNano(), 10), got["startTimeUnixNano"])
+	assert.Equal(t, strconv.FormatInt(span.EndTime.UnixNano(), 10), got["endTimeUnixNano"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "db.operation", "value": map[string]interface{}{"stringValue": "findDrinks"}},
+		map[string]interface{}{"key": "rows", "value": map[string]interface{}{"intValue": "3"}},
+		map[string]interface{}{"key": "cached", "value": map[string]interface{}{"boolValue": false}},
+	}, got["attributes"])
+	assert.Equal(t, map[string]interface{}{"code": 2.0, "message": "boom"}, got["status"])
+}
+
+func TestOTLPCountsFailedExports(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+	failed := metrics.TraceSpans.WithLabelValues("failed")
+	before := testutil.ToFloat64(failed)
+
+	e := NewOTLPExporter(collector.URL, "drinkee")
+	for i := 0; i < 2; i++ {
+		_, span := StartServer(context.Background(), "GET /drinks", SpanContext{})
+		span.End()
+		e.ExportSpan(span)
+	}
+	assert.NoError(t, e.Shutdown(context.Background()))
+
+	assert.Equal(t, before+2, testutil.ToFloat64(failed))
+}
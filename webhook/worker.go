@@ -0,0 +1,231 @@
+// Package webhook delivers catalog events to subscribed URLs. Deliveries are
+// queued in postgres by the database itself; the Worker claims due deliveries,
+// POSTs them signed with the subscription's secret and records every attempt.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/logger"
+	"github.com/dylanconnolly/drinkee/metrics"
+)
+
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 20
+	DefaultMaxAttempts  = 8
+	DefaultBackoff      = 30 * time.Second
+	DefaultMaxBackoff   = time.Hour
+	DefaultTimeout      = 10 * time.Second
+)
+
+// Queue is the delivery storage the worker drains, implemented by
+// postgres.WebhookService.
+type Queue interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*drinkee.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, a *drinkee.WebhookAttempt, status string, retryIn time.Duration) error
+}
+
+type Worker struct {
+	Queue Queue
+	// HTTPClient sends deliveries. The default from NewHTTPClient refuses to
+	// connect to private addresses.
+	HTTPClient *http.Client
+	Logger     logger.Logger
+
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is how many times a delivery is tried before it's marked failed.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled after each attempt
+	// up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds each POST.
+	Timeout time.Duration
+
+	now    func() time.Time
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWorker(q Queue) *Worker {
+	return &Worker{
+		Queue:        q,
+		HTTPClient:   NewHTTPClient(),
+		Logger:       logger.NewNop(),
+		PollInterval: DefaultPollInterval,
+		BatchSize:    DefaultBatchSize,
+		MaxAttempts:  DefaultMaxAttempts,
+		Backoff:      DefaultBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		Timeout:      DefaultTimeout,
+		now:          time.Now,
+	}
+}
+
+// Open starts polling for due deliveries in the background.
+func (w *Worker) Open() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			// keep draining while full batches come back
+			for {
+				n, err := w.RunOnce(ctx)
+				if err != nil {
+					w.Logger.Error("error delivering webhooks", logger.Err(err))
+				}
+				if err != nil || n < w.BatchSize {
+					break
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops polling and waits for in-flight deliveries to be recorded.
+func (w *Worker) Close(ctx context.Context) error {
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce claims one batch of due deliveries and sends them concurrently,
+// returning how many were claimed.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+
+	// the lease outlives the POST so a slow receiver isn't sent the delivery twice
+	deliveries, err := w.Queue.ClaimWebhookDeliveries(ctx, w.BatchSize, 2*w.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func(d *drinkee.WebhookDelivery) {
+			defer wg.Done()
+			w.deliver(d)
+		}(d)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// deliver sends d once and records the outcome. It runs to completion even
+// when the worker is closing so a sent delivery is never left unrecorded.
+func (w *Worker) deliver(d *drinkee.WebhookDelivery) {
+	l := w.Logger.With(logger.F("deliveryId", d.ID), logger.F("eventType", d.Event.Type), logger.F("url", d.URL))
+
+	start := w.now()
+	statusCode, err := w.post(d)
+	attempt := &drinkee.WebhookAttempt{
+		DeliveryID: d.ID,
+		StatusCode: statusCode,
+		DurationMs: int(w.now().Sub(start).Milliseconds()),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	status, retryIn := drinkee.DeliverySucceeded, time.Duration(0)
+	switch attempts := d.Attempts + 1; {
+	case err == nil:
+		metrics.WebhookAttempts.WithLabelValues("succeeded").Inc()
+		l.Debug("delivered webhook", logger.F("attempt", attempts))
+	case attempts >= w.MaxAttempts:
+		status = drinkee.DeliveryFailed
+		metrics.WebhookAttempts.WithLabelValues("failed").Inc()
+		l.Warn("giving up on webhook delivery", logger.F("attempt", attempts), logger.Err(err))
+	default:
+		status, retryIn = drinkee.DeliveryPending, w.backoff(attempts)
+		metrics.WebhookAttempts.WithLabelValues("retrying").Inc()
+		l.Info("webhook delivery failed, retrying", logger.F("attempt", attempts), logger.F("retryIn", retryIn.String()), logger.Err(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.Timeout)
+	defer cancel()
+	if err := w.Queue.RecordWebhookAttempt(ctx, attempt, status, retryIn); err != nil {
+		l.Error("error recording webhook attempt", logger.Err(err))
+	}
+}
+
+// post sends the event to the subscription URL. Any response other than 2xx
+// is an error.
+func (w *Worker) post(d *drinkee.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	ts := w.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "drinkee-webhooks")
+	req.Header.Set(EventHeader, d.Event.Type)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(SignatureHeader, Sign(d.Secret, ts, body))
+
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (w *Worker) backoff(attempts int) time.Duration {
+	d := w.Backoff << (attempts - 1)
+	if d <= 0 || d > w.MaxBackoff {
+		d = w.MaxBackoff
+	}
+	return d
+}
@@ -0,0 +1,161 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dylanconnolly/drinkee/drinkee"
+	"github.com/dylanconnolly/drinkee/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedAttempt struct {
+	attempt drinkee.WebhookAttempt
+	status  string
+	retryIn time.Duration
+}
+
+type memQueue struct {
+	mu       sync.Mutex
+	due      []*drinkee.WebhookDelivery
+	attempts []recordedAttempt
+}
+
+func (q *memQueue) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*drinkee.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	claimed := q.due
+	q.due = nil
+	return claimed, nil
+}
+
+func (q *memQueue) RecordWebhookAttempt(ctx context.Context, a *drinkee.WebhookAttempt, status string, retryIn time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.attempts = append(q.attempts, recordedAttempt{*a, status, retryIn})
+	return nil
+}
+
+func delivery(url string, attempts int) *drinkee.WebhookDelivery {
+	return &drinkee.WebhookDelivery{
+		ID:       7,
+		URL:      url,
+		Secret:   "shh",
+		Attempts: attempts,
+		Event: drinkee.Event{
+			ID:   3,
+			Type: drinkee.EventDrinkCreated,
+			Data: json.RawMessage(`{"id": 12, "name": "negroni", "displayName": "Negroni"}`),
+		},
+	}
+}
+
+// newWorker delivers to ts, which listens on loopback and so would be refused
+// by the default client.
+func newWorker(q webhook.Queue, ts *httptest.Server) *webhook.Worker {
+	w := webhook.NewWorker(q)
+	w.HTTPClient = ts.Client()
+	return w
+}
+
+func TestWorkerSignsDeliveries(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	q := &memQueue{due: []*drinkee.WebhookDelivery{delivery(ts.URL, 0)}}
+	n, err := newWorker(q, ts).RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "drink.created", got.Header.Get(webhook.EventHeader))
+	assert.Equal(t, "7", got.Header.Get(webhook.DeliveryHeader))
+	assert.NoError(t, webhook.Verify("shh", got.Header.Get(webhook.SignatureHeader), got.Header.Get(webhook.TimestampHeader), body, time.Minute))
+	assert.ErrorIs(t, webhook.Verify("wrong", got.Header.Get(webhook.SignatureHeader), got.Header.Get(webhook.TimestampHeader), body, time.Minute), webhook.ErrInvalidSignature)
+
+	var event drinkee.Event
+	assert.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, int64(3), event.ID)
+	assert.JSONEq(t, `{"id": 12, "name": "negroni", "displayName": "Negroni"}`, string(event.Data))
+
+	assert.Len(t, q.attempts, 1)
+	assert.Equal(t, drinkee.DeliverySucceeded, q.attempts[0].status)
+	assert.Equal(t, http.StatusOK, q.attempts[0].attempt.StatusCode)
+}
+
+func TestWorkerRetriesWithBackoffThenGivesUp(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	q := &memQueue{}
+	w := newWorker(q, ts)
+	w.Backoff = time.Second
+	w.MaxBackoff = 3 * time.Second
+	w.MaxAttempts = 4
+
+	for attempts := 0; attempts < 4; attempts++ {
+		q.due = []*drinkee.WebhookDelivery{delivery(ts.URL, attempts)}
+		_, err := w.RunOnce(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 0}, []time.Duration{
+		q.attempts[0].retryIn, q.attempts[1].retryIn, q.attempts[2].retryIn, q.attempts[3].retryIn,
+	})
+	assert.Equal(t, drinkee.DeliveryPending, q.attempts[2].status)
+	assert.Equal(t, drinkee.DeliveryFailed, q.attempts[3].status)
+	assert.Equal(t, http.StatusBadGateway, q.attempts[3].attempt.StatusCode)
+	assert.Equal(t, "unexpected status 502", q.attempts[3].attempt.Error)
+}
+
+func TestWorkerRecordsUnreachableReceivers(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+
+	q := &memQueue{due: []*drinkee.WebhookDelivery{delivery(ts.URL, 0)}}
+	newWorker(q, ts).RunOnce(context.Background())
+
+	assert.Equal(t, 0, q.attempts[0].attempt.StatusCode)
+	assert.NotEmpty(t, q.attempts[0].attempt.Error)
+	assert.Equal(t, drinkee.DeliveryPending, q.attempts[0].status)
+}
+
+func TestWorkerRefusesPrivateAddresses(t *testing.T) {
+	var hit bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	for _, url := range []string{
+		ts.URL,
+		"http://localhost:" + port,
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.1:8080/hook",
+		"http://[::1]:" + port,
+		"http://100.64.0.1/hook",
+	} {
+		q := &memQueue{due: []*drinkee.WebhookDelivery{delivery(url, 0)}}
+		webhook.NewWorker(q).RunOnce(context.Background())
+
+		assert.Equal(t, 0, q.attempts[0].attempt.StatusCode, url)
+		assert.Contains(t, q.attempts[0].attempt.Error, webhook.ErrPrivateAddress.Error(), url)
+	}
+	assert.False(t, hit)
+}